@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
 	"prb_care_api/internal/config"
+	"strconv"
+	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+
 	viperConfig := config.NewViper()
-	app := config.NewFiber()
+	app := config.NewFiber(viperConfig)
 	db := config.NewDatabase(viperConfig)
 	validator := config.NewValidator()
 	mold := config.NewMold()
@@ -25,3 +35,60 @@ func main() {
 		log.Fatalln(err)
 	}
 }
+
+// prb_care_api migrate up | down [jumlah] | status
+func migrate(args []string) {
+	if len(args) == 0 {
+		log.Fatalln("penggunaan: prb_care_api migrate up | down [jumlah] | status")
+	}
+
+	ctx := context.Background()
+	db := config.OpenDatabase(config.NewViper())
+
+	switch args[0] {
+	case "up":
+		migrations, err := config.MigrateUp(ctx, db)
+		for _, m := range migrations {
+			fmt.Printf("diterapkan %06d_%s\n", m.Versi, m.Nama)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(migrations) == 0 {
+			fmt.Println("skema database sudah terbaru")
+		}
+	case "down":
+		langkah := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalln("jumlah migrasi yang dibatalkan harus bilangan bulat positif")
+			}
+			langkah = n
+		}
+		migrations, err := config.MigrateDown(ctx, db, langkah)
+		for _, m := range migrations {
+			fmt.Printf("dibatalkan %06d_%s\n", m.Versi, m.Nama)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(migrations) == 0 {
+			fmt.Println("tidak ada migrasi yang dibatalkan")
+		}
+	case "status":
+		status, err := config.MigrateStatus(ctx, db)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, s := range status {
+			if s.Diterapkan {
+				fmt.Printf("%06d_%s\tditerapkan %s\n", s.Versi, s.Nama, time.Unix(s.Waktu, 0).Format(time.RFC3339))
+			} else {
+				fmt.Printf("%06d_%s\ttertunda\n", s.Versi, s.Nama)
+			}
+		}
+	default:
+		log.Fatalf("perintah migrate tidak dikenal: %s\n", args[0])
+	}
+}
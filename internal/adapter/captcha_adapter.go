@@ -1,24 +1,45 @@
 package adapter
 
 import (
+	"fmt"
 	"github.com/gofiber/fiber/v3/client"
+	"github.com/spf13/viper"
+	"log"
 	"log/slog"
+	"prb_care_api/internal/constant"
 	"prb_care_api/internal/model"
 )
 
-type Captcha struct {
+type CaptchaVerifier interface {
+	Verify(request *model.CaptchaRequest) (bool, error)
+}
+
+// turnstile, hcaptcha dan recaptcha memakai bentuk siteverify yang sama
+type SiteverifyCaptcha struct {
 	Client *client.Client
+	Url    string
+}
+
+func NewTurnstileCaptcha(client *client.Client) *SiteverifyCaptcha {
+	return &SiteverifyCaptcha{client, "https://challenges.cloudflare.com/turnstile/v0/siteverify"}
+}
+
+func NewHCaptchaCaptcha(client *client.Client) *SiteverifyCaptcha {
+	return &SiteverifyCaptcha{client, "https://api.hcaptcha.com/siteverify"}
 }
 
-func NewCaptcha(client *client.Client) *Captcha {
-	return &Captcha{Client: client}
+func NewRecaptchaCaptcha(client *client.Client) *SiteverifyCaptcha {
+	return &SiteverifyCaptcha{client, "https://www.google.com/recaptcha/api/siteverify"}
 }
 
-func (r *Captcha) Verify(request *model.CaptchaRequest) (bool, error) {
+func (r *SiteverifyCaptcha) Verify(request *model.CaptchaRequest) (bool, error) {
 	resp, err := r.Client.Post(
-		"https://challenges.cloudflare.com/turnstile/v0/siteverify",
+		r.Url,
 		client.Config{
-			Body: request,
+			FormData: map[string]string{
+				"secret":   request.Secret,
+				"response": request.TokenCaptcha,
+			},
 		},
 	)
 
@@ -37,3 +58,71 @@ func (r *Captcha) Verify(request *model.CaptchaRequest) (bool, error) {
 	}
 	return response.Success, nil
 }
+
+// untuk development dan pengujian tanpa jaringan, hasilnya selalu sama untuk token apa pun
+type StubCaptcha struct {
+	Lolos bool
+}
+
+func (r *StubCaptcha) Verify(request *model.CaptchaRequest) (bool, error) {
+	return r.Lolos, nil
+}
+
+type captchaRole struct {
+	Verifier CaptchaVerifier
+	Secret   string
+}
+
+// provider dan secret bisa diatur per role lewat captcha.<role>.provider dan captcha.<role>.secret,
+// bila kosong memakai captcha.provider dan captcha.secret
+type Captcha struct {
+	Role map[string]*captchaRole
+}
+
+func NewCaptcha(client *client.Client, config *viper.Viper) *Captcha {
+	captcha := &Captcha{Role: map[string]*captchaRole{}}
+	for _, role := range []string{constant.RoleAdminSuper, constant.RoleAdminPuskesmas, constant.RoleAdminApotek, constant.RolePengguna} {
+		provider := config.GetString("captcha." + role + ".provider")
+		if provider == "" {
+			provider = config.GetString("captcha.provider")
+		}
+		secret := config.GetString("captcha." + role + ".secret")
+		if secret == "" {
+			secret = config.GetString("captcha.secret")
+		}
+
+		verifier, err := newCaptchaVerifier(client, provider)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if _, ok := verifier.(*StubCaptcha); ok {
+			slog.Warn("Captcha stub is active", "role", role, "provider", provider)
+		}
+		captcha.Role[role] = &captchaRole{verifier, secret}
+	}
+	return captcha
+}
+
+func newCaptchaVerifier(client *client.Client, provider string) (CaptchaVerifier, error) {
+	switch provider {
+	case "", constant.CaptchaTurnstile:
+		return NewTurnstileCaptcha(client), nil
+	case constant.CaptchaHCaptcha:
+		return NewHCaptchaCaptcha(client), nil
+	case constant.CaptchaRecaptcha:
+		return NewRecaptchaCaptcha(client), nil
+	case constant.CaptchaSelaluLolos:
+		return &StubCaptcha{Lolos: true}, nil
+	case constant.CaptchaSelaluGagal:
+		return &StubCaptcha{Lolos: false}, nil
+	}
+	return nil, fmt.Errorf("provider captcha %q tidak dikenal", provider)
+}
+
+func (r *Captcha) Verify(role string, tokenCaptcha string) (bool, error) {
+	captchaRole, ok := r.Role[role]
+	if !ok {
+		return false, fmt.Errorf("captcha untuk role %q tidak dikonfigurasi", role)
+	}
+	return captchaRole.Verifier.Verify(&model.CaptchaRequest{Secret: captchaRole.Secret, TokenCaptcha: tokenCaptcha})
+}
@@ -0,0 +1,96 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v3/client"
+	"github.com/spf13/viper"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"sync"
+)
+
+// id dipakai konsumen untuk membuang event ganda, karena event yang gagal di salah satu sink dikirim ulang ke semua sink
+type Event struct {
+	ID        string          `json:"id"`
+	Jenis     string          `json:"jenis"`
+	Agregat   string          `json:"agregat"`
+	IdAgregat int32           `json:"idAgregat"`
+	Data      json.RawMessage `json:"data"`
+	Waktu     int64           `json:"waktu"`
+}
+
+type EventSink interface {
+	Nama() string
+	Kirim(event *Event) error
+}
+
+type EventHandler func(event *Event) error
+
+// meneruskan event ke handler di dalam proses yang didaftarkan per jenis event
+type HandlerSink struct {
+	mu      sync.RWMutex
+	handler map[string][]EventHandler
+}
+
+func NewHandlerSink() *HandlerSink {
+	return &HandlerSink{handler: make(map[string][]EventHandler)}
+}
+
+func (s *HandlerSink) Daftar(jenis string, handler EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler[jenis] = append(s.handler[jenis], handler)
+}
+
+func (s *HandlerSink) Nama() string {
+	return "handler"
+}
+
+func (s *HandlerSink) Kirim(event *Event) error {
+	s.mu.RLock()
+	handler := append(append([]EventHandler{}, s.handler[event.Jenis]...), s.handler[constant.EventSemua]...)
+	s.mu.RUnlock()
+	for _, h := range handler {
+		if err := h(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mengirim event sebagai json ke url tetap, id event juga dikirim di header X-Event-Id
+type WebhookSink struct {
+	Client *client.Client
+	Url    string
+}
+
+func (s *WebhookSink) Nama() string {
+	return "webhook " + s.Url
+}
+
+func (s *WebhookSink) Kirim(event *Event) error {
+	resp, err := s.Client.Post(s.Url, client.Config{
+		Header: map[string]string{"X-Event-Id": event.ID},
+		Body:   event,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return fmt.Errorf("webhook %s membalas status %d", s.Url, resp.StatusCode())
+	}
+	return nil
+}
+
+// sink webhook dibuat dari daftar url pada event.webhook
+func NewEventSinks(client *client.Client, config *viper.Viper, handler *HandlerSink) []EventSink {
+	sinks := []EventSink{handler}
+	for _, url := range config.GetStringSlice("event.webhook") {
+		sinks = append(sinks, &WebhookSink{Client: client, Url: url})
+	}
+	if len(sinks) == 1 {
+		slog.Warn("No static event webhook configured")
+	}
+	return sinks
+}
@@ -0,0 +1,177 @@
+package adapter
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v3/client"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"log"
+	"log/slog"
+	"os"
+	"prb_care_api/internal/constant"
+	"sync"
+	"time"
+)
+
+// token sudah tidak terdaftar di perangkat mana pun, percobaan ulang tidak ada gunanya
+var ErrTokenPerangkatTidakValid = errors.New("token perangkat tidak valid")
+
+type Notifier interface {
+	Send(tokenPerangkat string, judul string, isi string, data map[string]string) error
+}
+
+// untuk development, notifikasi hanya ditulis ke log dan tidak benar-benar dikirim
+type LogNotifier struct{}
+
+func (n *LogNotifier) Send(tokenPerangkat string, judul string, isi string, data map[string]string) error {
+	slog.Info("Notification sent", "tokenPerangkat", tokenPerangkat, "judul", judul, "isi", isi, "data", data)
+	return nil
+}
+
+// firebase cloud messaging http v1, access token oauth diminta memakai service account dan di-cache sampai hampir habis
+type FcmNotifier struct {
+	Client      *client.Client
+	ProjectId   string
+	ClientEmail string
+	PrivateKey  *rsa.PrivateKey
+	TokenUri    string
+
+	mu          sync.Mutex
+	accessToken string
+	kedaluwarsa time.Time
+}
+
+type fcmServiceAccount struct {
+	ProjectId   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenUri    string `json:"token_uri"`
+}
+
+func NewFcmNotifier(client *client.Client, kredensial []byte) (*FcmNotifier, error) {
+	akun := new(fcmServiceAccount)
+	if err := json.Unmarshal(kredensial, akun); err != nil {
+		return nil, err
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(akun.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if akun.TokenUri == "" {
+		akun.TokenUri = "https://oauth2.googleapis.com/token"
+	}
+	return &FcmNotifier{Client: client, ProjectId: akun.ProjectId, ClientEmail: akun.ClientEmail, PrivateKey: privateKey, TokenUri: akun.TokenUri}, nil
+}
+
+func (n *FcmNotifier) Send(tokenPerangkat string, judul string, isi string, data map[string]string) error {
+	accessToken, err := n.token()
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.Client.Post(
+		fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", n.ProjectId),
+		client.Config{
+			Header: map[string]string{"Authorization": "Bearer " + accessToken},
+			Body: map[string]any{
+				"message": map[string]any{
+					"token":        tokenPerangkat,
+					"notification": map[string]string{"title": judul, "body": isi},
+					"data":         data,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() >= 200 && resp.StatusCode() <= 299 {
+		return nil
+	}
+
+	// hanya UNREGISTERED yang berarti token tidak berlaku, 404 lain (misalnya project salah) dicoba ulang
+	var balasan struct {
+		Error struct {
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(resp.Body(), &balasan)
+	for _, d := range balasan.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" {
+			return ErrTokenPerangkatTidakValid
+		}
+	}
+	return fmt.Errorf("fcm membalas status %d: %s", resp.StatusCode(), string(resp.Body()))
+}
+
+func (n *FcmNotifier) token() (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sekarang := time.Now()
+	if n.accessToken != "" && sekarang.Before(n.kedaluwarsa) {
+		return n.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   n.ClientEmail,
+		"scope": "https://www.googleapis.com/auth/firebase.messaging",
+		"aud":   n.TokenUri,
+		"iat":   sekarang.Unix(),
+		"exp":   sekarang.Add(time.Hour).Unix(),
+	}).SignedString(n.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := n.Client.Post(n.TokenUri, client.Config{
+		FormData: map[string]string{
+			"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer",
+			"assertion":  assertion,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return "", fmt.Errorf("oauth fcm membalas status %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	var balasan struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(resp.Body(), &balasan); err != nil {
+		return "", err
+	}
+	n.accessToken = balasan.AccessToken
+	// diperbarui satu menit sebelum benar-benar kedaluwarsa
+	n.kedaluwarsa = sekarang.Add(time.Duration(balasan.ExpiresIn)*time.Second - time.Minute)
+	return n.accessToken, nil
+}
+
+func NewNotifier(client *client.Client, config *viper.Viper) Notifier {
+	switch notifier := config.GetString("notifikasi.notifier"); notifier {
+	case "", constant.NotifierLog:
+		slog.Warn("Log notifier is active, push notifications are not delivered")
+		return &LogNotifier{}
+	case constant.NotifierFcm:
+		kredensial, err := os.ReadFile(config.GetString("notifikasi.fcm.kredensial"))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fcm, err := NewFcmNotifier(client, kredensial)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return fcm
+	default:
+		log.Fatalln(fmt.Errorf("notifier %q tidak dikenal", notifier))
+	}
+	return nil
+}
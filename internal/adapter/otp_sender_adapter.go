@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v3/client"
+	"github.com/spf13/viper"
+	"log"
+	"log/slog"
+	"prb_care_api/internal/constant"
+)
+
+type OtpSender interface {
+	Send(telepon string, pesan string) error
+}
+
+// untuk development, otp hanya ditulis ke log dan tidak benar-benar dikirim
+type LogOtpSender struct{}
+
+func (s *LogOtpSender) Send(telepon string, pesan string) error {
+	slog.Info("OTP sent", "telepon", telepon, "pesan", pesan)
+	return nil
+}
+
+// gateway sms atau whatsapp yang menerima {"to","message"} dengan bearer token
+type GatewayOtpSender struct {
+	Client *client.Client
+	Url    string
+	Token  string
+}
+
+func (s *GatewayOtpSender) Send(telepon string, pesan string) error {
+	resp, err := s.Client.Post(
+		s.Url,
+		client.Config{
+			Header: map[string]string{"Authorization": "Bearer " + s.Token},
+			Body:   map[string]string{"to": telepon, "message": pesan},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return fmt.Errorf("gateway otp membalas status %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return nil
+}
+
+func NewOtpSender(client *client.Client, config *viper.Viper) OtpSender {
+	// log harus dipilih secara eksplisit karena menulis otp yang masih berlaku ke log
+	switch sender := config.GetString("otp.sender"); sender {
+	case "":
+		log.Fatalln("otp.sender belum diatur, gunakan \"gateway\" atau \"log\" untuk development")
+	case constant.OtpSenderLog:
+		slog.Warn("OTP log sender is active, OTP is not delivered")
+		return &LogOtpSender{}
+	case constant.OtpSenderGateway:
+		return &GatewayOtpSender{client, config.GetString("otp.gateway.url"), config.GetString("otp.gateway.token")}
+	default:
+		log.Fatalln(fmt.Errorf("sender otp %q tidak dikenal", sender))
+	}
+	return nil
+}
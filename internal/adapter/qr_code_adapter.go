@@ -0,0 +1,40 @@
+package adapter
+
+import (
+	"fmt"
+	"github.com/skip2/go-qrcode"
+	"strings"
+)
+
+type QrCodeAdapter struct {
+}
+
+func NewQrCodeAdapter() *QrCodeAdapter {
+	return &QrCodeAdapter{}
+}
+
+func (a *QrCodeAdapter) PNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+func (a *QrCodeAdapter) SVG(content string) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := qr.Bitmap()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, len(bitmap), len(bitmap))
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#ffffff"/>`, len(bitmap), len(bitmap))
+	sb.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, hitam := range row {
+			if hitam {
+				fmt.Fprintf(&sb, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	sb.WriteString(`"/></svg>`)
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,63 @@
+package adapter
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// alfabet crockford base32 tanpa huruf yang mudah tertukar (I, L, O, U)
+const resiAlfabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const resiPanjang = 9
+
+type ResiAdapter struct {
+}
+
+func NewResiAdapter() *ResiAdapter {
+	return &ResiAdapter{}
+}
+
+func (a *ResiAdapter) Generate() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(resiAlfabet)))
+	for i := 0; i < resiPanjang; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(resiAlfabet[n.Int64()])
+	}
+	kode := sb.String()
+	return kode + string(resiAlfabet[checkDigit(kode)]), nil
+}
+
+func (a *ResiAdapter) Valid(resi string) bool {
+	if len(resi) != resiPanjang+1 {
+		return false
+	}
+	kode := resi[:resiPanjang]
+	for i := 0; i < len(kode); i++ {
+		if strings.IndexByte(resiAlfabet, kode[i]) < 0 {
+			return false
+		}
+	}
+	return resi[resiPanjang] == resiAlfabet[checkDigit(kode)]
+}
+
+// luhn mod n: salah ketik satu karakter dan hampir semua pertukaran karakter bersebelahan terdeteksi
+func checkDigit(kode string) int {
+	n := len(resiAlfabet)
+	faktor := 2
+	total := 0
+	for i := len(kode) - 1; i >= 0; i-- {
+		nilai := strings.IndexByte(resiAlfabet, kode[i]) * faktor
+		total += nilai/n + nilai%n
+		if faktor == 2 {
+			faktor = 1
+		} else {
+			faktor = 2
+		}
+	}
+	return (n - total%n) % n
+}
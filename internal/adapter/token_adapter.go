@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"math/big"
+	"prb_care_api/internal/model"
+	"time"
+)
+
+type TokenAdapter struct {
+	Config *viper.Viper
+}
+
+func NewTokenAdapter(config *viper.Viper) *TokenAdapter {
+	return &TokenAdapter{Config: config}
+}
+
+// access token berumur pendek (jwt.access_exp menit), refresh token mengikuti jwt.exp jam
+func (a *TokenAdapter) AccessExp() time.Duration {
+	exp := a.Config.GetInt("jwt.access_exp")
+	if exp <= 0 {
+		exp = 15
+	}
+	return time.Duration(exp) * time.Minute
+}
+
+func (a *TokenAdapter) RefreshExp() time.Duration {
+	return time.Duration(a.Config.GetInt("jwt.exp")) * time.Hour
+}
+
+func (a *TokenAdapter) Access(auth *model.Auth) (string, error) {
+	sekarang := time.Now()
+	claims := jwt.MapClaims{
+		"sub":  auth.ID,
+		"role": auth.Role,
+		"ver":  auth.VersiToken,
+		"sid":  auth.IdSesi,
+		"iat":  sekarang.Unix(),
+		"exp":  sekarang.Add(a.AccessExp()).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(a.Config.GetString("jwt.secret")))
+}
+
+func (a *TokenAdapter) Parse(token string) (*model.Auth, error) {
+	tokenVerify, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(a.Config.GetString("jwt.secret")), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := tokenVerify.Claims.(jwt.MapClaims)
+	if !ok || !tokenVerify.Valid {
+		return nil, errors.New("token tidak valid")
+	}
+	sub, okSub := claims["sub"].(float64)
+	role, okRole := claims["role"].(string)
+	ver, okVer := claims["ver"].(float64)
+	sid, okSid := claims["sid"].(string)
+	if !okSub || !okRole || !okVer || !okSid {
+		return nil, errors.New("klaim token tidak lengkap")
+	}
+	return &model.Auth{ID: int32(sub), Role: role, VersiToken: int32(ver), IdSesi: sid}, nil
+}
+
+func (a *TokenAdapter) IdSesi() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// token acak sekali pakai untuk refresh token dan reset password, hanya hash-nya yang disimpan di database
+func (a *TokenAdapter) Acak() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, a.Hash(token), nil
+}
+
+// kode numerik 6 digit yang dikirim lewat sms atau whatsapp
+func (a *TokenAdapter) Otp() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (a *TokenAdapter) Hash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
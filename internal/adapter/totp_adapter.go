@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+type TotpAdapter struct {
+	Config *viper.Viper
+}
+
+func NewTotpAdapter(config *viper.Viper) *TotpAdapter {
+	return &TotpAdapter{Config: config}
+}
+
+var totpOpts = totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+func (a *TotpAdapter) Generate(akun string) (string, string, error) {
+	issuer := a.Config.GetString("dua_faktor.issuer")
+	if issuer == "" {
+		issuer = "PRB Care"
+	}
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: issuer, AccountName: akun})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// kode diterima untuk langkah waktu sebelum, saat ini dan sesudahnya, tapi tidak boleh memakai
+// langkah yang sudah pernah dipakai supaya kode yang sama tidak bisa diputar ulang
+func (a *TotpAdapter) Validate(secret string, kode string, langkahTerakhir int64) (int64, bool) {
+	sekarang := time.Now().Unix() / int64(totpOpts.Period)
+	for _, langkah := range []int64{sekarang - 1, sekarang, sekarang + 1} {
+		if langkah <= langkahTerakhir {
+			continue
+		}
+		benar, err := totp.GenerateCodeCustom(secret, time.Unix(langkah*int64(totpOpts.Period), 0), totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(benar), []byte(kode)) == 1 {
+			return langkah, true
+		}
+	}
+	return 0, false
+}
+
+// kode cadangan berbentuk xxxxx-xxxxx, dipakai bila perangkat authenticator hilang
+func (a *TotpAdapter) KodeCadangan(jumlah int) ([]string, error) {
+	kode := make([]string, 0, jumlah)
+	for range jumlah {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+		kode = append(kode, s[:5]+"-"+s[5:])
+	}
+	return kode, nil
+}
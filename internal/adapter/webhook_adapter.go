@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gofiber/fiber/v3/client"
+	"strconv"
+	"time"
+)
+
+type WebhookAdapter struct {
+	Client *client.Client
+}
+
+func NewWebhookAdapter(client *client.Client) *WebhookAdapter {
+	return &WebhookAdapter{Client: client}
+}
+
+func (a *WebhookAdapter) Secret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// hmac-sha256 dari "<waktu>.<payload>" memakai secret webhook. waktu ikut ditandatangani agar penerima bisa menolak
+// payload lama yang dikirim ulang pihak lain
+func (a *WebhookAdapter) Tanda(secret string, waktu int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(waktu, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// mengembalikan status http balasan, 0 bila permintaan tidak sampai ke penerima
+func (a *WebhookAdapter) Kirim(url string, secret string, idEvent string, jenis string, payload []byte, timeout time.Duration) (int, error) {
+	waktu := time.Now().Unix()
+	resp, err := a.Client.R().
+		SetHeaders(map[string]string{
+			"Content-Type":         "application/json",
+			"X-PRB-Care-Event-Id":  idEvent,
+			"X-PRB-Care-Event":     jenis,
+			"X-PRB-Care-Timestamp": strconv.FormatInt(waktu, 10),
+			"X-PRB-Care-Signature": a.Tanda(secret, waktu, payload),
+		}).
+		SetRawBody(payload).
+		SetTimeout(timeout).
+		Post(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return resp.StatusCode(), fmt.Errorf("webhook membalas status %d", resp.StatusCode())
+	}
+	return resp.StatusCode(), nil
+}
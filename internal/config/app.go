@@ -1,18 +1,22 @@
 package config
 
 import (
+	"context"
 	"github.com/go-playground/mold/v4"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/client"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
+	"log/slog"
 	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
 	"prb_care_api/internal/controller"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/repository"
 	"prb_care_api/internal/route"
 	"prb_care_api/internal/service"
+	"time"
 )
 
 type BootstrapConfig struct {
@@ -33,49 +37,138 @@ func Bootstrap(config *BootstrapConfig) {
 	obatRepository := repository.NewObatRepository()
 	pasienRepository := repository.NewPasienRepository()
 	kontrolBalikRepository := repository.NewKontrolBalikRepository()
+	antreanKontrolBalikRepository := repository.NewAntreanKontrolBalikRepository()
 	pengambilanObatRepository := repository.NewPengambilanObatRepository()
+	pengambilanObatItemRepository := repository.NewPengambilanObatItemRepository()
+	timelinePasienRepository := repository.NewTimelinePasienRepository()
+	pemeriksaanLabRepository := repository.NewPemeriksaanLabRepository()
+	hasilPemeriksaanLabRepository := repository.NewHasilPemeriksaanLabRepository()
+	mutasiObatRepository := repository.NewMutasiObatRepository()
+	batchObatRepository := repository.NewBatchObatRepository()
+	alokasiBatchObatRepository := repository.NewAlokasiBatchObatRepository()
+	peringatanStokObatRepository := repository.NewPeringatanStokObatRepository()
 	artikelRepository := repository.NewArtikelRepository()
 	fileRepository := repository.NewFileRepository()
+	jadwalOperasionalRepository := repository.NewJadwalOperasionalRepository()
+	sesiRepository := repository.NewSesiRepository()
+	refreshTokenRepository := repository.NewRefreshTokenRepository()
+	percobaanLoginRepository := repository.NewPercobaanLoginRepository()
+	otpResetPasswordRepository := repository.NewOtpResetPasswordRepository()
+	duaFaktorRepository := repository.NewDuaFaktorRepository()
+	kodeCadanganRepository := repository.NewKodeCadanganRepository()
+	tantanganDuaFaktorRepository := repository.NewTantanganDuaFaktorRepository()
+	auditRepository := repository.NewAuditRepository()
+	notifikasiRepository := repository.NewNotifikasiRepository()
+	riwayatJobRepository := repository.NewRiwayatJobRepository()
+	outboxRepository := repository.NewOutboxRepository()
+	webhookRepository := repository.NewWebhookRepository()
+	webhookEventRepository := repository.NewWebhookEventRepository()
+	pengirimanWebhookRepository := repository.NewPengirimanWebhookRepository()
 
-	captchaAdapter := adapter.NewCaptcha(config.Client)
+	captchaAdapter := adapter.NewCaptcha(config.Client, config.Config)
 	fileAdapter := adapter.NewFileAdapter()
+	resiAdapter := adapter.NewResiAdapter()
+	qrCodeAdapter := adapter.NewQrCodeAdapter()
+	tokenAdapter := adapter.NewTokenAdapter(config.Config)
+	otpSender := adapter.NewOtpSender(config.Client, config.Config)
+	totpAdapter := adapter.NewTotpAdapter(config.Config)
+	notifier := adapter.NewNotifier(config.Client, config.Config)
+	webhookAdapter := adapter.NewWebhookAdapter(config.Client)
+	eventHandler := adapter.NewHandlerSink()
+	eventSinks := adapter.NewEventSinks(config.Client, config.Config, eventHandler)
 
-	adminSuperService := service.NewAdminSuperService(config.DB, adminSuperRepository, captchaAdapter, config.Validate, config.Config)
-	adminPuskesmasService := service.NewAdminPuskesmasService(config.DB, adminPuskesmasRepository, pasienRepository, captchaAdapter, config.Validate, config.Config)
-	adminApotekService := service.NewAdminApotekService(config.DB, adminApotekRepository, obatRepository, config.Validate, captchaAdapter, config.Config)
-	penggunaService := service.NewPenggunaService(config.DB, penggunaRepository, pasienRepository, config.Validate, captchaAdapter, config.Config)
-	obatService := service.NewObatService(config.DB, obatRepository, adminApotekRepository, pengambilanObatRepository, config.Validate)
-	pasienService := service.NewPasienService(config.DB, pasienRepository, adminPuskesmasRepository, penggunaRepository, kontrolBalikRepository, pengambilanObatRepository, config.Validate)
-	kontrolBalikService := service.NewKontrolBalikService(config.DB, kontrolBalikRepository, pasienRepository, config.Validate)
-	pengambilanObatService := service.NewPengambilanObatService(config.DB, pengambilanObatRepository, pasienRepository, obatRepository, config.Validate)
+	adminSuperService := service.NewAdminSuperService(config.DB, adminSuperRepository, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, duaFaktorRepository, tantanganDuaFaktorRepository, config.Validate, config.Config)
+	adminPuskesmasService := service.NewAdminPuskesmasService(config.DB, adminPuskesmasRepository, pasienRepository, jadwalOperasionalRepository, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, duaFaktorRepository, tantanganDuaFaktorRepository, config.Validate, config.Config)
+	adminApotekService := service.NewAdminApotekService(config.DB, adminApotekRepository, obatRepository, config.Validate, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, duaFaktorRepository, tantanganDuaFaktorRepository, config.Config)
+	penggunaService := service.NewPenggunaService(config.DB, penggunaRepository, pasienRepository, config.Validate, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, config.Config)
+	authService := service.NewAuthService(config.DB, sesiRepository, refreshTokenRepository, adminSuperRepository, adminPuskesmasRepository, adminApotekRepository, penggunaRepository, tokenAdapter, config.Validate)
+	percobaanLoginService := service.NewPercobaanLoginService(config.DB, percobaanLoginRepository, config.Validate)
+	auditService := service.NewAuditService(config.DB, auditRepository, config.Validate)
+	notifikasiService := service.NewNotifikasiService(config.DB, notifikasiRepository, penggunaRepository, notifier, config.Validate, config.Config)
+	pengingatService := service.NewPengingatService(config.DB, kontrolBalikRepository, pengambilanObatRepository, notifikasiRepository, config.Config)
+	riwayatJobService := service.NewRiwayatJobService(config.DB, riwayatJobRepository, config.Validate)
+	webhookService := service.NewWebhookService(config.DB, webhookRepository, webhookEventRepository, pengirimanWebhookRepository, webhookAdapter, config.Validate, config.Config)
+	eventService := service.NewEventService(config.DB, outboxRepository, append(eventSinks, webhookService), config.Validate, config.Config)
+	duaFaktorService := service.NewDuaFaktorService(config.DB, duaFaktorRepository, kodeCadanganRepository, tantanganDuaFaktorRepository, sesiRepository, refreshTokenRepository, adminSuperRepository, adminPuskesmasRepository, adminApotekRepository, percobaanLoginRepository, tokenAdapter, totpAdapter, qrCodeAdapter, config.Validate, config.Config)
+	resetPasswordService := service.NewResetPasswordService(config.DB, penggunaRepository, otpResetPasswordRepository, sesiRepository, captchaAdapter, tokenAdapter, otpSender, config.Validate, config.Config)
+	obatService := service.NewObatService(config.DB, obatRepository, adminApotekRepository, pengambilanObatRepository, mutasiObatRepository, batchObatRepository, peringatanStokObatRepository, auditRepository, config.Validate)
+	pasienService := service.NewPasienService(config.DB, pasienRepository, adminPuskesmasRepository, penggunaRepository, kontrolBalikRepository, pengambilanObatRepository, timelinePasienRepository, auditRepository, outboxRepository, config.Validate)
+	tandaVitalService := service.NewTandaVitalService(config.DB, pasienRepository, kontrolBalikRepository, config.Validate, config.Config)
+	pemeriksaanLabService := service.NewPemeriksaanLabService(config.DB, pemeriksaanLabRepository, hasilPemeriksaanLabRepository, auditRepository, config.Validate)
+	hasilPemeriksaanLabService := service.NewHasilPemeriksaanLabService(config.DB, hasilPemeriksaanLabRepository, pemeriksaanLabRepository, kontrolBalikRepository, pasienRepository, auditRepository, config.Validate)
+	kontrolBalikService := service.NewKontrolBalikService(config.DB, kontrolBalikRepository, antreanKontrolBalikRepository, jadwalOperasionalRepository, pasienRepository, auditRepository, notifikasiRepository, outboxRepository, config.Validate)
+	pengambilanObatService := service.NewPengambilanObatService(config.DB, pengambilanObatRepository, pengambilanObatItemRepository, pasienRepository, obatRepository, mutasiObatRepository, batchObatRepository, alokasiBatchObatRepository, peringatanStokObatRepository, resiAdapter, qrCodeAdapter, auditRepository, notifikasiRepository, outboxRepository, config.Validate)
+	jadwalOperasionalService := service.NewJadwalOperasionalService(config.DB, jadwalOperasionalRepository, adminPuskesmasRepository, config.Validate)
 	artikelSevice := service.NewArtikelService(config.DB, artikelRepository, adminPuskesmasRepository, fileRepository, fileAdapter, config.Validate, config.Config)
 
+	authController := controller.NewAuthController(authService)
 	adminSuperController := controller.NewAdminSuperController(adminSuperService)
+	percobaanLoginController := controller.NewPercobaanLoginController(percobaanLoginService)
+	auditController := controller.NewAuditController(auditService)
+	notifikasiController := controller.NewNotifikasiController(notifikasiService)
+	riwayatJobController := controller.NewRiwayatJobController(riwayatJobService)
+	eventController := controller.NewEventController(eventService)
+	webhookController := controller.NewWebhookController(webhookService, config.Modifier)
+	duaFaktorController := controller.NewDuaFaktorController(duaFaktorService)
+	resetPasswordController := controller.NewResetPasswordController(resetPasswordService)
 	adminPuskesmasController := controller.NewAdminPuskesmasController(adminPuskesmasService, config.Modifier)
 	adminApotekController := controller.NewAdminApotekController(adminApotekService, config.Modifier)
 	penggunaController := controller.NewPenggunaController(penggunaService, config.Modifier)
 	obatController := controller.NewObatController(obatService, config.Modifier)
 	pasienController := controller.NewPasienController(pasienService, config.Modifier)
+	tandaVitalController := controller.NewTandaVitalController(tandaVitalService)
+	pemeriksaanLabController := controller.NewPemeriksaanLabController(pemeriksaanLabService, config.Modifier)
+	hasilPemeriksaanLabController := controller.NewHasilPemeriksaanLabController(hasilPemeriksaanLabService)
 	kontrolBalikController := controller.NewKontrolBalikController(kontrolBalikService, config.Modifier)
 	pengambilanObatController := controller.NewPengambilanObatController(pengambilanObatService)
 	artikelController := controller.NewArtikelController(artikelSevice, config.Modifier)
+	jadwalOperasionalController := controller.NewJadwalOperasionalController(jadwalOperasionalService)
 
-	authMiddleware := middleware.AuthMiddleware(config.Config, adminSuperService, adminPuskesmasService, adminApotekService, penggunaService)
+	authMiddleware := middleware.AuthMiddleware(tokenAdapter, authService)
 
 	route := route.Config{
-		App:                       config.App,
-		AuthMiddleware:            authMiddleware,
-		AdminSuperController:      adminSuperController,
-		AdminPuskesmasController:  adminPuskesmasController,
-		AdminApotekController:     adminApotekController,
-		PenggunaController:        penggunaController,
-		ObatController:            obatController,
-		PasienController:          pasienController,
-		KontrolBalikController:    kontrolBalikController,
-		PengambilanObatController: pengambilanObatController,
-		ArtikelController:         artikelController,
-		Config:                    config.Config,
+		App:                           config.App,
+		AuthMiddleware:                authMiddleware,
+		AuthController:                authController,
+		AdminSuperController:          adminSuperController,
+		PercobaanLoginController:      percobaanLoginController,
+		ResetPasswordController:       resetPasswordController,
+		DuaFaktorController:           duaFaktorController,
+		AuditController:               auditController,
+		NotifikasiController:          notifikasiController,
+		RiwayatJobController:          riwayatJobController,
+		EventController:               eventController,
+		WebhookController:             webhookController,
+		AdminPuskesmasController:      adminPuskesmasController,
+		AdminApotekController:         adminApotekController,
+		PenggunaController:            penggunaController,
+		ObatController:                obatController,
+		TandaVitalController:          tandaVitalController,
+		PasienController:              pasienController,
+		HasilPemeriksaanLabController: hasilPemeriksaanLabController,
+		PemeriksaanLabController:      pemeriksaanLabController,
+		KontrolBalikController:        kontrolBalikController,
+		PengambilanObatController:     pengambilanObatController,
+		ArtikelController:             artikelController,
+		JadwalOperasionalController:   jadwalOperasionalController,
+		Config:                        config.Config,
 	}
 	route.Setup()
 
+	go notifikasiService.Jalankan(context.Background())
+
+	eventHandler.Daftar(constant.EventSemua, func(event *adapter.Event) error {
+		slog.Info("Event published", "id", event.ID, "jenis", event.Jenis, "agregat", event.Agregat, "idAgregat", event.IdAgregat)
+		return nil
+	})
+	go eventService.Jalankan(context.Background())
+	go webhookService.Jalankan(context.Background())
+
+	scheduler := NewScheduler(config.DB, riwayatJobRepository, config.Config,
+		Job{Nama: constant.JobPengingat, Interval: 15 * time.Minute, Jalankan: pengingatService.Kirim},
+		Job{Nama: constant.JobBatalKontrolBalikTerlewat, Interval: time.Hour, Jalankan: kontrolBalikService.BatalTerlewat},
+		Job{Nama: constant.JobBatalPengambilanObatTerlewat, Interval: time.Hour, Jalankan: pengambilanObatService.BatalTerlewat},
+	)
+	go scheduler.Jalankan(context.Background())
+
 }
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v3"
+	"prb_care_api/internal/constant"
+)
+
+type katalogError struct {
+	Status int
+	Pesan  map[string]string
+}
+
+// katalog error domain. pesan boleh memuat verb fmt yang diisi param dari model.AppError
+var katalog = map[string]katalogError{
+	constant.KodeErrorParameterHalaman: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Parameter %s tidak valid",
+		bahasaInggris:   "Invalid %s parameter",
+	}},
+	constant.KodeErrorLoginSalah: {fiber.StatusUnauthorized, map[string]string{
+		bahasaIndonesia: "Username atau password salah",
+		bahasaInggris:   "Incorrect username or password",
+	}},
+	constant.KodeErrorLoginDibatasi: {fiber.StatusTooManyRequests, map[string]string{
+		bahasaIndonesia: "Terlalu banyak percobaan login, coba lagi dalam %d detik",
+		bahasaInggris:   "Too many login attempts, try again in %d seconds",
+	}},
+	constant.KodeErrorPasswordSaatIniSalah: {fiber.StatusUnauthorized, map[string]string{
+		bahasaIndonesia: "Password saat ini salah",
+		bahasaInggris:   "Current password is incorrect",
+	}},
+	constant.KodeErrorUsernameDigunakan: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Username sudah digunakan",
+		bahasaInggris:   "Username is already taken",
+	}},
+	constant.KodeErrorTeleponDigunakan: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Telepon sudah digunakan",
+		bahasaInggris:   "Phone number is already in use",
+	}},
+	constant.KodeErrorDuaFaktorSalah: {fiber.StatusUnauthorized, map[string]string{
+		bahasaIndonesia: "Kode 2FA salah",
+		bahasaInggris:   "Incorrect 2FA code",
+	}},
+	constant.KodeErrorDuaFaktorAktif: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "2FA sudah aktif",
+		bahasaInggris:   "2FA is already enabled",
+	}},
+	constant.KodeErrorDuaFaktorBelumEnrol: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "2FA belum dienrol",
+		bahasaInggris:   "2FA has not been enrolled",
+	}},
+	constant.KodeErrorDuaFaktorWajib: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "2FA wajib untuk role ini",
+		bahasaInggris:   "2FA is mandatory for this role",
+	}},
+	constant.KodeErrorOtpSalah: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "OTP salah atau sudah kedaluwarsa",
+		bahasaInggris:   "OTP is incorrect or has expired",
+	}},
+	constant.KodeErrorTokenResetTidakValid: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Token reset tidak valid atau sudah kedaluwarsa",
+		bahasaInggris:   "Reset token is invalid or has expired",
+	}},
+	constant.KodeErrorCaptchaTidakValid: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Verifikasi captcha gagal, silakan coba lagi",
+		bahasaInggris:   "Captcha verification failed, please try again",
+	}},
+	constant.KodeErrorPenggunaTerkaitPasien: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pengguna masih terkait dengan data pasien yang ada",
+		bahasaInggris:   "User is still linked to existing patient records",
+	}},
+	constant.KodeErrorAdminPuskesmasTerkaitPasien: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Admin puskesmas masih terkait dengan data pasien yang ada",
+		bahasaInggris:   "Puskesmas admin is still linked to existing patient records",
+	}},
+	constant.KodeErrorAdminApotekTerkaitObat: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Admin apotek masih terkait dengan data obat yang ada",
+		bahasaInggris:   "Pharmacy admin is still linked to existing medicine records",
+	}},
+	constant.KodeErrorPasienTerkaitKontrolBalik: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pasien masih terkait dengan data kontrol balik yang ada",
+		bahasaInggris:   "Patient is still linked to existing follow-up records",
+	}},
+	constant.KodeErrorPasienTerkaitPengambilanObat: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pasien masih terkait dengan data pengambilan obat yang ada",
+		bahasaInggris:   "Patient is still linked to existing medicine pickup records",
+	}},
+	constant.KodeErrorPasienKontrolBalikAktif: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pasien masih memiliki kontrol balik yang harus dilakukan",
+		bahasaInggris:   "Patient still has a pending follow-up",
+	}},
+	constant.KodeErrorPasienPengambilanObatAktif: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pasien masih memiliki pengambilan obat yang harus dilakukan",
+		bahasaInggris:   "Patient still has a pending medicine pickup",
+	}},
+	constant.KodeErrorObatTerkaitPengambilanObat: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Obat masih terkait dengan data pengambilan obat yang ada",
+		bahasaInggris:   "Medicine is still linked to existing medicine pickup records",
+	}},
+	constant.KodeErrorNomorAntreanDigunakan: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Nomor antrean pada tanggal tersebut sudah digunakan",
+		bahasaInggris:   "Queue number is already used on that date",
+	}},
+	constant.KodeErrorKuotaKontrolBalikPenuh: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Kuota kontrol balik pada tanggal tersebut sudah penuh",
+		bahasaInggris:   "Follow-up quota for that date is full",
+	}},
+	constant.KodeErrorSlotKontrolBalikHabis: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Tidak ada slot kontrol balik yang tersedia pada tanggal tersebut",
+		bahasaInggris:   "No follow-up slot is available on that date",
+	}},
+	constant.KodeErrorPuskesmasTutup: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Puskesmas tidak beroperasi pada tanggal tersebut",
+		bahasaInggris:   "Puskesmas is closed on that date",
+	}},
+	constant.KodeErrorJadwalOperasional: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Jam tutup harus setelah jam buka dan cukup untuk minimal satu slot",
+		bahasaInggris:   "Closing time must be after opening time and leave room for at least one slot",
+	}},
+	constant.KodeErrorRentangTanggal: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Rentang tanggal maksimal %d hari",
+		bahasaInggris:   "Date range must not exceed %d days",
+	}},
+	constant.KodeErrorStokTidakCukup: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Jumlah obat melebihi persediaan apotek yang belum kedaluwarsa",
+		bahasaInggris:   "Medicine quantity exceeds unexpired pharmacy stock",
+	}},
+	constant.KodeErrorStokBatchTidakCukup: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Persediaan obat yang belum kedaluwarsa tidak mencukupi",
+		bahasaInggris:   "Unexpired medicine stock is insufficient",
+	}},
+	constant.KodeErrorBatchKedaluwarsa: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Batch obat sudah kedaluwarsa",
+		bahasaInggris:   "Medicine batch has expired",
+	}},
+	constant.KodeErrorBatchBerbeda: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Nomor batch sudah terdaftar dengan tanggal kedaluwarsa berbeda",
+		bahasaInggris:   "Batch number is already registered with a different expiry date",
+	}},
+	constant.KodeErrorResiTidakValid: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Resi tidak valid",
+		bahasaInggris:   "Receipt number is invalid",
+	}},
+	constant.KodeErrorResiBedaApotek: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Seluruh obat dalam satu resi harus berasal dari apotek yang sama",
+		bahasaInggris:   "All medicines in one receipt must come from the same pharmacy",
+	}},
+	constant.KodeErrorKodePemeriksaanLabDigunakan: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Kode pemeriksaan lab sudah digunakan",
+		bahasaInggris:   "Lab test code is already taken",
+	}},
+	constant.KodeErrorPemeriksaanLabTerkaitHasil: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Pemeriksaan lab masih terkait dengan hasil lab yang ada",
+		bahasaInggris:   "Lab test is still linked to existing lab results",
+	}},
+	constant.KodeErrorRentangRujukan: {fiber.StatusBadRequest, map[string]string{
+		bahasaIndonesia: "Nilai maksimum rujukan harus lebih besar dari nilai minimum",
+		bahasaInggris:   "Reference range maximum must be greater than its minimum",
+	}},
+	constant.KodeErrorNotifikasiTerkirim: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Notifikasi sudah terkirim",
+		bahasaInggris:   "Notification has already been sent",
+	}},
+	constant.KodeErrorEventTerkirim: {fiber.StatusConflict, map[string]string{
+		bahasaIndonesia: "Event sudah terkirim",
+		bahasaInggris:   "Event has already been sent",
+	}},
+}
+
+// kode untuk error umum fiber berdasarkan status, status lain jatuh ke kode status terdekat
+var kodeStatus = map[int]string{
+	fiber.StatusBadRequest:            constant.KodeErrorBadRequest,
+	fiber.StatusUnauthorized:          constant.KodeErrorUnauthorized,
+	fiber.StatusForbidden:             constant.KodeErrorForbidden,
+	fiber.StatusNotFound:              constant.KodeErrorNotFound,
+	fiber.StatusMethodNotAllowed:      constant.KodeErrorMethodNotAllowed,
+	fiber.StatusConflict:              constant.KodeErrorConflict,
+	fiber.StatusRequestEntityTooLarge: constant.KodeErrorPayloadTooLarge,
+	fiber.StatusTooManyRequests:       constant.KodeErrorTooManyRequests,
+	fiber.StatusInternalServerError:   constant.KodeErrorInternalServerError,
+	fiber.StatusServiceUnavailable:    constant.KodeErrorServiceUnavailable,
+}
+
+func kodeError(status int) string {
+	if kode, ok := kodeStatus[status]; ok {
+		return kode
+	}
+	if status < fiber.StatusInternalServerError {
+		return constant.KodeErrorBadRequest
+	}
+	return constant.KodeErrorInternalServerError
+}
+
+// mengembalikan status dan pesan kode dari katalog, ok false bila kode belum terdaftar
+func pesanError(kode string, param []any, bahasa string) (int, string, bool) {
+	k, ok := katalog[kode]
+	if !ok {
+		return 0, "", false
+	}
+	pesan := k.Pesan[bahasaValidasi(bahasa)]
+	if len(param) > 0 {
+		pesan = fmt.Sprintf(pesan, param...)
+	}
+	return k.Status, pesan, true
+}
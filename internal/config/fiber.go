@@ -1,22 +1,61 @@
 package config
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/model"
 )
 
-func NewFiber() *fiber.App {
-	return fiber.New(fiber.Config{ErrorHandler: ErrorHandler(), BodyLimit: 50 * 1024 * 1024})
+func NewFiber(config *viper.Viper) *fiber.App {
+	// di balik reverse proxy ip klien dibaca dari header agar pembatasan login per ip tidak mengenai proxy
+	trustedProxies := config.GetStringSlice("web.trusted_proxies")
+	return fiber.New(fiber.Config{
+		ErrorHandler:            ErrorHandler(),
+		BodyLimit:               50 * 1024 * 1024,
+		ProxyHeader:             config.GetString("web.proxy_header"),
+		EnableTrustedProxyCheck: len(trustedProxies) > 0,
+		TrustedProxies:          trustedProxies,
+	})
 }
 func ErrorHandler() fiber.ErrorHandler {
 	return func(ctx fiber.Ctx, err error) error {
-		code := err.(*fiber.Error).Code
-		if code == fiber.StatusNotFound {
-			return ctx.Status(code).JSON(fiber.Map{
-				"error": "Not found",
+		bahasa := ctx.AcceptsLanguages(bahasaIndonesia, bahasaInggris)
+
+		var validationError *model.ValidationError
+		if errors.As(err, &validationError) {
+			pesan := "Data yang dikirim tidak valid"
+			if bahasaValidasi(bahasa) == bahasaInggris {
+				pesan = "The submitted data is invalid"
+			}
+			return ctx.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Kode:   constant.KodeErrorValidasi,
+				Error:  pesan,
+				Detail: detailValidasi(validationError.Errors, bahasa),
 			})
 		}
-		return ctx.Status(code).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+
+		var appError *model.AppError
+		if errors.As(err, &appError) {
+			if status, pesan, ok := pesanError(appError.Kode, appError.Param, bahasa); ok {
+				return ctx.Status(status).JSON(model.ErrorResponse{Kode: appError.Kode, Error: pesan})
+			}
+			slog.Error("Error code is not registered in catalog", "kode", appError.Kode)
+			err = fiber.ErrInternalServerError
+		}
+
+		var fiberError *fiber.Error
+		if !errors.As(err, &fiberError) {
+			// error selain fiber.Error tidak boleh bocor ke klien
+			slog.Error(err.Error())
+			fiberError = fiber.ErrInternalServerError
+		}
+		code := fiberError.Code
+		if code == fiber.StatusNotFound {
+			return ctx.Status(code).JSON(model.ErrorResponse{Kode: kodeError(code), Error: "Not found"})
+		}
+		return ctx.Status(code).JSON(model.ErrorResponse{Kode: kodeError(code), Error: fiberError.Message})
 	}
 }
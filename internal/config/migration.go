@@ -0,0 +1,269 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"maps"
+	"prb_care_api/internal/entity"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+//go:embed migration/*.sql
+var migrationFS embed.FS
+
+// nama file migrasi: 000001_nama.up.sql dan 000001_nama.down.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// kunci advisory lock agar hanya satu proses yang menjalankan migrasi
+const migrationLock = 7_105_001
+
+const schemaMigrationQuery = "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+	"versi bigint PRIMARY KEY, nama varchar(255) NOT NULL, checksum varchar(64) NOT NULL, waktu bigint NOT NULL)"
+
+type Migration struct {
+	Versi    int64
+	Nama     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Versi      int64
+	Nama       string
+	Diterapkan bool
+	Waktu      int64
+}
+
+func LoadMigration() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationFS, "migration")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make(map[int64]*Migration)
+	for _, file := range files {
+		match := migrationFile.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", file.Name())
+		}
+		versi, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		isi, err := fs.ReadFile(migrationFS, "migration/"+file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := migrations[versi]
+		if !ok {
+			m = &Migration{Versi: versi, Nama: match[2]}
+			migrations[versi] = m
+		} else if m.Nama != match[2] {
+			return nil, fmt.Errorf("versi migrasi %d digunakan lebih dari satu nama", versi)
+		}
+		if match[3] == "up" {
+			m.Up = string(isi)
+			checksum := sha256.Sum256(isi)
+			m.Checksum = hex.EncodeToString(checksum[:])
+		} else {
+			m.Down = string(isi)
+		}
+	}
+
+	var result []Migration
+	for _, versi := range slices.Sorted(maps.Keys(migrations)) {
+		m := migrations[versi]
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migrasi %d_%s harus memiliki skrip up dan down", m.Versi, m.Nama)
+		}
+		result = append(result, *m)
+	}
+	return result, nil
+}
+
+func MigrateUp(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	migrations, err := LoadMigration()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Exec(schemaMigrationQuery).Error; err != nil {
+		return nil, err
+	}
+	if _, err := appliedMigration(ctx, db, migrations); err != nil {
+		return nil, err
+	}
+
+	var diterapkan []Migration
+	for _, m := range migrations {
+		ok, err := applyMigration(ctx, db, m)
+		if err != nil {
+			return diterapkan, fmt.Errorf("migrasi %d_%s gagal: %w", m.Versi, m.Nama, err)
+		}
+		if ok {
+			diterapkan = append(diterapkan, m)
+		}
+	}
+	return diterapkan, nil
+}
+
+func MigrateDown(ctx context.Context, db *gorm.DB, langkah int) ([]Migration, error) {
+	migrations, err := LoadMigration()
+	if err != nil {
+		return nil, err
+	}
+	if !db.WithContext(ctx).Migrator().HasTable(&entity.SchemaMigration{}) {
+		return nil, nil
+	}
+	applied, err := appliedMigration(ctx, db, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var dibatalkan []Migration
+	for i := len(migrations) - 1; i >= 0 && len(dibatalkan) < langkah; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Versi]; !ok {
+			continue
+		}
+		if err := revertMigration(ctx, db, m); err != nil {
+			return dibatalkan, fmt.Errorf("pembatalan migrasi %d_%s gagal: %w", m.Versi, m.Nama, err)
+		}
+		dibatalkan = append(dibatalkan, m)
+	}
+	return dibatalkan, nil
+}
+
+func MigrateStatus(ctx context.Context, db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigration()
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]entity.SchemaMigration)
+	if db.WithContext(ctx).Migrator().HasTable(&entity.SchemaMigration{}) {
+		if applied, err = appliedMigration(ctx, db, migrations); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []MigrationStatus
+	for _, m := range migrations {
+		status := MigrationStatus{Versi: m.Versi, Nama: m.Nama}
+		if s, ok := applied[m.Versi]; ok {
+			status.Diterapkan = true
+			status.Waktu = s.Waktu
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// server tidak boleh berjalan dengan skema yang tertinggal dari kode
+func CheckMigration(ctx context.Context, db *gorm.DB) error {
+	status, err := MigrateStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	var tertunda int
+	for _, s := range status {
+		if !s.Diterapkan {
+			tertunda++
+		}
+	}
+	if tertunda > 0 {
+		return fmt.Errorf("skema database tertinggal %d migrasi, jalankan \"prb_care_api migrate up\"", tertunda)
+	}
+	return nil
+}
+
+// memastikan migrasi yang sudah diterapkan tidak diubah dan dikenal oleh versi aplikasi ini
+func appliedMigration(ctx context.Context, db *gorm.DB, migrations []Migration) (map[int64]entity.SchemaMigration, error) {
+	var schemaMigrations []entity.SchemaMigration
+	if err := db.WithContext(ctx).Order("versi ASC").Find(&schemaMigrations).Error; err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Versi] = m
+	}
+	applied := make(map[int64]entity.SchemaMigration, len(schemaMigrations))
+	for _, s := range schemaMigrations {
+		m, ok := known[s.Versi]
+		if !ok {
+			return nil, fmt.Errorf("migrasi %d_%s sudah diterapkan tetapi tidak dikenal oleh versi aplikasi ini", s.Versi, s.Nama)
+		}
+		if m.Checksum != s.Checksum {
+			return nil, fmt.Errorf("checksum migrasi %d_%s berbeda dengan yang sudah diterapkan", s.Versi, s.Nama)
+		}
+		applied[s.Versi] = s
+	}
+	return applied, nil
+}
+
+func applyMigration(ctx context.Context, db *gorm.DB, m Migration) (bool, error) {
+	tx := db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+		return false, err
+	}
+	// proses lain mungkin sudah menerapkan migrasi ini selama menunggu lock
+	var count int64
+	if err := tx.Model(&entity.SchemaMigration{}).Where("versi = ?", m.Versi).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if err := tx.Exec(m.Up).Error; err != nil {
+		return false, err
+	}
+	schemaMigration := entity.SchemaMigration{
+		Versi:    m.Versi,
+		Nama:     m.Nama,
+		Checksum: m.Checksum,
+		Waktu:    time.Now().Unix(),
+	}
+	if err := tx.Create(&schemaMigration).Error; err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func revertMigration(ctx context.Context, db *gorm.DB, m Migration) error {
+	tx := db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+		return err
+	}
+	result := tx.Where("versi = ?", m.Versi).Delete(&entity.SchemaMigration{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("migrasi sudah dibatalkan oleh proses lain")
+	}
+
+	if err := tx.Exec(m.Down).Error; err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS file;
+DROP TABLE IF EXISTS artikel;
+DROP TABLE IF EXISTS pengambilan_obat;
+DROP TABLE IF EXISTS kontrol_balik;
+DROP TABLE IF EXISTS obat;
+DROP TABLE IF EXISTS pasien;
+DROP TABLE IF EXISTS pengguna;
+DROP TABLE IF EXISTS admin_apotek;
+DROP TABLE IF EXISTS admin_puskesmas;
+DROP TABLE IF EXISTS admin_super;
+
+DROP TYPE IF EXISTS status_kontrol_balik_enum;
+DROP TYPE IF EXISTS status_pengambilan_obat_enum;
+DROP TYPE IF EXISTS status_pasien_enum;
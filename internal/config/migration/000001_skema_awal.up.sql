@@ -0,0 +1,117 @@
+-- skema sebelum migrasi berversi, aman dijalankan pada database yang dibuat oleh auto migrate
+DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'status_pasien_enum') THEN CREATE TYPE status_pasien_enum AS ENUM ('aktif', 'selesai'); END IF; END $$;
+DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'status_pengambilan_obat_enum') THEN CREATE TYPE status_pengambilan_obat_enum AS ENUM ('menunggu', 'diambil', 'batal'); END IF; END $$;
+DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'status_kontrol_balik_enum') THEN CREATE TYPE status_kontrol_balik_enum AS ENUM ('menunggu', 'selesai', 'batal'); END IF; END $$;
+
+CREATE TABLE IF NOT EXISTS admin_super
+(
+    id       SERIAL PRIMARY KEY,
+    username varchar(50)  NOT NULL UNIQUE,
+    password varchar(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS admin_puskesmas
+(
+    id                SERIAL PRIMARY KEY,
+    nama_puskesmas    varchar(100)  NOT NULL,
+    telepon           varchar(16)   NOT NULL UNIQUE,
+    alamat            varchar(1000) NOT NULL,
+    waktu_operasional varchar(1000) NOT NULL,
+    username          varchar(50)   NOT NULL UNIQUE,
+    password          varchar(255)  NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS admin_apotek
+(
+    id                SERIAL PRIMARY KEY,
+    nama_apotek       varchar(100)  NOT NULL,
+    telepon           varchar(16)   NOT NULL UNIQUE,
+    alamat            varchar(1000) NOT NULL,
+    waktu_operasional varchar(1000) NOT NULL,
+    username          varchar(50)   NOT NULL UNIQUE,
+    password          varchar(255)  NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pengguna
+(
+    id               SERIAL PRIMARY KEY,
+    token_perangkat  varchar(255),
+    nama_lengkap     varchar(100) NOT NULL,
+    telepon          varchar(16)  NOT NULL UNIQUE,
+    telepon_keluarga varchar(16)  NOT NULL,
+    alamat           varchar(500) NOT NULL,
+    username         varchar(50)  NOT NULL UNIQUE,
+    password         varchar(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pasien
+(
+    id                 SERIAL PRIMARY KEY,
+    no_rekam_medis     varchar(50)        NOT NULL,
+    id_pengguna        integer            NOT NULL,
+    id_admin_puskesmas integer            NOT NULL,
+    tanggal_daftar     bigint             NOT NULL,
+    status             status_pasien_enum NOT NULL,
+    CONSTRAINT fk_pasien_pengguna FOREIGN KEY (id_pengguna) REFERENCES pengguna (id),
+    CONSTRAINT fk_pasien_admin_puskesmas FOREIGN KEY (id_admin_puskesmas) REFERENCES admin_puskesmas (id)
+);
+
+CREATE TABLE IF NOT EXISTS obat
+(
+    id              SERIAL PRIMARY KEY,
+    nama_obat       varchar(100) NOT NULL,
+    jumlah          integer      NOT NULL,
+    id_admin_apotek integer      NOT NULL,
+    CONSTRAINT fk_obat_admin_apotek FOREIGN KEY (id_admin_apotek) REFERENCES admin_apotek (id)
+);
+
+CREATE TABLE IF NOT EXISTS kontrol_balik
+(
+    id              SERIAL PRIMARY KEY,
+    no_antrean      integer                   NOT NULL,
+    id_pasien       integer                   NOT NULL,
+    keluhan         text,
+    berat_badan     integer,
+    tinggi_badan    integer,
+    tekanan_darah   varchar(20),
+    denyut_nadi     integer,
+    hasil_lab       text,
+    hasil_ekg       text,
+    hasil_diagnosa  text,
+    tanggal_kontrol bigint                    NOT NULL,
+    status          status_kontrol_balik_enum NOT NULL,
+    CONSTRAINT fk_kontrol_balik_pasien FOREIGN KEY (id_pasien) REFERENCES pasien (id)
+);
+
+CREATE TABLE IF NOT EXISTS pengambilan_obat
+(
+    id                  SERIAL PRIMARY KEY,
+    resi                varchar(50)                  NOT NULL,
+    id_pasien           integer                      NOT NULL,
+    id_obat             integer                      NOT NULL,
+    jumlah              integer                      NOT NULL,
+    tanggal_pengambilan bigint                       NOT NULL,
+    status              status_pengambilan_obat_enum NOT NULL,
+    CONSTRAINT fk_pengambilan_obat_pasien FOREIGN KEY (id_pasien) REFERENCES pasien (id),
+    CONSTRAINT fk_pengambilan_obat_obat FOREIGN KEY (id_obat) REFERENCES obat (id)
+);
+
+CREATE TABLE IF NOT EXISTS artikel
+(
+    id                 SERIAL PRIMARY KEY,
+    id_admin_puskesmas integer       NOT NULL,
+    judul              varchar(255)  NOT NULL,
+    ringkasan          varchar(1000) NOT NULL,
+    isi                text          NOT NULL,
+    tanggal_publikasi  bigint        NOT NULL,
+    banner             varchar(100),
+    CONSTRAINT fk_artikel_admin_puskesmas FOREIGN KEY (id_admin_puskesmas) REFERENCES admin_puskesmas (id)
+);
+
+CREATE TABLE IF NOT EXISTS file
+(
+    id         SERIAL PRIMARY KEY,
+    id_artikel integer NOT NULL,
+    file       varchar(100),
+    CONSTRAINT fk_file_artikel FOREIGN KEY (id_artikel) REFERENCES artikel (id)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS antrean_kontrol_balik;
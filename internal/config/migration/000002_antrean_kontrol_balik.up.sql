@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS antrean_kontrol_balik
+(
+    id_admin_puskesmas  integer NOT NULL,
+    tanggal_kontrol     bigint  NOT NULL,
+    no_antrean_terakhir integer NOT NULL,
+    PRIMARY KEY (id_admin_puskesmas, tanggal_kontrol)
+);
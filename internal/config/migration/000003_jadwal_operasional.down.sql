@@ -0,0 +1,3 @@
+ALTER TABLE kontrol_balik DROP COLUMN IF EXISTS waktu_kontrol;
+
+DROP TABLE IF EXISTS jadwal_operasional;
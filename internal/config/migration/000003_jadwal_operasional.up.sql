@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS jadwal_operasional
+(
+    id                 SERIAL PRIMARY KEY,
+    id_admin_puskesmas integer    NOT NULL,
+    hari               smallint   NOT NULL,
+    jam_buka           varchar(5) NOT NULL,
+    jam_tutup          varchar(5) NOT NULL,
+    durasi_slot        integer    NOT NULL,
+    kuota_slot         integer    NOT NULL,
+    kuota_harian       integer    NOT NULL,
+    CONSTRAINT fk_jadwal_operasional_admin_puskesmas FOREIGN KEY (id_admin_puskesmas) REFERENCES admin_puskesmas (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jadwal_operasional_hari ON jadwal_operasional (id_admin_puskesmas, hari);
+
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS waktu_kontrol bigint;
@@ -0,0 +1,17 @@
+-- resi dengan lebih dari satu obat hanya menyimpan item pertama
+ALTER TABLE pengambilan_obat ADD COLUMN id_obat integer;
+ALTER TABLE pengambilan_obat ADD COLUMN jumlah integer;
+UPDATE pengambilan_obat
+SET id_obat = item.id_obat,
+    jumlah  = item.jumlah
+FROM (SELECT DISTINCT ON (id_pengambilan_obat) id_pengambilan_obat, id_obat, jumlah
+      FROM pengambilan_obat_item
+      ORDER BY id_pengambilan_obat, id) item
+WHERE item.id_pengambilan_obat = pengambilan_obat.id;
+DELETE FROM pengambilan_obat WHERE id_obat IS NULL;
+ALTER TABLE pengambilan_obat ALTER COLUMN id_obat SET NOT NULL;
+ALTER TABLE pengambilan_obat ALTER COLUMN jumlah SET NOT NULL;
+ALTER TABLE pengambilan_obat ADD CONSTRAINT fk_pengambilan_obat_obat FOREIGN KEY (id_obat) REFERENCES obat (id);
+ALTER TABLE pengambilan_obat DROP COLUMN id_admin_apotek;
+
+DROP TABLE IF EXISTS pengambilan_obat_item;
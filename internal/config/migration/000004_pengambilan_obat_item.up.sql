@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS pengambilan_obat_item
+(
+    id                  SERIAL PRIMARY KEY,
+    id_pengambilan_obat integer NOT NULL,
+    id_obat             integer NOT NULL,
+    jumlah              integer NOT NULL,
+    CONSTRAINT fk_pengambilan_obat_item FOREIGN KEY (id_pengambilan_obat) REFERENCES pengambilan_obat (id),
+    CONSTRAINT fk_pengambilan_obat_item_obat FOREIGN KEY (id_obat) REFERENCES obat (id)
+);
+CREATE INDEX IF NOT EXISTS idx_pengambilan_obat_item_id_pengambilan_obat ON pengambilan_obat_item (id_pengambilan_obat);
+
+-- pindahkan obat pada pengambilan obat lama ke tabel item
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'pengambilan_obat' AND column_name = 'id_obat') THEN
+        INSERT INTO pengambilan_obat_item (id_pengambilan_obat, id_obat, jumlah)
+        SELECT id, id_obat, jumlah FROM pengambilan_obat;
+        ALTER TABLE pengambilan_obat ADD COLUMN IF NOT EXISTS id_admin_apotek integer;
+        UPDATE pengambilan_obat SET id_admin_apotek = obat.id_admin_apotek FROM obat WHERE obat.id = pengambilan_obat.id_obat;
+        ALTER TABLE pengambilan_obat DROP COLUMN id_obat, DROP COLUMN jumlah;
+    END IF;
+END $$;
+
+ALTER TABLE pengambilan_obat ADD COLUMN IF NOT EXISTS id_admin_apotek integer;
+ALTER TABLE pengambilan_obat ALTER COLUMN id_admin_apotek SET NOT NULL;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_pengambilan_obat_admin_apotek') THEN
+        ALTER TABLE pengambilan_obat ADD CONSTRAINT fk_pengambilan_obat_admin_apotek FOREIGN KEY (id_admin_apotek) REFERENCES admin_apotek (id);
+    END IF;
+END $$;
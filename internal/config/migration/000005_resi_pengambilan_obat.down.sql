@@ -0,0 +1,2 @@
+-- resi lama yang sudah diganti tidak dapat dikembalikan
+DROP INDEX IF EXISTS idx_pengambilan_obat_resi;
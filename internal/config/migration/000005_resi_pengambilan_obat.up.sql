@@ -0,0 +1,54 @@
+-- sama dengan adapter.ResiAdapter: 9 karakter crockford base32 ditambah check digit luhn mod 32
+CREATE FUNCTION pg_temp.resi_check_digit(kode text) RETURNS integer AS $$
+DECLARE
+    alfabet CONSTANT text := '0123456789ABCDEFGHJKMNPQRSTVWXYZ';
+    faktor  integer := 2;
+    total   integer := 0;
+    nilai   integer;
+BEGIN
+    FOR i IN REVERSE length(kode)..1 LOOP
+        nilai := (strpos(alfabet, substr(kode, i, 1)) - 1) * faktor;
+        total := total + nilai / 32 + nilai % 32;
+        faktor := CASE WHEN faktor = 2 THEN 1 ELSE 2 END;
+    END LOOP;
+    RETURN (32 - total % 32) % 32;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION pg_temp.resi_valid(resi text) RETURNS boolean AS $$
+BEGIN
+    RETURN resi ~ '^[0-9A-HJKMNP-TV-Z]{10}$'
+        AND substr(resi, 10, 1) = substr('0123456789ABCDEFGHJKMNPQRSTVWXYZ', pg_temp.resi_check_digit(left(resi, 9)) + 1, 1);
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION pg_temp.resi_generate() RETURNS text AS $$
+DECLARE
+    alfabet CONSTANT text := '0123456789ABCDEFGHJKMNPQRSTVWXYZ';
+    acak    bytea := uuid_send(gen_random_uuid());
+    kode    text := '';
+BEGIN
+    FOR i IN 0..8 LOOP
+        kode := kode || substr(alfabet, get_byte(acak, i) % 32 + 1, 1);
+    END LOOP;
+    RETURN kode || substr(alfabet, pg_temp.resi_check_digit(kode) + 1, 1);
+END
+$$ LANGUAGE plpgsql;
+
+-- ganti resi lama yang tidak memiliki check digit atau terduplikasi sebelum unique index dibuat
+DO $$
+DECLARE
+    p         record;
+    resi_baru text;
+BEGIN
+    FOR p IN SELECT id FROM (SELECT id, resi, row_number() OVER (PARTITION BY resi ORDER BY id) AS urutan FROM pengambilan_obat) t
+             WHERE urutan > 1 OR NOT pg_temp.resi_valid(t.resi) LOOP
+        LOOP
+            resi_baru := pg_temp.resi_generate();
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM pengambilan_obat WHERE pengambilan_obat.resi = resi_baru);
+        END LOOP;
+        UPDATE pengambilan_obat SET resi = resi_baru WHERE id = p.id;
+    END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pengambilan_obat_resi ON pengambilan_obat (resi);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mutasi_obat;
+
+DROP TYPE IF EXISTS alasan_mutasi_obat_enum;
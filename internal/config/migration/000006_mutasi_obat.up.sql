@@ -0,0 +1,23 @@
+DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'alasan_mutasi_obat_enum') THEN CREATE TYPE alasan_mutasi_obat_enum AS ENUM ('restok', 'reservasi', 'pembatalan', 'koreksi'); END IF; END $$;
+
+CREATE TABLE IF NOT EXISTS mutasi_obat
+(
+    id                  SERIAL PRIMARY KEY,
+    id_obat             integer                 NOT NULL,
+    id_pengambilan_obat integer,
+    perubahan           integer                 NOT NULL,
+    jumlah_akhir        integer                 NOT NULL,
+    alasan              alasan_mutasi_obat_enum NOT NULL,
+    id_aktor            integer,
+    role_aktor          varchar(20),
+    waktu               bigint                  NOT NULL,
+    CONSTRAINT fk_mutasi_obat_obat FOREIGN KEY (id_obat) REFERENCES obat (id)
+);
+CREATE INDEX IF NOT EXISTS idx_mutasi_obat_id_obat ON mutasi_obat (id_obat);
+CREATE INDEX IF NOT EXISTS idx_mutasi_obat_id_pengambilan_obat ON mutasi_obat (id_pengambilan_obat);
+
+-- catat saldo awal obat yang belum memiliki mutasi
+INSERT INTO mutasi_obat (id_obat, perubahan, jumlah_akhir, alasan, waktu)
+SELECT obat.id, obat.jumlah, obat.jumlah, 'koreksi', EXTRACT(EPOCH FROM NOW())::bigint
+FROM obat
+WHERE NOT EXISTS (SELECT 1 FROM mutasi_obat WHERE mutasi_obat.id_obat = obat.id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alokasi_batch_obat;
+DROP TABLE IF EXISTS batch_obat;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS batch_obat
+(
+    id                  SERIAL PRIMARY KEY,
+    id_obat             integer     NOT NULL,
+    no_batch            varchar(50) NOT NULL,
+    tanggal_kedaluwarsa bigint,
+    jumlah_diterima     integer     NOT NULL,
+    sisa                integer     NOT NULL,
+    tanggal_diterima    bigint      NOT NULL,
+    CONSTRAINT fk_batch_obat_obat FOREIGN KEY (id_obat) REFERENCES obat (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_obat_no_batch ON batch_obat (id_obat, no_batch);
+CREATE INDEX IF NOT EXISTS idx_batch_obat_tanggal_kedaluwarsa ON batch_obat (tanggal_kedaluwarsa);
+
+CREATE TABLE IF NOT EXISTS alokasi_batch_obat
+(
+    id                       SERIAL PRIMARY KEY,
+    id_pengambilan_obat_item integer NOT NULL,
+    id_batch_obat            integer NOT NULL,
+    jumlah                   integer NOT NULL,
+    CONSTRAINT fk_alokasi_batch_obat_batch_obat FOREIGN KEY (id_batch_obat) REFERENCES batch_obat (id)
+);
+CREATE INDEX IF NOT EXISTS idx_alokasi_batch_obat_id_pengambilan_obat_item ON alokasi_batch_obat (id_pengambilan_obat_item);
+CREATE INDEX IF NOT EXISTS idx_alokasi_batch_obat_id_batch_obat ON alokasi_batch_obat (id_batch_obat);
+
+-- stok yang belum tercatat pada batch dimasukkan ke batch tanpa nomor
+INSERT INTO batch_obat (id_obat, no_batch, jumlah_diterima, sisa, tanggal_diterima)
+SELECT obat.id,
+       '-',
+       obat.jumlah - COALESCE(SUM(batch_obat.sisa), 0),
+       obat.jumlah - COALESCE(SUM(batch_obat.sisa), 0),
+       EXTRACT(EPOCH FROM NOW())::bigint
+FROM obat
+         LEFT JOIN batch_obat ON batch_obat.id_obat = obat.id
+WHERE NOT EXISTS (SELECT 1 FROM batch_obat b WHERE b.id_obat = obat.id AND b.no_batch = '-')
+GROUP BY obat.id
+HAVING obat.jumlah > COALESCE(SUM(batch_obat.sisa), 0);
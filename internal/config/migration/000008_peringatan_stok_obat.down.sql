@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS peringatan_stok_obat_puskesmas;
+DROP TABLE IF EXISTS peringatan_stok_obat;
+
+ALTER TABLE obat DROP COLUMN IF EXISTS stok_minimum;
@@ -0,0 +1,20 @@
+ALTER TABLE obat ADD COLUMN IF NOT EXISTS stok_minimum integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS peringatan_stok_obat
+(
+    id           SERIAL PRIMARY KEY,
+    id_obat      integer NOT NULL,
+    jumlah       integer NOT NULL,
+    stok_minimum integer NOT NULL,
+    waktu        bigint  NOT NULL,
+    CONSTRAINT fk_peringatan_stok_obat_obat FOREIGN KEY (id_obat) REFERENCES obat (id)
+);
+CREATE INDEX IF NOT EXISTS idx_peringatan_stok_obat_id_obat ON peringatan_stok_obat (id_obat);
+
+CREATE TABLE IF NOT EXISTS peringatan_stok_obat_puskesmas
+(
+    id_peringatan_stok_obat integer NOT NULL,
+    id_admin_puskesmas      integer NOT NULL,
+    PRIMARY KEY (id_peringatan_stok_obat, id_admin_puskesmas)
+);
+CREATE INDEX IF NOT EXISTS idx_peringatan_stok_obat_puskesmas_id_admin_puskesmas ON peringatan_stok_obat_puskesmas (id_admin_puskesmas);
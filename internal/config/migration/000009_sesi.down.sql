@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS sesi;
+
+ALTER TABLE pengguna DROP COLUMN IF EXISTS versi_token;
+ALTER TABLE admin_apotek DROP COLUMN IF EXISTS versi_token;
+ALTER TABLE admin_puskesmas DROP COLUMN IF EXISTS versi_token;
+ALTER TABLE admin_super DROP COLUMN IF EXISTS versi_token;
@@ -0,0 +1,28 @@
+ALTER TABLE admin_super ADD COLUMN IF NOT EXISTS versi_token integer NOT NULL DEFAULT 1;
+ALTER TABLE admin_puskesmas ADD COLUMN IF NOT EXISTS versi_token integer NOT NULL DEFAULT 1;
+ALTER TABLE admin_apotek ADD COLUMN IF NOT EXISTS versi_token integer NOT NULL DEFAULT 1;
+ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS versi_token integer NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS sesi
+(
+    id          varchar(32) PRIMARY KEY,
+    id_akun     integer     NOT NULL,
+    role        varchar(20) NOT NULL,
+    versi_token integer     NOT NULL,
+    dibuat      bigint      NOT NULL,
+    kedaluwarsa bigint      NOT NULL,
+    dicabut     bigint
+);
+CREATE INDEX IF NOT EXISTS idx_sesi_akun ON sesi (id_akun, role);
+
+CREATE TABLE IF NOT EXISTS refresh_token
+(
+    id        SERIAL PRIMARY KEY,
+    id_sesi   varchar(32) NOT NULL,
+    hash      varchar(64) NOT NULL,
+    dibuat    bigint      NOT NULL,
+    digunakan bigint,
+    CONSTRAINT fk_refresh_token_sesi FOREIGN KEY (id_sesi) REFERENCES sesi (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_token_hash ON refresh_token (hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_id_sesi ON refresh_token (id_sesi);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS percobaan_login;
+DROP TYPE IF EXISTS status_percobaan_login_enum;
@@ -0,0 +1,15 @@
+DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'status_percobaan_login_enum') THEN CREATE TYPE status_percobaan_login_enum AS ENUM ('berhasil', 'gagal', 'ditolak', 'dibuka'); END IF; END $$;
+
+CREATE TABLE IF NOT EXISTS percobaan_login
+(
+    id       SERIAL PRIMARY KEY,
+    role     varchar(20),
+    username varchar(50),
+    ip       varchar(45),
+    status   status_percobaan_login_enum NOT NULL,
+    id_aktor integer,
+    waktu    bigint                      NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_percobaan_login_username ON percobaan_login (role, username, id);
+CREATE INDEX IF NOT EXISTS idx_percobaan_login_ip ON percobaan_login (ip, id);
+CREATE INDEX IF NOT EXISTS idx_percobaan_login_waktu ON percobaan_login (waktu);
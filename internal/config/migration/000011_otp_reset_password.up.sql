@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS otp_reset_password
+(
+    id               SERIAL PRIMARY KEY,
+    id_pengguna      integer      NOT NULL,
+    hash_otp         varchar(64)  NOT NULL,
+    percobaan        integer      NOT NULL DEFAULT 0,
+    dibuat           bigint       NOT NULL,
+    kedaluwarsa      bigint       NOT NULL,
+    terverifikasi    bigint,
+    hash_token_reset varchar(64),
+    digunakan        bigint,
+    CONSTRAINT fk_otp_reset_password_pengguna FOREIGN KEY (id_pengguna) REFERENCES pengguna (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_otp_reset_password_id_pengguna ON otp_reset_password (id_pengguna, dibuat);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_otp_reset_password_hash_token_reset ON otp_reset_password (hash_token_reset);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tantangan_dua_faktor;
+DROP TABLE IF EXISTS kode_cadangan;
+DROP TABLE IF EXISTS dua_faktor;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS dua_faktor
+(
+    id               SERIAL PRIMARY KEY,
+    id_akun          integer     NOT NULL,
+    role             varchar(20) NOT NULL,
+    secret           varchar(64) NOT NULL,
+    langkah_terakhir bigint      NOT NULL DEFAULT 0,
+    dibuat           bigint      NOT NULL,
+    aktif            bigint
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dua_faktor_akun ON dua_faktor (id_akun, role);
+
+CREATE TABLE IF NOT EXISTS kode_cadangan
+(
+    id            SERIAL PRIMARY KEY,
+    id_dua_faktor integer     NOT NULL,
+    hash          varchar(64) NOT NULL,
+    digunakan     bigint,
+    CONSTRAINT fk_kode_cadangan_dua_faktor FOREIGN KEY (id_dua_faktor) REFERENCES dua_faktor (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_kode_cadangan_id_dua_faktor ON kode_cadangan (id_dua_faktor);
+
+CREATE TABLE IF NOT EXISTS tantangan_dua_faktor
+(
+    id          SERIAL PRIMARY KEY,
+    hash        varchar(64) NOT NULL,
+    id_akun     integer     NOT NULL,
+    role        varchar(20) NOT NULL,
+    username    varchar(50) NOT NULL,
+    versi_token integer     NOT NULL,
+    percobaan   integer     NOT NULL DEFAULT 0,
+    dibuat      bigint      NOT NULL,
+    kedaluwarsa bigint      NOT NULL,
+    digunakan   bigint
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tantangan_dua_faktor_hash ON tantangan_dua_faktor (hash);
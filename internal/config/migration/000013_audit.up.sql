@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS audit
+(
+    id         SERIAL PRIMARY KEY,
+    id_aktor   integer,
+    role_aktor varchar(20),
+    aksi       varchar(20) NOT NULL,
+    entitas    varchar(50) NOT NULL,
+    id_entitas integer     NOT NULL,
+    perubahan  jsonb       NOT NULL DEFAULT '{}',
+    waktu      bigint      NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_audit_entitas ON audit (entitas, id_entitas);
+CREATE INDEX IF NOT EXISTS idx_audit_aktor ON audit (role_aktor, id_aktor);
+CREATE INDEX IF NOT EXISTS idx_audit_waktu ON audit (waktu);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS notifikasi
+(
+    id               SERIAL PRIMARY KEY,
+    id_pengguna      integer      NOT NULL,
+    jenis            varchar(50)  NOT NULL,
+    judul            varchar(255) NOT NULL,
+    isi              text         NOT NULL,
+    data             jsonb        NOT NULL DEFAULT '{}',
+    status           varchar(20)  NOT NULL,
+    percobaan        integer      NOT NULL DEFAULT 0,
+    error            text,
+    dibuat           bigint       NOT NULL,
+    kirim_berikutnya bigint       NOT NULL,
+    terkirim         bigint,
+    CONSTRAINT fk_notifikasi_pengguna FOREIGN KEY (id_pengguna) REFERENCES pengguna (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_notifikasi_antrean ON notifikasi (status, kirim_berikutnya);
+CREATE INDEX IF NOT EXISTS idx_notifikasi_id_pengguna ON notifikasi (id_pengguna);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS riwayat_job;
+
+DROP INDEX IF EXISTS idx_notifikasi_kunci;
+ALTER TABLE notifikasi DROP COLUMN IF EXISTS kunci;
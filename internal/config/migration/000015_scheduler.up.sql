@@ -0,0 +1,14 @@
+ALTER TABLE notifikasi ADD COLUMN IF NOT EXISTS kunci varchar(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifikasi_kunci ON notifikasi (kunci);
+
+CREATE TABLE IF NOT EXISTS riwayat_job
+(
+    id      SERIAL PRIMARY KEY,
+    nama    varchar(50) NOT NULL,
+    mulai   bigint      NOT NULL,
+    selesai bigint      NOT NULL,
+    status  varchar(20) NOT NULL,
+    jumlah  integer     NOT NULL DEFAULT 0,
+    error   text
+);
+CREATE INDEX IF NOT EXISTS idx_riwayat_job_nama ON riwayat_job (nama, mulai);
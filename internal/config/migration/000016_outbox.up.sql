@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id               SERIAL PRIMARY KEY,
+    id_event         uuid        NOT NULL,
+    jenis            varchar(50) NOT NULL,
+    agregat          varchar(50) NOT NULL,
+    id_agregat       integer     NOT NULL,
+    data             jsonb       NOT NULL DEFAULT '{}',
+    status           varchar(20) NOT NULL,
+    percobaan        integer     NOT NULL DEFAULT 0,
+    error            text,
+    dibuat           bigint      NOT NULL,
+    kirim_berikutnya bigint      NOT NULL,
+    terkirim         bigint,
+    CONSTRAINT uq_outbox_id_event UNIQUE (id_event)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_antrean ON outbox (status, kirim_berikutnya);
+CREATE INDEX IF NOT EXISTS idx_outbox_agregat ON outbox (agregat, id_agregat);
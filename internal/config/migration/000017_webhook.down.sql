@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pengiriman_webhook;
+DROP TABLE IF EXISTS webhook_event;
+DROP TABLE IF EXISTS webhook;
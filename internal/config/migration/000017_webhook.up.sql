@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS webhook
+(
+    id     SERIAL PRIMARY KEY,
+    nama   varchar(100) NOT NULL,
+    url    varchar(500) NOT NULL,
+    secret varchar(100) NOT NULL,
+    aktif  boolean      NOT NULL DEFAULT true,
+    dibuat bigint       NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_event
+(
+    id_webhook integer     NOT NULL,
+    jenis      varchar(50) NOT NULL,
+    PRIMARY KEY (id_webhook, jenis),
+    CONSTRAINT fk_webhook_event_webhook FOREIGN KEY (id_webhook) REFERENCES webhook (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_event_jenis ON webhook_event (jenis);
+
+CREATE TABLE IF NOT EXISTS pengiriman_webhook
+(
+    id               SERIAL PRIMARY KEY,
+    id_webhook       integer     NOT NULL,
+    id_event         uuid        NOT NULL,
+    jenis            varchar(50) NOT NULL,
+    payload          jsonb       NOT NULL,
+    status           varchar(20) NOT NULL,
+    percobaan        integer     NOT NULL DEFAULT 0,
+    status_http      integer,
+    error            text,
+    dibuat           bigint      NOT NULL,
+    kirim_berikutnya bigint      NOT NULL,
+    terkirim         bigint,
+    CONSTRAINT fk_pengiriman_webhook_webhook FOREIGN KEY (id_webhook) REFERENCES webhook (id) ON DELETE CASCADE,
+    CONSTRAINT uq_pengiriman_webhook_event UNIQUE (id_webhook, id_event)
+);
+CREATE INDEX IF NOT EXISTS idx_pengiriman_webhook_antrean ON pengiriman_webhook (status, kirim_berikutnya);
@@ -0,0 +1,4 @@
+DROP VIEW IF EXISTS timeline_pasien;
+
+DROP INDEX IF EXISTS idx_pengambilan_obat_id_pasien;
+DROP INDEX IF EXISTS idx_kontrol_balik_id_pasien;
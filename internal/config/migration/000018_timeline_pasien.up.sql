@@ -0,0 +1,19 @@
+CREATE INDEX IF NOT EXISTS idx_kontrol_balik_id_pasien ON kontrol_balik (id_pasien, tanggal_kontrol);
+CREATE INDEX IF NOT EXISTS idx_pengambilan_obat_id_pasien ON pengambilan_obat (id_pasien, tanggal_pengambilan);
+
+CREATE OR REPLACE VIEW timeline_pasien AS
+SELECT 'kontrol_balik-' || id AS kunci,
+       'kontrol_balik'        AS jenis,
+       id                     AS id_referensi,
+       id_pasien,
+       tanggal_kontrol        AS tanggal,
+       status::text           AS status
+FROM kontrol_balik
+UNION ALL
+SELECT 'pengambilan_obat-' || id AS kunci,
+       'pengambilan_obat'        AS jenis,
+       id                        AS id_referensi,
+       id_pasien,
+       tanggal_pengambilan       AS tanggal,
+       status::text              AS status
+FROM pengambilan_obat;
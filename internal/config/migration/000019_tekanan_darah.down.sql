@@ -0,0 +1,9 @@
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS tekanan_darah varchar(20);
+
+UPDATE kontrol_balik
+SET tekanan_darah = tekanan_darah_sistolik || '/' || tekanan_darah_diastolik
+WHERE tekanan_darah_sistolik IS NOT NULL
+  AND tekanan_darah_diastolik IS NOT NULL;
+
+ALTER TABLE kontrol_balik DROP COLUMN IF EXISTS tekanan_darah_diastolik;
+ALTER TABLE kontrol_balik DROP COLUMN IF EXISTS tekanan_darah_sistolik;
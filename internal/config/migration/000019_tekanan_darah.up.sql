@@ -0,0 +1,15 @@
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS tekanan_darah_sistolik integer;
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS tekanan_darah_diastolik integer;
+
+-- hanya teks berformat sistolik/diastolik seperti "130/85" atau "130/85 mmHg" yang dipindahkan, polanya sama dengan
+-- constant.PolaTekananDarah. teks yang sudah dipindahkan dikosongkan sehingga kolom lama hanya berisi nilai yang perlu
+-- ditinjau, ditampilkan sebagai tekananDarahLama sampai kontrol balik diubah dengan nilai terstruktur
+UPDATE kontrol_balik
+SET tekanan_darah_sistolik  = (regexp_match(tekanan_darah, '(?i)^\s*(\d{2,3})\s*/\s*(\d{2,3})\s*(mmhg)?\s*$'))[1]::integer,
+    tekanan_darah_diastolik = (regexp_match(tekanan_darah, '(?i)^\s*(\d{2,3})\s*/\s*(\d{2,3})\s*(mmhg)?\s*$'))[2]::integer,
+    tekanan_darah           = NULL
+WHERE tekanan_darah ~ '(?i)^\s*(\d{2,3})\s*/\s*(\d{2,3})\s*(mmhg)?\s*$';
+
+UPDATE kontrol_balik
+SET tekanan_darah = NULL
+WHERE trim(tekanan_darah) = '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS hasil_pemeriksaan_lab;
+DROP TABLE IF EXISTS pemeriksaan_lab;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS pemeriksaan_lab
+(
+    id         SERIAL PRIMARY KEY,
+    kode       varchar(30)  NOT NULL,
+    nama       varchar(100) NOT NULL,
+    satuan     varchar(30)  NOT NULL,
+    nilai_min  numeric(10, 2),
+    nilai_maks numeric(10, 2),
+    CONSTRAINT uq_pemeriksaan_lab_kode UNIQUE (kode)
+);
+
+-- rentang rujukan dewasa umum, dapat disesuaikan admin super melalui api
+INSERT INTO pemeriksaan_lab (kode, nama, satuan, nilai_min, nilai_maks)
+VALUES ('HBA1C', 'HbA1c', '%', 4.0, 5.6),
+       ('GDP', 'Glukosa Darah Puasa', 'mg/dL', 70, 99),
+       ('GD2PP', 'Glukosa Darah 2 Jam Post Prandial', 'mg/dL', NULL, 139),
+       ('GDS', 'Glukosa Darah Sewaktu', 'mg/dL', NULL, 199),
+       ('KOLESTEROL_TOTAL', 'Kolesterol Total', 'mg/dL', NULL, 199),
+       ('LDL', 'Kolesterol LDL', 'mg/dL', NULL, 129),
+       ('HDL', 'Kolesterol HDL', 'mg/dL', 40, NULL),
+       ('TRIGLISERIDA', 'Trigliserida', 'mg/dL', NULL, 149),
+       ('KREATININ', 'Kreatinin', 'mg/dL', 0.6, 1.2),
+       ('UREUM', 'Ureum', 'mg/dL', 10, 50),
+       ('ASAM_URAT', 'Asam Urat', 'mg/dL', 3.4, 7.0)
+ON CONFLICT (kode) DO NOTHING;
+
+-- nilai_min dan nilai_maks disalin dari katalog saat hasil disimpan agar flag hasil lama tidak berubah
+CREATE TABLE IF NOT EXISTS hasil_pemeriksaan_lab
+(
+    id                 SERIAL PRIMARY KEY,
+    id_kontrol_balik   integer        NOT NULL,
+    id_pemeriksaan_lab integer        NOT NULL,
+    nilai              numeric(10, 2) NOT NULL,
+    nilai_min          numeric(10, 2),
+    nilai_maks         numeric(10, 2),
+    flag               varchar(20)    NOT NULL,
+    CONSTRAINT fk_hasil_pemeriksaan_lab_kontrol_balik FOREIGN KEY (id_kontrol_balik) REFERENCES kontrol_balik (id) ON DELETE CASCADE,
+    CONSTRAINT fk_hasil_pemeriksaan_lab_pemeriksaan_lab FOREIGN KEY (id_pemeriksaan_lab) REFERENCES pemeriksaan_lab (id),
+    CONSTRAINT uq_hasil_pemeriksaan_lab UNIQUE (id_kontrol_balik, id_pemeriksaan_lab)
+);
+CREATE INDEX IF NOT EXISTS idx_hasil_pemeriksaan_lab_id_pemeriksaan_lab ON hasil_pemeriksaan_lab (id_pemeriksaan_lab);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS uq_kontrol_balik_no_antrean_menunggu;
+
+-- penghitung kembali dikunci per nilai tanggal_kontrol
+DELETE FROM antrean_kontrol_balik;
+INSERT INTO antrean_kontrol_balik (id_admin_puskesmas, tanggal_kontrol, no_antrean_terakhir)
+SELECT id_admin_puskesmas, tanggal_kontrol, max(no_antrean)
+FROM kontrol_balik
+GROUP BY id_admin_puskesmas, tanggal_kontrol;
+
+ALTER TABLE kontrol_balik DROP COLUMN IF EXISTS tanggal_antrean;
+ALTER TABLE kontrol_balik DROP COLUMN IF EXISTS id_admin_puskesmas;
@@ -0,0 +1,50 @@
+-- nomor antrean berlaku per puskesmas per hari, bukan per nilai tanggal_kontrol. date_trunc memakai TimeZone sesi yang
+-- diatur dari ZONA_WAKTU, zona yang sama dengan batasHari di aplikasi
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS id_admin_puskesmas integer;
+ALTER TABLE kontrol_balik ADD COLUMN IF NOT EXISTS tanggal_antrean bigint;
+
+UPDATE kontrol_balik
+SET id_admin_puskesmas = pasien.id_admin_puskesmas,
+    tanggal_antrean    = extract(epoch FROM date_trunc('day', to_timestamp(kontrol_balik.tanggal_kontrol)))::bigint
+FROM pasien
+WHERE pasien.id = kontrol_balik.id_pasien;
+
+-- nomor ganda pada hari yang sama dipindahkan ke belakang antrean hari itu
+UPDATE kontrol_balik
+SET no_antrean = ganda.maks + ganda.urutan
+FROM (SELECT id,
+             maks,
+             row_number() OVER (PARTITION BY id_admin_puskesmas, tanggal_antrean ORDER BY id) AS urutan
+      FROM (SELECT id,
+                   id_admin_puskesmas,
+                   tanggal_antrean,
+                   row_number() OVER (PARTITION BY id_admin_puskesmas, tanggal_antrean, no_antrean ORDER BY id) AS ke,
+                   max(no_antrean) OVER (PARTITION BY id_admin_puskesmas, tanggal_antrean)                     AS maks
+            FROM kontrol_balik
+            WHERE status = 'menunggu') antrean
+      WHERE ke > 1) ganda
+WHERE kontrol_balik.id = ganda.id;
+
+ALTER TABLE kontrol_balik ALTER COLUMN id_admin_puskesmas SET NOT NULL;
+ALTER TABLE kontrol_balik ALTER COLUMN tanggal_antrean SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_kontrol_balik_no_antrean_menunggu
+    ON kontrol_balik (id_admin_puskesmas, tanggal_antrean, no_antrean)
+    WHERE status = 'menunggu';
+
+-- penghitung antrean kini disimpan per awal hari
+CREATE TEMPORARY TABLE antrean_kontrol_balik_harian ON COMMIT DROP AS
+SELECT id_admin_puskesmas, tanggal_kontrol, max(no_antrean_terakhir) AS no_antrean_terakhir
+FROM (SELECT id_admin_puskesmas,
+             extract(epoch FROM date_trunc('day', to_timestamp(tanggal_kontrol)))::bigint AS tanggal_kontrol,
+             no_antrean_terakhir
+      FROM antrean_kontrol_balik
+      UNION ALL
+      SELECT id_admin_puskesmas, tanggal_antrean, no_antrean
+      FROM kontrol_balik) antrean
+GROUP BY id_admin_puskesmas, tanggal_kontrol;
+
+DELETE FROM antrean_kontrol_balik;
+INSERT INTO antrean_kontrol_balik (id_admin_puskesmas, tanggal_kontrol, no_antrean_terakhir)
+SELECT id_admin_puskesmas, tanggal_kontrol, no_antrean_terakhir
+FROM antrean_kontrol_balik_harian;
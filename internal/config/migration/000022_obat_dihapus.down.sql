@@ -0,0 +1 @@
+ALTER TABLE obat DROP COLUMN IF EXISTS dihapus;
@@ -0,0 +1,2 @@
+-- obat yang dihapus tetap disimpan agar riwayat mutasi stok tidak hilang
+ALTER TABLE obat ADD COLUMN IF NOT EXISTS dihapus bigint;
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+// kunci advisory lock untuk memilih satu instance yang menjalankan job
+const schedulerLock = 7_105_002
+
+type Job struct {
+	Nama     string
+	Interval time.Duration
+	// mengembalikan jumlah data yang diproses
+	Jalankan func(ctx context.Context) (int, error)
+}
+
+type Scheduler struct {
+	DB                   *gorm.DB
+	RiwayatJobRepository *repository.RiwayatJobRepository
+	Jobs                 []Job
+	Config               *viper.Viper
+}
+
+func NewScheduler(db *gorm.DB, riwayatJobRepository *repository.RiwayatJobRepository, config *viper.Viper, jobs ...Job) *Scheduler {
+	return &Scheduler{db, riwayatJobRepository, jobs, config}
+}
+
+// setiap instance mencoba menjadi leader secara berkala. leader memegang session advisory lock pada satu koneksi
+// khusus, sehingga lock otomatis lepas dan diambil alih instance lain bila leader mati atau koneksinya putus
+func (s *Scheduler) Jalankan(ctx context.Context) {
+	if s.Config.IsSet("scheduler.aktif") && !s.Config.GetBool("scheduler.aktif") {
+		slog.Warn("Scheduler is disabled")
+		return
+	}
+
+	interval := time.Duration(s.Config.GetInt("scheduler.interval")) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	for {
+		if err := s.pimpin(ctx, interval); err != nil {
+			slog.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *Scheduler) pimpin(ctx context.Context, interval time.Duration) error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var leader bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", schedulerLock).Scan(&leader); err != nil {
+		return err
+	}
+	if !leader {
+		return nil
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", schedulerLock)
+	slog.Info("Scheduler leadership acquired")
+
+	terakhir := make(map[string]time.Time)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// lock ikut hilang bersama koneksinya, jadi koneksi yang putus berarti bukan leader lagi
+		if err := conn.PingContext(ctx); err != nil {
+			slog.Warn("Scheduler leadership lost")
+			return err
+		}
+		for _, job := range s.Jobs {
+			if time.Since(terakhir[job.Nama]) < job.Interval {
+				continue
+			}
+			terakhir[job.Nama] = time.Now()
+			s.jalankan(ctx, job)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) jalankan(ctx context.Context, job Job) {
+	riwayat := &entity.RiwayatJob{Nama: job.Nama, Mulai: time.Now().Unix(), Status: constant.StatusRiwayatJobBerhasil}
+	jumlah, err := job.Jalankan(ctx)
+	riwayat.Selesai = time.Now().Unix()
+	riwayat.Jumlah = int32(jumlah)
+	if err != nil {
+		slog.Error("Job failed", "job", job.Nama, "error", err.Error())
+		riwayat.Status = constant.StatusRiwayatJobGagal
+		pesan := err.Error()
+		riwayat.Error = &pesan
+	}
+	if err := s.RiwayatJobRepository.Create(s.DB.WithContext(ctx), riwayat); err != nil {
+		slog.Error(err.Error())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"mime/multipart"
 	"path/filepath"
+	"prb_care_api/internal/constant"
 	"regexp"
 	"strconv"
 	"strings"
@@ -17,6 +18,7 @@ import (
 
 func NewValidator() *validator.Validate {
 	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(namaField)
 	if err := v.RegisterValidation("not_contain_space", ValidateNotContainSpace); err != nil {
 		log.Fatalln(err)
 	}
@@ -26,6 +28,9 @@ func NewValidator() *validator.Validate {
 	if err := v.RegisterValidation("image", ValidateImage); err != nil {
 		log.Fatalln(err)
 	}
+	if err := v.RegisterValidation("tekanan_darah", ValidateTekananDarah); err != nil {
+		log.Fatalln(err)
+	}
 	return v
 }
 
@@ -43,6 +48,11 @@ func ValidatePasswordFormat(fl validator.FieldLevel) bool {
 	return hasLower && hasUpper && hasNumber && hasSpecial
 }
 
+// sistolik/diastolik seperti "130/85" atau "130/85 mmHg"
+func ValidateTekananDarah(fl validator.FieldLevel) bool {
+	return constant.PolaTekananDarah.MatchString(fl.Field().String())
+}
+
 func ValidateImage(fl validator.FieldLevel) bool {
 	file := fl.Field().Interface().(multipart.FileHeader)
 
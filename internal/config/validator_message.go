@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"prb_care_api/internal/model"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+const (
+	bahasaIndonesia = "id"
+	bahasaInggris   = "en"
+)
+
+// pesan per rule dalam bahasa indonesia dan inggris. %[1]s diisi nama field dan %[2]s diisi param rule
+var pesanRule = map[string]map[string]string{
+	"required": {
+		bahasaIndonesia: "%[1]s wajib diisi",
+		bahasaInggris:   "%[1]s is required",
+	},
+	"required_with": {
+		bahasaIndonesia: "%[1]s wajib diisi bila %[2]s diisi",
+		bahasaInggris:   "%[1]s is required when %[2]s is present",
+	},
+	"required_without": {
+		bahasaIndonesia: "%[1]s wajib diisi bila %[2]s kosong",
+		bahasaInggris:   "%[1]s is required when %[2]s is empty",
+	},
+	"excluded_with": {
+		bahasaIndonesia: "%[1]s tidak boleh diisi bersama %[2]s",
+		bahasaInggris:   "%[1]s must not be present together with %[2]s",
+	},
+	"numeric": {
+		bahasaIndonesia: "%[1]s harus berupa angka",
+		bahasaInggris:   "%[1]s must be a number",
+	},
+	"gt": {
+		bahasaIndonesia: "%[1]s harus lebih dari %[2]s",
+		bahasaInggris:   "%[1]s must be greater than %[2]s",
+	},
+	"gte": {
+		bahasaIndonesia: "%[1]s paling kecil %[2]s",
+		bahasaInggris:   "%[1]s must be at least %[2]s",
+	},
+	"lt": {
+		bahasaIndonesia: "%[1]s harus kurang dari %[2]s",
+		bahasaInggris:   "%[1]s must be less than %[2]s",
+	},
+	"lte": {
+		bahasaIndonesia: "%[1]s paling besar %[2]s",
+		bahasaInggris:   "%[1]s must be at most %[2]s",
+	},
+	"gtfield": {
+		bahasaIndonesia: "%[1]s harus lebih dari %[2]s",
+		bahasaInggris:   "%[1]s must be greater than %[2]s",
+	},
+	"gtefield": {
+		bahasaIndonesia: "%[1]s tidak boleh kurang dari %[2]s",
+		bahasaInggris:   "%[1]s must not be less than %[2]s",
+	},
+	"eqfield": {
+		bahasaIndonesia: "%[1]s harus sama dengan %[2]s",
+		bahasaInggris:   "%[1]s must match %[2]s",
+	},
+	"oneof": {
+		bahasaIndonesia: "%[1]s harus salah satu dari: %[2]s",
+		bahasaInggris:   "%[1]s must be one of: %[2]s",
+	},
+	"unique": {
+		bahasaIndonesia: "%[1]s tidak boleh berisi nilai yang sama",
+		bahasaInggris:   "%[1]s must not contain duplicate values",
+	},
+	"http_url": {
+		bahasaIndonesia: "%[1]s harus berupa URL http atau https yang valid",
+		bahasaInggris:   "%[1]s must be a valid http or https URL",
+	},
+	"datetime": {
+		bahasaIndonesia: "%[1]s harus berformat %[2]s",
+		bahasaInggris:   "%[1]s must use the format %[2]s",
+	},
+	"ip": {
+		bahasaIndonesia: "%[1]s harus berupa alamat IP yang valid",
+		bahasaInggris:   "%[1]s must be a valid IP address",
+	},
+	"hexadecimal": {
+		bahasaIndonesia: "%[1]s harus berupa bilangan heksadesimal",
+		bahasaInggris:   "%[1]s must be hexadecimal",
+	},
+	"alphanum": {
+		bahasaIndonesia: "%[1]s hanya boleh berisi huruf dan angka",
+		bahasaInggris:   "%[1]s may only contain letters and numbers",
+	},
+	"not_contain_space": {
+		bahasaIndonesia: "%[1]s tidak boleh mengandung spasi",
+		bahasaInggris:   "%[1]s must not contain spaces",
+	},
+	"is_password_format": {
+		bahasaIndonesia: "%[1]s harus mengandung huruf kecil, huruf besar, angka, dan karakter khusus",
+		bahasaInggris:   "%[1]s must contain a lowercase letter, an uppercase letter, a number, and a special character",
+	},
+	"tekanan_darah": {
+		bahasaIndonesia: "%[1]s harus berformat sistolik/diastolik, misalnya 130/85",
+		bahasaInggris:   "%[1]s must be in systolic/diastolic format, for example 130/85",
+	},
+}
+
+// min, max dan len bergantung pada jenis field: panjang teks, jumlah item, atau nilai angka
+var pesanUkuran = map[string]map[string][3]string{
+	"min": {
+		bahasaIndonesia: {"%[1]s minimal %[2]s karakter", "%[1]s minimal berisi %[2]s item", "%[1]s paling kecil %[2]s"},
+		bahasaInggris:   {"%[1]s must be at least %[2]s characters", "%[1]s must contain at least %[2]s items", "%[1]s must be at least %[2]s"},
+	},
+	"max": {
+		bahasaIndonesia: {"%[1]s maksimal %[2]s karakter", "%[1]s maksimal berisi %[2]s item", "%[1]s paling besar %[2]s"},
+		bahasaInggris:   {"%[1]s must be at most %[2]s characters", "%[1]s must contain at most %[2]s items", "%[1]s must be at most %[2]s"},
+	},
+	"len": {
+		bahasaIndonesia: {"%[1]s harus %[2]s karakter", "%[1]s harus berisi %[2]s item", "%[1]s harus bernilai %[2]s"},
+		bahasaInggris:   {"%[1]s must be exactly %[2]s characters", "%[1]s must contain exactly %[2]s items", "%[1]s must equal %[2]s"},
+	},
+}
+
+// nama field pada pesan kesalahan mengikuti nama yang dikirim klien, bukan nama field struct go
+func namaField(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "form"} {
+		nama, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if nama != "" && nama != "-" {
+			return nama
+		}
+	}
+	return hurufKecilAwal(field.Name)
+}
+
+// singkatan di awal nama ikut dikecilkan, misalnya ID menjadi id dan IdPasien menjadi idPasien
+func hurufKecilAwal(s string) string {
+	r := []rune(s)
+	for i := range r {
+		if !unicode.IsUpper(r[i]) || (i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1])) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+func bahasaValidasi(bahasa string) string {
+	if bahasa == bahasaInggris {
+		return bahasaInggris
+	}
+	return bahasaIndonesia
+}
+
+func detailValidasi(errs validator.ValidationErrors, bahasa string) []model.FieldErrorResponse {
+	bahasa = bahasaValidasi(bahasa)
+	detail := make([]model.FieldErrorResponse, 0, len(errs))
+	for _, fe := range errs {
+		// namespace diawali nama struct request, misalnya PengambilanObatCreateRequest.obat[0].jumlah
+		_, field, ok := strings.Cut(fe.Namespace(), ".")
+		if !ok {
+			field = fe.Field()
+		}
+		detail = append(detail, model.FieldErrorResponse{
+			Field: field,
+			Rule:  fe.Tag(),
+			Param: paramValidasi(fe),
+			Pesan: pesanValidasi(fe, bahasa),
+		})
+	}
+	return detail
+}
+
+func pesanValidasi(fe validator.FieldError, bahasa string) string {
+	bahasa = bahasaValidasi(bahasa)
+	param := paramValidasi(fe)
+	switch fe.Tag() {
+	case "oneof":
+		param = strings.Join(strings.Fields(param), ", ")
+	case "image":
+		return pesanGambar(fe.Field(), param, bahasa)
+	}
+
+	if ukuran, ok := pesanUkuran[fe.Tag()]; ok {
+		i := 2
+		switch fe.Kind() {
+		case reflect.String:
+			i = 0
+		case reflect.Slice, reflect.Array, reflect.Map:
+			i = 1
+		}
+		return fmt.Sprintf(ukuran[bahasa][i], fe.Field(), param)
+	}
+	if pesan, ok := pesanRule[fe.Tag()]; ok {
+		return fmt.Sprintf(pesan[bahasa], fe.Field(), param)
+	}
+	if bahasa == bahasaInggris {
+		return fmt.Sprintf("%s is invalid", fe.Field())
+	}
+	return fmt.Sprintf("%s tidak valid", fe.Field())
+}
+
+// param rule yang merujuk field lain ditulis dengan nama field yang dikirim klien
+func paramValidasi(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "eqfield", "gtfield", "gtefield", "required_with", "required_without", "excluded_with":
+		return hurufKecilAwal(fe.Param())
+	}
+	return fe.Param()
+}
+
+// param rule image berbentuk <lebar>x<tinggi>+<ukuran maksimal dalam kb>, lihat ValidateImage
+func pesanGambar(field string, param string, bahasa string) string {
+	dimensi, ukuran, _ := strings.Cut(param, "+")
+	if bahasa == bahasaInggris {
+		return fmt.Sprintf("%s must be a JPG or PNG image of %s pixels and at most %s KB", field, dimensi, ukuran)
+	}
+	return fmt.Sprintf("%s harus berupa gambar JPG atau PNG berukuran %s piksel dan maksimal %s KB", field, dimensi, ukuran)
+}
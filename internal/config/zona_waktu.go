@@ -0,0 +1,23 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"log"
+	"time"
+	_ "time/tzdata"
+)
+
+// zona waktu aplikasi dan sesi database disamakan agar batas hari antrean, slot, dan pengingat di Go sama dengan
+// date_trunc pada migrasi dan query. zona ini menjadi time.Local sehingga time.Now dan time.Unix ikut memakainya
+func NewZonaWaktu(config *viper.Viper) *time.Location {
+	nama := config.GetString("zona_waktu")
+	if nama == "" {
+		nama = "Asia/Jakarta"
+	}
+	zona, err := time.LoadLocation(nama)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	time.Local = zona
+	return zona
+}
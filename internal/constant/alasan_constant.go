@@ -0,0 +1,8 @@
+package constant
+
+const (
+	AlasanMutasiObatRestok     = "restok"
+	AlasanMutasiObatReservasi  = "reservasi"
+	AlasanMutasiObatPembatalan = "pembatalan"
+	AlasanMutasiObatKoreksi    = "koreksi"
+)
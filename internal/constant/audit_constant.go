@@ -0,0 +1,12 @@
+package constant
+
+const (
+	AksiAuditBuat    = "buat"
+	AksiAuditUbah    = "ubah"
+	AksiAuditHapus   = "hapus"
+	AksiAuditBatal   = "batal"
+	AksiAuditSelesai = "selesai"
+	AksiAuditDiambil = "diambil"
+	AksiAuditRestok  = "restok"
+	AksiAuditKoreksi = "koreksi"
+)
@@ -0,0 +1,4 @@
+package constant
+
+// nomor batch untuk stok tanpa nomor batch, misalnya stok sebelum pencatatan batch
+const NoBatchTanpaNomor = "-"
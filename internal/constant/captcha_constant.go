@@ -0,0 +1,9 @@
+package constant
+
+const (
+	CaptchaTurnstile   = "turnstile"
+	CaptchaHCaptcha    = "hcaptcha"
+	CaptchaRecaptcha   = "recaptcha"
+	CaptchaSelaluLolos = "selalu-lolos"
+	CaptchaSelaluGagal = "selalu-gagal"
+)
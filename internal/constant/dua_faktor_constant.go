@@ -0,0 +1,7 @@
+package constant
+
+// langkah berikutnya yang harus dilakukan setelah password benar pada akun dengan 2fa
+const (
+	DuaFaktorVerifikasi = "verifikasi"
+	DuaFaktorEnrol      = "enrol"
+)
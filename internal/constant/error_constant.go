@@ -0,0 +1,61 @@
+package constant
+
+// kode error dibaca klien sebagai pengganti pesan, nilainya tidak boleh diubah setelah dirilis
+const (
+	KodeErrorBadRequest          = "BAD_REQUEST"
+	KodeErrorUnauthorized        = "UNAUTHORIZED"
+	KodeErrorForbidden           = "FORBIDDEN"
+	KodeErrorNotFound            = "NOT_FOUND"
+	KodeErrorMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	KodeErrorConflict            = "CONFLICT"
+	KodeErrorPayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	KodeErrorTooManyRequests     = "TOO_MANY_REQUESTS"
+	KodeErrorInternalServerError = "INTERNAL_SERVER_ERROR"
+	KodeErrorServiceUnavailable  = "SERVICE_UNAVAILABLE"
+	KodeErrorValidasi            = "VALIDATION_FAILED"
+	KodeErrorParameterHalaman    = "PAGE_PARAMETER_INVALID"
+
+	KodeErrorLoginSalah           = "INVALID_CREDENTIALS"
+	KodeErrorLoginDibatasi        = "LOGIN_RATE_LIMITED"
+	KodeErrorPasswordSaatIniSalah = "CURRENT_PASSWORD_INCORRECT"
+	KodeErrorUsernameDigunakan    = "USERNAME_TAKEN"
+	KodeErrorTeleponDigunakan     = "PHONE_TAKEN"
+
+	KodeErrorDuaFaktorSalah       = "TWO_FACTOR_CODE_INVALID"
+	KodeErrorDuaFaktorAktif       = "TWO_FACTOR_ALREADY_ENABLED"
+	KodeErrorDuaFaktorBelumEnrol  = "TWO_FACTOR_NOT_ENROLLED"
+	KodeErrorDuaFaktorWajib       = "TWO_FACTOR_REQUIRED"
+	KodeErrorOtpSalah             = "OTP_INVALID"
+	KodeErrorTokenResetTidakValid = "RESET_TOKEN_INVALID"
+	KodeErrorCaptchaTidakValid    = "CAPTCHA_INVALID"
+
+	KodeErrorPenggunaTerkaitPasien        = "USER_HAS_PATIENTS"
+	KodeErrorAdminPuskesmasTerkaitPasien  = "PUSKESMAS_ADMIN_HAS_PATIENTS"
+	KodeErrorAdminApotekTerkaitObat       = "PHARMACY_ADMIN_HAS_MEDICINES"
+	KodeErrorPasienTerkaitKontrolBalik    = "PATIENT_HAS_FOLLOW_UPS"
+	KodeErrorPasienTerkaitPengambilanObat = "PATIENT_HAS_PICKUPS"
+	KodeErrorPasienKontrolBalikAktif      = "PATIENT_HAS_PENDING_FOLLOW_UP"
+	KodeErrorPasienPengambilanObatAktif   = "PATIENT_HAS_PENDING_PICKUP"
+	KodeErrorObatTerkaitPengambilanObat   = "MEDICINE_HAS_PICKUPS"
+
+	KodeErrorNomorAntreanDigunakan  = "QUEUE_NUMBER_CONFLICT"
+	KodeErrorKuotaKontrolBalikPenuh = "FOLLOW_UP_QUOTA_FULL"
+	KodeErrorSlotKontrolBalikHabis  = "FOLLOW_UP_SLOT_UNAVAILABLE"
+	KodeErrorPuskesmasTutup         = "PUSKESMAS_CLOSED"
+	KodeErrorJadwalOperasional      = "OPERATING_HOURS_INVALID"
+	KodeErrorRentangTanggal         = "DATE_RANGE_TOO_LONG"
+
+	KodeErrorStokTidakCukup      = "STOCK_INSUFFICIENT"
+	KodeErrorStokBatchTidakCukup = "BATCH_STOCK_INSUFFICIENT"
+	KodeErrorBatchKedaluwarsa    = "BATCH_EXPIRED"
+	KodeErrorBatchBerbeda        = "BATCH_EXPIRY_MISMATCH"
+	KodeErrorResiTidakValid      = "RECEIPT_INVALID"
+	KodeErrorResiBedaApotek      = "RECEIPT_MIXED_PHARMACIES"
+
+	KodeErrorKodePemeriksaanLabDigunakan = "LAB_TEST_CODE_TAKEN"
+	KodeErrorPemeriksaanLabTerkaitHasil  = "LAB_TEST_HAS_RESULTS"
+	KodeErrorRentangRujukan              = "REFERENCE_RANGE_INVALID"
+
+	KodeErrorNotifikasiTerkirim = "NOTIFICATION_ALREADY_SENT"
+	KodeErrorEventTerkirim      = "EVENT_ALREADY_SENT"
+)
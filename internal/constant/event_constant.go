@@ -0,0 +1,22 @@
+package constant
+
+const (
+	AgregatPasien          = "pasien"
+	AgregatKontrolBalik    = "kontrol_balik"
+	AgregatPengambilanObat = "pengambilan_obat"
+
+	EventPasienSelesai = "pasien_selesai"
+
+	EventKontrolBalikDibuat  = "kontrol_balik_dibuat"
+	EventKontrolBalikDiubah  = "kontrol_balik_diubah"
+	EventKontrolBalikBatal   = "kontrol_balik_batal"
+	EventKontrolBalikSelesai = "kontrol_balik_selesai"
+
+	EventPengambilanObatDibuat  = "pengambilan_obat_dibuat"
+	EventPengambilanObatDiubah  = "pengambilan_obat_diubah"
+	EventPengambilanObatBatal   = "pengambilan_obat_batal"
+	EventPengambilanObatDiambil = "pengambilan_obat_diambil"
+
+	// handler in-process yang didaftarkan dengan jenis ini menerima semua event
+	EventSemua = "*"
+)
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	FlagHasilLabNormal       = "normal"
+	FlagHasilLabRendah       = "rendah"
+	FlagHasilLabTinggi       = "tinggi"
+	FlagHasilLabTanpaRujukan = "tanpa_rujukan"
+)
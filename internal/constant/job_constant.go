@@ -0,0 +1,7 @@
+package constant
+
+const (
+	JobPengingat                    = "pengingat"
+	JobBatalKontrolBalikTerlewat    = "batal_kontrol_balik_terlewat"
+	JobBatalPengambilanObatTerlewat = "batal_pengambilan_obat_terlewat"
+)
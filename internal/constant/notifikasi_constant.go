@@ -0,0 +1,17 @@
+package constant
+
+const (
+	NotifierLog = "log"
+	NotifierFcm = "fcm"
+
+	JenisNotifikasiKontrolBalikDibuat           = "kontrol_balik_dibuat"
+	JenisNotifikasiKontrolBalikDijadwalkanUlang = "kontrol_balik_dijadwalkan_ulang"
+	JenisNotifikasiKontrolBalikBatal            = "kontrol_balik_batal"
+	JenisNotifikasiPengambilanObatDibuat        = "pengambilan_obat_dibuat"
+	JenisNotifikasiPengambilanObatBatal         = "pengambilan_obat_batal"
+
+	JenisNotifikasiKontrolBalikPengingatH1         = "kontrol_balik_pengingat_h1"
+	JenisNotifikasiKontrolBalikPengingatHariIni    = "kontrol_balik_pengingat_hari_ini"
+	JenisNotifikasiPengambilanObatPengingatH1      = "pengambilan_obat_pengingat_h1"
+	JenisNotifikasiPengambilanObatPengingatHariIni = "pengambilan_obat_pengingat_hari_ini"
+)
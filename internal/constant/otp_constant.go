@@ -0,0 +1,6 @@
+package constant
+
+const (
+	OtpSenderLog     = "log"
+	OtpSenderGateway = "gateway"
+)
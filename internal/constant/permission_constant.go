@@ -0,0 +1,129 @@
+package constant
+
+// cakupan data yang boleh disentuh sebuah role pada satu permission
+const (
+	ScopeSemua = "semua"
+	ScopeMilik = "milik"
+)
+
+const (
+	PermissionAuthLogout      = "auth:logout"
+	PermissionAuthLogoutSemua = "auth:logout-semua"
+
+	PermissionAdminSuperPasswordUpdate = "admin-super:password-update"
+
+	PermissionDuaFaktorGet          = "dua-faktor:get"
+	PermissionDuaFaktorEnrol        = "dua-faktor:enrol"
+	PermissionDuaFaktorAktifkan     = "dua-faktor:aktifkan"
+	PermissionDuaFaktorNonaktifkan  = "dua-faktor:nonaktifkan"
+	PermissionDuaFaktorKodeCadangan = "dua-faktor:kode-cadangan"
+	PermissionDuaFaktorReset        = "dua-faktor:reset"
+
+	PermissionPercobaanLoginList      = "percobaan-login:list"
+	PermissionPercobaanLoginBukaKunci = "percobaan-login:buka-kunci"
+
+	PermissionAuditList = "audit:list"
+
+	PermissionNotifikasiList       = "notifikasi:list"
+	PermissionNotifikasiKirimUlang = "notifikasi:kirim-ulang"
+
+	PermissionRiwayatJobList = "riwayat-job:list"
+
+	PermissionEventList       = "event:list"
+	PermissionEventKirimUlang = "event:kirim-ulang"
+
+	PermissionWebhookList         = "webhook:list"
+	PermissionWebhookGet          = "webhook:get"
+	PermissionWebhookCreate       = "webhook:create"
+	PermissionWebhookUpdate       = "webhook:update"
+	PermissionWebhookDelete       = "webhook:delete"
+	PermissionWebhookRotasiSecret = "webhook:rotasi-secret"
+	PermissionWebhookPengiriman   = "webhook:pengiriman"
+	PermissionWebhookKirimUlang   = "webhook:kirim-ulang"
+
+	PermissionAdminPuskesmasList                  = "admin-puskesmas:list"
+	PermissionAdminPuskesmasCurrent               = "admin-puskesmas:current"
+	PermissionAdminPuskesmasCurrentProfileUpdate  = "admin-puskesmas:current-profile-update"
+	PermissionAdminPuskesmasCurrentPasswordUpdate = "admin-puskesmas:current-password-update"
+	PermissionAdminPuskesmasGet                   = "admin-puskesmas:get"
+	PermissionAdminPuskesmasCreate                = "admin-puskesmas:create"
+	PermissionAdminPuskesmasUpdate                = "admin-puskesmas:update"
+	PermissionAdminPuskesmasDelete                = "admin-puskesmas:delete"
+
+	PermissionJadwalOperasionalCurrent       = "jadwal-operasional:current"
+	PermissionJadwalOperasionalCurrentUpdate = "jadwal-operasional:current-update"
+	PermissionJadwalOperasionalGet           = "jadwal-operasional:get"
+	PermissionJadwalOperasionalUpdate        = "jadwal-operasional:update"
+
+	PermissionAdminApotekList                  = "admin-apotek:list"
+	PermissionAdminApotekCurrent               = "admin-apotek:current"
+	PermissionAdminApotekCurrentProfileUpdate  = "admin-apotek:current-profile-update"
+	PermissionAdminApotekCurrentPasswordUpdate = "admin-apotek:current-password-update"
+	PermissionAdminApotekGet                   = "admin-apotek:get"
+	PermissionAdminApotekCreate                = "admin-apotek:create"
+	PermissionAdminApotekUpdate                = "admin-apotek:update"
+	PermissionAdminApotekDelete                = "admin-apotek:delete"
+
+	PermissionPenggunaCurrent                     = "pengguna:current"
+	PermissionPenggunaCurrentProfileUpdate        = "pengguna:current-profile-update"
+	PermissionPenggunaCurrentPasswordUpdate       = "pengguna:current-password-update"
+	PermissionPenggunaCurrentTokenPerangkatUpdate = "pengguna:current-token-perangkat-update"
+	PermissionPenggunaList                        = "pengguna:list"
+	PermissionPenggunaGet                         = "pengguna:get"
+	PermissionPenggunaCreate                      = "pengguna:create"
+	PermissionPenggunaUpdate                      = "pengguna:update"
+	PermissionPenggunaDelete                      = "pengguna:delete"
+
+	PermissionObatList         = "obat:list"
+	PermissionObatKedaluwarsa  = "obat:kedaluwarsa"
+	PermissionObatPeringatan   = "obat:peringatan"
+	PermissionObatGet          = "obat:get"
+	PermissionObatCreate       = "obat:create"
+	PermissionObatUpdate       = "obat:update"
+	PermissionObatDelete       = "obat:delete"
+	PermissionObatMutasi       = "obat:mutasi"
+	PermissionObatRestok       = "obat:restok"
+	PermissionObatBatch        = "obat:batch"
+	PermissionObatKoreksiBatch = "obat:koreksi-batch"
+
+	PermissionPasienSearch     = "pasien:search"
+	PermissionPasienGet        = "pasien:get"
+	PermissionPasienCreate     = "pasien:create"
+	PermissionPasienUpdate     = "pasien:update"
+	PermissionPasienDelete     = "pasien:delete"
+	PermissionPasienSelesai    = "pasien:selesai"
+	PermissionPasienTimeline   = "pasien:timeline"
+	PermissionPasienTandaVital = "pasien:tanda-vital"
+	PermissionPasienHasilLab   = "pasien:hasil-lab"
+
+	PermissionKontrolBalikSearch         = "kontrol-balik:search"
+	PermissionKontrolBalikSlot           = "kontrol-balik:slot"
+	PermissionKontrolBalikGet            = "kontrol-balik:get"
+	PermissionKontrolBalikCreate         = "kontrol-balik:create"
+	PermissionKontrolBalikUpdate         = "kontrol-balik:update"
+	PermissionKontrolBalikDelete         = "kontrol-balik:delete"
+	PermissionKontrolBalikSelesai        = "kontrol-balik:selesai"
+	PermissionKontrolBalikBatal          = "kontrol-balik:batal"
+	PermissionKontrolBalikHasilLab       = "kontrol-balik:hasil-lab"
+	PermissionKontrolBalikHasilLabUpdate = "kontrol-balik:hasil-lab-update"
+
+	PermissionPemeriksaanLabList   = "pemeriksaan-lab:list"
+	PermissionPemeriksaanLabGet    = "pemeriksaan-lab:get"
+	PermissionPemeriksaanLabCreate = "pemeriksaan-lab:create"
+	PermissionPemeriksaanLabUpdate = "pemeriksaan-lab:update"
+	PermissionPemeriksaanLabDelete = "pemeriksaan-lab:delete"
+
+	PermissionPengambilanObatSearch    = "pengambilan-obat:search"
+	PermissionPengambilanObatGetByResi = "pengambilan-obat:get-by-resi"
+	PermissionPengambilanObatGet       = "pengambilan-obat:get"
+	PermissionPengambilanObatQrCode    = "pengambilan-obat:qr-code"
+	PermissionPengambilanObatCreate    = "pengambilan-obat:create"
+	PermissionPengambilanObatUpdate    = "pengambilan-obat:update"
+	PermissionPengambilanObatDelete    = "pengambilan-obat:delete"
+	PermissionPengambilanObatBatal     = "pengambilan-obat:batal"
+	PermissionPengambilanObatDiambil   = "pengambilan-obat:diambil"
+
+	PermissionArtikelCreate = "artikel:create"
+	PermissionArtikelUpdate = "artikel:update"
+	PermissionArtikelDelete = "artikel:delete"
+)
@@ -11,4 +11,24 @@ const (
 	StatusKontrolBalikMenunggu = "menunggu"
 	StatusKontrolBalikSelesai  = "selesai"
 	StatusKontrolBalikBatal    = "batal"
+
+	StatusPercobaanLoginBerhasil = "berhasil"
+	StatusPercobaanLoginGagal    = "gagal"
+	StatusPercobaanLoginDitolak  = "ditolak"
+	StatusPercobaanLoginDibuka   = "dibuka"
+
+	StatusNotifikasiMenunggu = "menunggu"
+	StatusNotifikasiTerkirim = "terkirim"
+	StatusNotifikasiGagal    = "gagal"
+
+	StatusRiwayatJobBerhasil = "berhasil"
+	StatusRiwayatJobGagal    = "gagal"
+
+	StatusOutboxMenunggu = "menunggu"
+	StatusOutboxTerkirim = "terkirim"
+	StatusOutboxGagal    = "gagal"
+
+	StatusPengirimanWebhookMenunggu = "menunggu"
+	StatusPengirimanWebhookTerkirim = "terkirim"
+	StatusPengirimanWebhookGagal    = "gagal"
 )
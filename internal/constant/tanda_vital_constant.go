@@ -0,0 +1,15 @@
+package constant
+
+import "regexp"
+
+const (
+	PeringatanTekananDarahTinggi = "tekanan_darah_tinggi"
+	PeringatanTekananDarahRendah = "tekanan_darah_rendah"
+	PeringatanDenyutNadiTinggi   = "denyut_nadi_tinggi"
+	PeringatanDenyutNadiRendah   = "denyut_nadi_rendah"
+	PeringatanImtTinggi          = "imt_tinggi"
+	PeringatanImtRendah          = "imt_rendah"
+)
+
+// tekanan darah teks sistolik/diastolik seperti "130/85" atau "130/85 mmHg", dipakai validator dan pengurai
+var PolaTekananDarah = regexp.MustCompile(`(?i)^\s*(\d{2,3})\s*/\s*(\d{2,3})\s*(mmhg)?\s*$`)
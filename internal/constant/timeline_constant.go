@@ -0,0 +1,6 @@
+package constant
+
+const (
+	JenisTimelineKontrolBalik    = "kontrol_balik"
+	JenisTimelinePengambilanObat = "pengambilan_obat"
+)
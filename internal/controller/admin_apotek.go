@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -27,20 +26,17 @@ func (c *AdminApotekController) Login(ctx fiber.Ctx) error {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
+	request.Ip = ctx.IP()
 	response, err := c.AdminApotekService.Login(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token": response.Token})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *AdminApotekController) Current(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekGetRequest)
 	request.ID = auth.ID
 	response, err := c.AdminApotekService.Current(ctx.Context(), request)
@@ -54,9 +50,6 @@ func (c *AdminApotekController) Current(ctx fiber.Ctx) error {
 
 func (c *AdminApotekController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekProfileUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -77,9 +70,6 @@ func (c *AdminApotekController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 
 func (c *AdminApotekController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekPasswordUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -95,20 +85,20 @@ func (c *AdminApotekController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 }
 
 func (c *AdminApotekController) List(ctx fiber.Ctx) error {
-	response, err := c.AdminApotekService.List(ctx.Context())
+	request := new(model.AdminApotekListRequest)
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.AdminApotekService.List(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *AdminApotekController) Get(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekGetRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -130,10 +120,6 @@ func (c *AdminApotekController) Get(ctx fiber.Ctx) error {
 }
 
 func (c *AdminApotekController) Create(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekCreateRequest)
 
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -155,10 +141,6 @@ func (c *AdminApotekController) Create(ctx fiber.Ctx) error {
 }
 
 func (c *AdminApotekController) Update(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekUpdateRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -191,10 +173,6 @@ func (c *AdminApotekController) Update(ctx fiber.Ctx) error {
 }
 
 func (c *AdminApotekController) Delete(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminApotekDeleteRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
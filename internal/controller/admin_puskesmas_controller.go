@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -27,20 +26,17 @@ func (c *AdminPuskesmasController) Login(ctx fiber.Ctx) error {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
+	request.Ip = ctx.IP()
 	response, err := c.AdminPuskesmasService.Login(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token": response.Token})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *AdminPuskesmasController) Current(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasGetRequest)
 	request.ID = auth.ID
 	response, err := c.AdminPuskesmasService.Current(ctx.Context(), request)
@@ -54,9 +50,6 @@ func (c *AdminPuskesmasController) Current(ctx fiber.Ctx) error {
 
 func (c *AdminPuskesmasController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasProfileUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -77,9 +70,6 @@ func (c *AdminPuskesmasController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 
 func (c *AdminPuskesmasController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasPasswordUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -95,20 +85,20 @@ func (c *AdminPuskesmasController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 }
 
 func (c *AdminPuskesmasController) List(ctx fiber.Ctx) error {
-	response, err := c.AdminPuskesmasService.List(ctx.Context())
+	request := new(model.AdminPuskesmasListRequest)
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.AdminPuskesmasService.List(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *AdminPuskesmasController) Get(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasGetRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -130,10 +120,6 @@ func (c *AdminPuskesmasController) Get(ctx fiber.Ctx) error {
 }
 
 func (c *AdminPuskesmasController) Create(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasCreateRequest)
 
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -155,10 +141,6 @@ func (c *AdminPuskesmasController) Create(ctx fiber.Ctx) error {
 }
 
 func (c *AdminPuskesmasController) Update(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasUpdateRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -191,10 +173,6 @@ func (c *AdminPuskesmasController) Update(ctx fiber.Ctx) error {
 }
 
 func (c *AdminPuskesmasController) Delete(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminPuskesmasDeleteRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -3,7 +3,6 @@ package controller
 import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -23,20 +22,17 @@ func (c *AdminSuperController) Login(ctx fiber.Ctx) error {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
+	request.Ip = ctx.IP()
 	response, err := c.AdminSuperService.Login(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token": response.Token})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *AdminSuperController) PasswordUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.AdminSuperPasswordUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
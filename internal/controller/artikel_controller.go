@@ -6,7 +6,6 @@ import (
 	"github.com/valyala/fasthttp"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -65,6 +64,10 @@ func (c *ArtikelController) Search(ctx fiber.Ctx) error {
 		}
 		request.IdAdminPuskesmas = int32(idAdminPuskesmas)
 	}
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
 
 	response, err := c.ArtikelService.Search(ctx.Context(), request)
 	if err != nil {
@@ -72,25 +75,19 @@ func (c *ArtikelController) Search(ctx fiber.Ctx) error {
 		return err
 	}
 
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response,
-	})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *ArtikelController) Create(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
-
 	request := new(model.ArtikelCreateRequest)
 	if err := ctx.Bind().Body(request); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
 		slog.Error(err.Error())
@@ -118,10 +115,6 @@ func (c *ArtikelController) Create(ctx fiber.Ctx) error {
 
 func (c *ArtikelController) Update(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
-
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -145,8 +138,8 @@ func (c *ArtikelController) Update(ctx fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 		request.CurrentAdminPuskesmas = true
 	}
 
@@ -173,10 +166,6 @@ func (c *ArtikelController) Update(ctx fiber.Ctx) error {
 
 func (c *ArtikelController) Delete(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
-
 	request := new(model.ArtikelDeleteRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -189,9 +178,7 @@ func (c *ArtikelController) Delete(ctx fiber.Ctx) error {
 	}
 	request.ID = int32(id)
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 
 	err = c.ArtikelService.Delete(ctx.Context(), request)
 	if err != nil {
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type AuditController struct {
+	AuditService *service.AuditService
+}
+
+func NewAuditController(auditService *service.AuditService) *AuditController {
+	return &AuditController{auditService}
+}
+
+func (c *AuditController) List(ctx fiber.Ctx) error {
+	request := new(model.AuditListRequest)
+	request.Entitas = ctx.Query("entitas")
+	request.Aksi = ctx.Query("aksi")
+	request.RoleAktor = ctx.Query("roleAktor")
+	if q := ctx.Query("idEntitas"); q != "" {
+		idEntitas, err := strconv.Atoi(q)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idEntitas < math.MinInt32 || idEntitas > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdEntitas = int32(idEntitas)
+	}
+	if q := ctx.Query("idAktor"); q != "" {
+		idAktor, err := strconv.Atoi(q)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idAktor < math.MinInt32 || idAktor > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdAktor = int32(idAktor)
+	}
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.AuditService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
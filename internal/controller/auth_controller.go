@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+)
+
+type AuthController struct {
+	AuthService *service.AuthService
+}
+
+func NewAuthController(authService *service.AuthService) *AuthController {
+	return &AuthController{authService}
+}
+
+func (c *AuthController) Refresh(ctx fiber.Ctx) error {
+	request := new(model.AuthRefreshRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.AuthService.Refresh(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"token":        response.Token,
+		"refreshToken": response.RefreshToken})
+}
+
+func (c *AuthController) Logout(ctx fiber.Ctx) error {
+	request := &model.AuthLogoutRequest{Aktor: middleware.GetAuth(ctx)}
+	if err := c.AuthService.Logout(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Logout berhasil"})
+}
+
+func (c *AuthController) LogoutSemua(ctx fiber.Ctx) error {
+	request := &model.AuthLogoutRequest{Aktor: middleware.GetAuth(ctx)}
+	if err := c.AuthService.LogoutSemua(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Logout dari semua perangkat berhasil"})
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type DuaFaktorController struct {
+	DuaFaktorService *service.DuaFaktorService
+}
+
+func NewDuaFaktorController(duaFaktorService *service.DuaFaktorService) *DuaFaktorController {
+	return &DuaFaktorController{duaFaktorService}
+}
+
+func (c *DuaFaktorController) Get(ctx fiber.Ctx) error {
+	request := &model.DuaFaktorGetRequest{Aktor: middleware.GetAuth(ctx)}
+	response, err := c.DuaFaktorService.Get(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *DuaFaktorController) Enrol(ctx fiber.Ctx) error {
+	request := &model.DuaFaktorEnrolRequest{Aktor: middleware.GetAuth(ctx)}
+	response, err := c.DuaFaktorService.Enrol(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *DuaFaktorController) Aktifkan(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorKodeRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.Aktor = middleware.GetAuth(ctx)
+	response, err := c.DuaFaktorService.Aktifkan(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *DuaFaktorController) Nonaktifkan(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorKodeRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.Aktor = middleware.GetAuth(ctx)
+	if err := c.DuaFaktorService.Nonaktifkan(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "2FA berhasil dinonaktifkan"})
+}
+
+func (c *DuaFaktorController) KodeCadangan(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorKodeRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.Aktor = middleware.GetAuth(ctx)
+	response, err := c.DuaFaktorService.KodeCadangan(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *DuaFaktorController) Reset(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorResetRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.IdAkun = int32(id)
+	request.Role = ctx.Params("role")
+	if err := c.DuaFaktorService.Reset(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "2FA berhasil direset"})
+}
+
+func (c *DuaFaktorController) LoginEnrol(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorLoginEnrolRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.DuaFaktorService.LoginEnrol(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *DuaFaktorController) Login(ctx fiber.Ctx) error {
+	request := new(model.DuaFaktorLoginRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.Ip = ctx.IP()
+	response, err := c.DuaFaktorService.Login(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type EventController struct {
+	EventService *service.EventService
+}
+
+func NewEventController(eventService *service.EventService) *EventController {
+	return &EventController{eventService}
+}
+
+func (c *EventController) List(ctx fiber.Ctx) error {
+	request := new(model.EventListRequest)
+	request.Jenis = ctx.Query("jenis")
+	request.Agregat = ctx.Query("agregat")
+	request.Status = ctx.Query("status")
+	if q := ctx.Query("idAgregat"); q != "" {
+		idAgregat, err := strconv.Atoi(q)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idAgregat < math.MinInt32 || idAgregat > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdAgregat = int32(idAgregat)
+	}
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.EventService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *EventController) KirimUlang(ctx fiber.Ctx) error {
+	request := new(model.EventKirimUlangRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	if err := c.EventService.KirimUlang(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Event dijadwalkan untuk dikirim ulang"})
+}
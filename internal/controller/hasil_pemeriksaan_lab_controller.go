@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+	"strings"
+)
+
+type HasilPemeriksaanLabController struct {
+	HasilPemeriksaanLabService *service.HasilPemeriksaanLabService
+}
+
+func NewHasilPemeriksaanLabController(hasilPemeriksaanLabService *service.HasilPemeriksaanLabService) *HasilPemeriksaanLabController {
+	return &HasilPemeriksaanLabController{hasilPemeriksaanLabService}
+}
+
+func (c *HasilPemeriksaanLabController) Get(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.HasilPemeriksaanLabGetRequest)
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	response, err := c.HasilPemeriksaanLabService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *HasilPemeriksaanLabController) Update(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.HasilPemeriksaanLabUpdateRequest)
+	request.Aktor = auth
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = int32(id)
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+
+	response, err := c.HasilPemeriksaanLabService.Update(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *HasilPemeriksaanLabController) Riwayat(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.RiwayatHasilPemeriksaanLabRequest)
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdPengguna = auth.ScopePengguna()
+	request.Kode = strings.ToUpper(strings.TrimSpace(ctx.Query("kode")))
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	if q := ctx.Query("tanggalMulai"); q != "" {
+		tanggalMulai, err := strconv.ParseInt(q, 10, 64)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		request.TanggalMulai = tanggalMulai
+	}
+	if q := ctx.Query("tanggalSelesai"); q != "" {
+		tanggalSelesai, err := strconv.ParseInt(q, 10, 64)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		request.TanggalSelesai = tanggalSelesai
+	}
+	response, err := c.HasilPemeriksaanLabService.Riwayat(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type JadwalOperasionalController struct {
+	JadwalOperasionalService *service.JadwalOperasionalService
+}
+
+func NewJadwalOperasionalController(jadwalOperasionalService *service.JadwalOperasionalService) *JadwalOperasionalController {
+	return &JadwalOperasionalController{jadwalOperasionalService}
+}
+
+func (c *JadwalOperasionalController) Current(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.JadwalOperasionalGetRequest)
+	request.IdAdminPuskesmas = auth.ID
+	response, err := c.JadwalOperasionalService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *JadwalOperasionalController) CurrentUpdate(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.JadwalOperasionalUpdateRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.IdAdminPuskesmas = auth.ID
+	if err := c.JadwalOperasionalService.Update(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Jadwal operasional berhasil diupdate"})
+}
+
+func (c *JadwalOperasionalController) Get(ctx fiber.Ctx) error {
+	request := new(model.JadwalOperasionalGetRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.IdAdminPuskesmas = int32(id)
+	response, err := c.JadwalOperasionalService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *JadwalOperasionalController) Update(ctx fiber.Ctx) error {
+	request := new(model.JadwalOperasionalUpdateRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.IdAdminPuskesmas = int32(id)
+
+	if err := c.JadwalOperasionalService.Update(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Jadwal operasional berhasil diupdate"})
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -23,33 +22,63 @@ func NewKontrolBalikController(kontrolBalikService *service.KontrolBalikService,
 
 func (c *KontrolBalikController) Search(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas && auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikSearchRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	} else if auth.Role == constant.RolePengguna {
-		request.IdPengguna = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdPengguna = auth.ScopePengguna()
 	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
 	response, err := c.KontrolBalikService.Search(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *KontrolBalikController) Slot(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.KontrolBalikSlotRequest)
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
+	} else {
+		idAdminPuskesmas, err := strconv.Atoi(ctx.Query("idAdminPuskesmas"))
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idAdminPuskesmas < math.MinInt32 || idAdminPuskesmas > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdAdminPuskesmas = int32(idAdminPuskesmas)
+	}
+	tanggalMulai, err := strconv.ParseInt(ctx.Query("tanggalMulai"), 10, 64)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	tanggalSelesai, err := strconv.ParseInt(ctx.Query("tanggalSelesai"), 10, 64)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.TanggalMulai = tanggalMulai
+	request.TanggalSelesai = tanggalSelesai
+	response, err := c.KontrolBalikService.Slot(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
 }
 
 func (c *KontrolBalikController) Get(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikGetRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -70,16 +99,14 @@ func (c *KontrolBalikController) Get(ctx fiber.Ctx) error {
 
 func (c *KontrolBalikController) Create(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikCreateRequest)
+	request.Aktor = auth
 	if err := ctx.Bind().JSON(request); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 	if err := c.KontrolBalikService.Create(ctx.Context(), request); err != nil {
 		slog.Error(err.Error())
@@ -90,10 +117,8 @@ func (c *KontrolBalikController) Create(ctx fiber.Ctx) error {
 
 func (c *KontrolBalikController) Update(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikUpdateRequest)
+	request.Aktor = auth
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -116,8 +141,8 @@ func (c *KontrolBalikController) Update(ctx fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 	if err := c.KontrolBalikService.Update(ctx.Context(), request); err != nil {
 		slog.Error(err.Error())
@@ -128,13 +153,9 @@ func (c *KontrolBalikController) Update(ctx fiber.Ctx) error {
 
 func (c *KontrolBalikController) Delete(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikDeleteRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -154,13 +175,9 @@ func (c *KontrolBalikController) Delete(ctx fiber.Ctx) error {
 
 func (c *KontrolBalikController) Batal(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikBatalRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -180,13 +197,9 @@ func (c *KontrolBalikController) Batal(ctx fiber.Ctx) error {
 
 func (c *KontrolBalikController) Selesai(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.KontrolBalikSelesaiRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
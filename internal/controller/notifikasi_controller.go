@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type NotifikasiController struct {
+	NotifikasiService *service.NotifikasiService
+}
+
+func NewNotifikasiController(notifikasiService *service.NotifikasiService) *NotifikasiController {
+	return &NotifikasiController{notifikasiService}
+}
+
+func (c *NotifikasiController) List(ctx fiber.Ctx) error {
+	request := new(model.NotifikasiListRequest)
+	request.Jenis = ctx.Query("jenis")
+	request.Status = ctx.Query("status")
+	if q := ctx.Query("idPengguna"); q != "" {
+		idPengguna, err := strconv.Atoi(q)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idPengguna < math.MinInt32 || idPengguna > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdPengguna = int32(idPengguna)
+	}
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.NotifikasiService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *NotifikasiController) KirimUlang(ctx fiber.Ctx) error {
+	request := new(model.NotifikasiKirimUlangRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	if err := c.NotifikasiService.KirimUlang(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Notifikasi dijadwalkan untuk dikirim ulang"})
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -22,31 +21,24 @@ func NewObatController(obatService *service.ObatService, modifier *mold.Transfor
 }
 func (c *ObatController) List(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.ObatListRequest)
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
 	}
 	response, err := c.ObatService.List(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *ObatController) Get(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.ObatGetRequest)
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
-	}
+	request.IdAdminApotek = auth.ScopeAdminApotek()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -68,18 +60,16 @@ func (c *ObatController) Get(ctx fiber.Ctx) error {
 
 func (c *ObatController) Create(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.ObatCreateRequest)
+	request.Aktor = auth
 
 	if err := ctx.Bind().JSON(request); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
 
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
+	if id := auth.ScopeAdminApotek(); id != 0 {
+		request.IdAdminApotek = id
 	}
 
 	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
@@ -97,10 +87,8 @@ func (c *ObatController) Create(ctx fiber.Ctx) error {
 
 func (c *ObatController) Update(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.ObatUpdateRequest)
+	request.Aktor = auth
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -118,8 +106,8 @@ func (c *ObatController) Update(ctx fiber.Ctx) error {
 
 	request.ID = int32(id)
 
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
+	if id := auth.ScopeAdminApotek(); id != 0 {
+		request.IdAdminApotek = id
 		request.CurrentAdminApotek = true
 	}
 
@@ -138,13 +126,9 @@ func (c *ObatController) Update(ctx fiber.Ctx) error {
 
 func (c *ObatController) Delete(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.ObatDeleteRequest)
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminApotek = auth.ScopeAdminApotek()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -163,3 +147,167 @@ func (c *ObatController) Delete(ctx fiber.Ctx) error {
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Obat berhasil dihapus"})
 }
+
+func (c *ObatController) Restok(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.ObatRestokRequest)
+	request.Aktor = auth
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = int32(id)
+
+	if id := auth.ScopeAdminApotek(); id != 0 {
+		request.IdAdminApotek = id
+	}
+
+	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.ObatService.Restok(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Stok obat berhasil ditambahkan"})
+}
+
+func (c *ObatController) Mutasi(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.MutasiObatGetRequest)
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.IdObat = int32(id)
+	response, err := c.ObatService.Mutasi(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *ObatController) Batch(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.BatchObatListRequest)
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.IdObat = int32(id)
+	response, err := c.ObatService.Batch(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *ObatController) KoreksiBatch(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.BatchObatKoreksiRequest)
+	request.Aktor = auth
+	idObat, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if idObat < math.MinInt32 || idObat > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	id, err := strconv.Atoi(ctx.Params("idBatch"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = int32(id)
+	request.IdObat = int32(idObat)
+
+	if id := auth.ScopeAdminApotek(); id != 0 {
+		request.IdAdminApotek = id
+	}
+
+	if err := c.ObatService.KoreksiBatch(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Sisa batch obat berhasil dikoreksi"})
+}
+
+func (c *ObatController) Kedaluwarsa(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.BatchObatKedaluwarsaRequest)
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	hari, err := strconv.Atoi(ctx.Query("hari", "30"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if hari < math.MinInt32 || hari > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.Hari = int32(hari)
+	response, err := c.ObatService.Kedaluwarsa(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *ObatController) Peringatan(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.PeringatanStokObatListRequest)
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	response, err := c.ObatService.Peringatan(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
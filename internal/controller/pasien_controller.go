@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -23,34 +22,26 @@ func NewPasienController(pasienService *service.PasienService, modifier *mold.Tr
 
 func (c *PasienController) Search(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas && auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PasienSearchRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	} else if auth.Role == constant.RolePengguna {
-		request.IdPengguna = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdPengguna = auth.ScopePengguna()
 	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
 	response, err := c.PasienService.Search(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *PasienController) Get(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PasienGetRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -70,20 +61,47 @@ func (c *PasienController) Get(ctx fiber.Ctx) error {
 		"data": response})
 }
 
-func (c *PasienController) Create(ctx fiber.Ctx) error {
+func (c *PasienController) Timeline(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
+	request := new(model.PasienTimelineRequest)
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdPengguna = auth.ScopePengguna()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
 	}
+	request.ID = int32(id)
+	request.Jenis = ctx.Query("jenis")
+	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.PasienService.Timeline(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *PasienController) Create(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
 	request := new(model.PasienCreateRequest)
+	request.Aktor = auth
 
 	if err := ctx.Bind().JSON(request); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 
 	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
@@ -101,10 +119,8 @@ func (c *PasienController) Create(ctx fiber.Ctx) error {
 
 func (c *PasienController) Update(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PasienUpdateRequest)
+	request.Aktor = auth
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -122,8 +138,8 @@ func (c *PasienController) Update(ctx fiber.Ctx) error {
 
 	request.ID = int32(id)
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 		request.CurrentAdminPuskesmas = true
 	}
 
@@ -142,13 +158,9 @@ func (c *PasienController) Update(ctx fiber.Ctx) error {
 
 func (c *PasienController) Delete(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PasienDeleteRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -170,13 +182,9 @@ func (c *PasienController) Delete(ctx fiber.Ctx) error {
 
 func (c *PasienController) Selesai(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PasienSelesaiRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"github.com/go-playground/mold/v4"
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type PemeriksaanLabController struct {
+	PemeriksaanLabService *service.PemeriksaanLabService
+	Modifier              *mold.Transformer
+}
+
+func NewPemeriksaanLabController(pemeriksaanLabService *service.PemeriksaanLabService, modifier *mold.Transformer) *PemeriksaanLabController {
+	return &PemeriksaanLabController{pemeriksaanLabService, modifier}
+}
+
+func (c *PemeriksaanLabController) List(ctx fiber.Ctx) error {
+	request := new(model.PemeriksaanLabListRequest)
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.PemeriksaanLabService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *PemeriksaanLabController) Get(ctx fiber.Ctx) error {
+	request := new(model.PemeriksaanLabGetRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	response, err := c.PemeriksaanLabService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *PemeriksaanLabController) Create(ctx fiber.Ctx) error {
+	request := new(model.PemeriksaanLabCreateRequest)
+	request.Aktor = middleware.GetAuth(ctx)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.PemeriksaanLabService.Create(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{"data": "Pemeriksaan lab berhasil dibuat"})
+}
+
+func (c *PemeriksaanLabController) Update(ctx fiber.Ctx) error {
+	request := new(model.PemeriksaanLabUpdateRequest)
+	request.Aktor = middleware.GetAuth(ctx)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = int32(id)
+
+	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.PemeriksaanLabService.Update(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Pemeriksaan lab berhasil diupdate"})
+}
+
+func (c *PemeriksaanLabController) Delete(ctx fiber.Ctx) error {
+	request := new(model.PemeriksaanLabDeleteRequest)
+	request.Aktor = middleware.GetAuth(ctx)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+
+	if err := c.PemeriksaanLabService.Delete(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Pemeriksaan lab berhasil dihapus"})
+}
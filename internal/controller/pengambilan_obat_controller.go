@@ -4,11 +4,11 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
 	"strconv"
+	"strings"
 )
 
 type PengambilanObatController struct {
@@ -23,31 +23,26 @@ func (c *PengambilanObatController) Search(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
 
 	request := new(model.PengambilanObatSearchRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	} else if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
-	} else if auth.Role == constant.RolePengguna {
-		request.IdPengguna = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	request.IdPengguna = auth.ScopePengguna()
 	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
 	response, err := c.PengambilanObatService.Search(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *PengambilanObatController) Get(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatGetRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -68,16 +63,14 @@ func (c *PengambilanObatController) Get(ctx fiber.Ctx) error {
 
 func (c *PengambilanObatController) Create(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatCreateRequest)
+	request.Aktor = auth
 	if err := ctx.Bind().JSON(request); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 	if err := c.PengambilanObatService.Create(ctx.Context(), request); err != nil {
 		slog.Error(err.Error())
@@ -88,10 +81,8 @@ func (c *PengambilanObatController) Create(ctx fiber.Ctx) error {
 
 func (c *PengambilanObatController) Update(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatUpdateRequest)
+	request.Aktor = auth
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -109,8 +100,8 @@ func (c *PengambilanObatController) Update(ctx fiber.Ctx) error {
 
 	request.ID = int32(id)
 
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
+	if id := auth.ScopeAdminPuskesmas(); id != 0 {
+		request.IdAdminPuskesmas = id
 	}
 	if err := c.PengambilanObatService.Update(ctx.Context(), request); err != nil {
 		slog.Error(err.Error())
@@ -121,13 +112,9 @@ func (c *PengambilanObatController) Update(ctx fiber.Ctx) error {
 
 func (c *PengambilanObatController) Delete(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatDeleteRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -147,13 +134,9 @@ func (c *PengambilanObatController) Delete(ctx fiber.Ctx) error {
 
 func (c *PengambilanObatController) Batal(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatBatalRequest)
-	if auth.Role == constant.RoleAdminPuskesmas {
-		request.IdAdminPuskesmas = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -173,13 +156,9 @@ func (c *PengambilanObatController) Batal(ctx fiber.Ctx) error {
 
 func (c *PengambilanObatController) Diambil(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminApotek {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PengambilanObatDiambilRequest)
-	if auth.Role == constant.RoleAdminApotek {
-		request.IdAdminApotek = auth.ID
-	}
+	request.Aktor = auth
+	request.IdAdminApotek = auth.ScopeAdminApotek()
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
 		slog.Error(err.Error())
@@ -196,3 +175,40 @@ func (c *PengambilanObatController) Diambil(ctx fiber.Ctx) error {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Pengambilan obat berhasil ditandai selesai"})
 }
+
+func (c *PengambilanObatController) GetByResi(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.PengambilanObatResiRequest)
+	request.IdAdminApotek = auth.ScopeAdminApotek()
+	request.Resi = strings.ToUpper(strings.TrimSpace(ctx.Params("resi")))
+	response, err := c.PengambilanObatService.GetByResi(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *PengambilanObatController) QrCode(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.PengambilanObatQrCodeRequest)
+	request.IdPengguna = auth.ScopePengguna()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	request.Format = ctx.Query("format")
+	response, err := c.PengambilanObatService.QrCode(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, response.ContentType)
+	return ctx.Status(fiber.StatusOK).Send(response.Data)
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"log/slog"
 	"math"
-	"prb_care_api/internal/constant"
 	"prb_care_api/internal/middleware"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/service"
@@ -27,20 +26,19 @@ func (c *PenggunaController) Login(ctx fiber.Ctx) error {
 		slog.Error(err.Error())
 		return fiber.ErrBadRequest
 	}
+	request.Ip = ctx.IP()
 	response, err := c.PenggunaService.Login(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token": response.Token})
+		"token":        response.Token,
+		"refreshToken": response.RefreshToken})
 }
 
 func (c *PenggunaController) Current(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaGetRequest)
 	request.ID = auth.ID
 	response, err := c.PenggunaService.Current(ctx.Context(), request)
@@ -54,9 +52,6 @@ func (c *PenggunaController) Current(ctx fiber.Ctx) error {
 
 func (c *PenggunaController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaProfileUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -77,10 +72,6 @@ func (c *PenggunaController) CurrentProfileUpdate(ctx fiber.Ctx) error {
 
 func (c *PenggunaController) CurrentTokenPerangkatUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
-
 	request := new(model.PenggunaTokenPerangkatUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -97,9 +88,6 @@ func (c *PenggunaController) CurrentTokenPerangkatUpdate(ctx fiber.Ctx) error {
 
 func (c *PenggunaController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RolePengguna {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaPasswordUpdateRequest)
 	request.ID = auth.ID
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -115,24 +103,20 @@ func (c *PenggunaController) CurrentPasswordUpdate(ctx fiber.Ctx) error {
 }
 
 func (c *PenggunaController) List(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper && auth.Role != constant.RoleAdminPuskesmas {
-		return fiber.ErrForbidden
+	request := new(model.PenggunaListRequest)
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
 	}
-	response, err := c.PenggunaService.List(ctx.Context())
+	response, err := c.PenggunaService.List(ctx.Context(), request)
 	if err != nil {
 		slog.Error(err.Error())
 		return err
 	}
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response})
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
 func (c *PenggunaController) Get(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaGetRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -154,10 +138,6 @@ func (c *PenggunaController) Get(ctx fiber.Ctx) error {
 }
 
 func (c *PenggunaController) Create(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaCreateRequest)
 
 	if err := ctx.Bind().JSON(request); err != nil {
@@ -200,10 +180,6 @@ func (c *PenggunaController) Register(ctx fiber.Ctx) error {
 }
 
 func (c *PenggunaController) Update(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaUpdateRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
@@ -236,10 +212,6 @@ func (c *PenggunaController) Update(ctx fiber.Ctx) error {
 }
 
 func (c *PenggunaController) Delete(ctx fiber.Ctx) error {
-	auth := middleware.GetAuth(ctx)
-	if auth.Role != constant.RoleAdminSuper {
-		return fiber.ErrForbidden
-	}
 	request := new(model.PenggunaDeleteRequest)
 	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
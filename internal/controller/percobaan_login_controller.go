@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+)
+
+type PercobaanLoginController struct {
+	PercobaanLoginService *service.PercobaanLoginService
+}
+
+func NewPercobaanLoginController(percobaanLoginService *service.PercobaanLoginService) *PercobaanLoginController {
+	return &PercobaanLoginController{percobaanLoginService}
+}
+
+func (c *PercobaanLoginController) List(ctx fiber.Ctx) error {
+	request := new(model.PercobaanLoginListRequest)
+	request.Role = ctx.Query("role")
+	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.PercobaanLoginService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *PercobaanLoginController) BukaKunci(ctx fiber.Ctx) error {
+	request := new(model.PercobaanLoginBukaKunciRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	request.Aktor = middleware.GetAuth(ctx)
+	if err := c.PercobaanLoginService.BukaKunci(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Kunci login berhasil dibuka"})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+)
+
+type ResetPasswordController struct {
+	ResetPasswordService *service.ResetPasswordService
+}
+
+func NewResetPasswordController(resetPasswordService *service.ResetPasswordService) *ResetPasswordController {
+	return &ResetPasswordController{resetPasswordService}
+}
+
+func (c *ResetPasswordController) LupaPassword(ctx fiber.Ctx) error {
+	request := new(model.LupaPasswordRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if err := c.ResetPasswordService.LupaPassword(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Jika nomor telepon terdaftar, kode OTP akan dikirim ke nomor tersebut"})
+}
+
+func (c *ResetPasswordController) VerifikasiOtp(ctx fiber.Ctx) error {
+	request := new(model.VerifikasiOtpRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.ResetPasswordService.VerifikasiOtp(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response})
+}
+
+func (c *ResetPasswordController) ResetPassword(ctx fiber.Ctx) error {
+	request := new(model.ResetPasswordRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if err := c.ResetPasswordService.ResetPassword(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Password berhasil direset, silakan login kembali"})
+}
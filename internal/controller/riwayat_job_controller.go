@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+)
+
+type RiwayatJobController struct {
+	RiwayatJobService *service.RiwayatJobService
+}
+
+func NewRiwayatJobController(riwayatJobService *service.RiwayatJobService) *RiwayatJobController {
+	return &RiwayatJobController{riwayatJobService}
+}
+
+func (c *RiwayatJobController) List(ctx fiber.Ctx) error {
+	request := new(model.RiwayatJobListRequest)
+	request.Nama = ctx.Query("nama")
+	request.Status = ctx.Query("status")
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.RiwayatJobService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
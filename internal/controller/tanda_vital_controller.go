@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/middleware"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type TandaVitalController struct {
+	TandaVitalService *service.TandaVitalService
+}
+
+func NewTandaVitalController(tandaVitalService *service.TandaVitalService) *TandaVitalController {
+	return &TandaVitalController{tandaVitalService}
+}
+
+func (c *TandaVitalController) Get(ctx fiber.Ctx) error {
+	auth := middleware.GetAuth(ctx)
+	request := new(model.TandaVitalRequest)
+	request.IdAdminPuskesmas = auth.ScopeAdminPuskesmas()
+	request.IdPengguna = auth.ScopePengguna()
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	if q := ctx.Query("tanggalMulai"); q != "" {
+		tanggalMulai, err := strconv.ParseInt(q, 10, 64)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		request.TanggalMulai = tanggalMulai
+	}
+	if q := ctx.Query("tanggalSelesai"); q != "" {
+		tanggalSelesai, err := strconv.ParseInt(q, 10, 64)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		request.TanggalSelesai = tanggalSelesai
+	}
+	response, err := c.TandaVitalService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
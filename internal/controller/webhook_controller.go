@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"github.com/go-playground/mold/v4"
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/service"
+	"strconv"
+)
+
+type WebhookController struct {
+	WebhookService *service.WebhookService
+	Modifier       *mold.Transformer
+}
+
+func NewWebhookController(webhookService *service.WebhookService, modifier *mold.Transformer) *WebhookController {
+	return &WebhookController{webhookService, modifier}
+}
+
+func (c *WebhookController) List(ctx fiber.Ctx) error {
+	request := new(model.WebhookListRequest)
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.WebhookService.List(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *WebhookController) Get(ctx fiber.Ctx) error {
+	request := new(model.WebhookGetRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	response, err := c.WebhookService.Get(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *WebhookController) Create(ctx fiber.Ctx) error {
+	request := new(model.WebhookCreateRequest)
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	response, err := c.WebhookService.Create(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{"data": response})
+}
+
+func (c *WebhookController) Update(ctx fiber.Ctx) error {
+	request := new(model.WebhookUpdateRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+
+	if err := ctx.Bind().JSON(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = int32(id)
+
+	if err := c.Modifier.Struct(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.WebhookService.Update(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Webhook berhasil diupdate"})
+}
+
+func (c *WebhookController) Delete(ctx fiber.Ctx) error {
+	request := new(model.WebhookDeleteRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+
+	if err := c.WebhookService.Delete(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": "Webhook berhasil dihapus"})
+}
+
+func (c *WebhookController) RotasiSecret(ctx fiber.Ctx) error {
+	request := new(model.WebhookRotasiSecretRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+
+	response, err := c.WebhookService.RotasiSecret(ctx.UserContext(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"data": response})
+}
+
+func (c *WebhookController) ListPengiriman(ctx fiber.Ctx) error {
+	request := new(model.PengirimanWebhookListRequest)
+	request.Jenis = ctx.Query("jenis")
+	request.Status = ctx.Query("status")
+	if q := ctx.Query("idWebhook"); q != "" {
+		idWebhook, err := strconv.Atoi(q)
+		if err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrBadRequest
+		}
+		if idWebhook < math.MinInt32 || idWebhook > math.MaxInt32 {
+			slog.Error("value out of range for int32")
+			return fiber.ErrBadRequest
+		}
+		request.IdWebhook = int32(idWebhook)
+	}
+	if err := ctx.Bind().Query(&request.PageRequest); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	response, err := c.WebhookService.ListPengiriman(ctx.Context(), request)
+	if err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (c *WebhookController) KirimUlang(ctx fiber.Ctx) error {
+	request := new(model.PengirimanWebhookKirimUlangRequest)
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrBadRequest
+	}
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		slog.Error("value out of range for int32")
+		return fiber.ErrBadRequest
+	}
+	request.ID = int32(id)
+	if err := c.WebhookService.KirimUlang(ctx.UserContext(), request); err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": "Webhook dijadwalkan untuk dikirim ulang"})
+}
@@ -8,6 +8,7 @@ type AdminPuskesmas struct {
 	WaktuOperasional string `gorm:"column:waktu_operasional;type:varchar(1000);not null"`
 	Username         string `gorm:"column:username;type:varchar(50);unique;not null"`
 	Password         string `gorm:"column:password;type:varchar(255);not null"`
+	VersiToken       int32  `gorm:"column:versi_token;type:integer;not null;default:1"`
 }
 
 func (AdminPuskesmas) TableName() string {
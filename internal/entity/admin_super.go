@@ -1,9 +1,10 @@
 package entity
 
 type AdminSuper struct {
-	ID       int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
-	Username string `gorm:"column:username;type:varchar(50);unique;not null"`
-	Password string `gorm:"column:password;type:varchar(255);not null"`
+	ID         int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Username   string `gorm:"column:username;type:varchar(50);unique;not null"`
+	Password   string `gorm:"column:password;type:varchar(255);not null"`
+	VersiToken int32  `gorm:"column:versi_token;type:integer;not null;default:1"`
 }
 
 func (AdminSuper) TableName() string {
@@ -0,0 +1,13 @@
+package entity
+
+type AlokasiBatchObat struct {
+	ID                    int32     `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdPengambilanObatItem int32     `gorm:"column:id_pengambilan_obat_item;type:integer;not null;index"`
+	IdBatchObat           int32     `gorm:"column:id_batch_obat;type:integer;not null;index"`
+	BatchObat             BatchObat `gorm:"foreignKey:IdBatchObat"`
+	Jumlah                int32     `gorm:"column:jumlah;type:integer;not null"`
+}
+
+func (AlokasiBatchObat) TableName() string {
+	return "alokasi_batch_obat"
+}
@@ -0,0 +1,11 @@
+package entity
+
+type AntreanKontrolBalik struct {
+	IdAdminPuskesmas  int32 `gorm:"column:id_admin_puskesmas;primaryKey;autoIncrement:false;type:integer;not null"`
+	TanggalKontrol    int64 `gorm:"column:tanggal_kontrol;primaryKey;autoIncrement:false;type:bigint;not null"`
+	NoAntreanTerakhir int32 `gorm:"column:no_antrean_terakhir;type:integer;not null"`
+}
+
+func (AntreanKontrolBalik) TableName() string {
+	return "antrean_kontrol_balik"
+}
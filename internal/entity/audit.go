@@ -0,0 +1,16 @@
+package entity
+
+type Audit struct {
+	ID        int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdAktor   *int32  `gorm:"column:id_aktor;type:integer"`
+	RoleAktor *string `gorm:"column:role_aktor;type:varchar(20)"`
+	Aksi      string  `gorm:"column:aksi;type:varchar(20);not null"`
+	Entitas   string  `gorm:"column:entitas;type:varchar(50);not null"`
+	IdEntitas int32   `gorm:"column:id_entitas;type:integer;not null"`
+	Perubahan string  `gorm:"column:perubahan;type:jsonb;not null"`
+	Waktu     int64   `gorm:"column:waktu;type:bigint;not null"`
+}
+
+func (Audit) TableName() string {
+	return "audit"
+}
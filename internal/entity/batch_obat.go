@@ -0,0 +1,16 @@
+package entity
+
+type BatchObat struct {
+	ID                 int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdObat             int32  `gorm:"column:id_obat;type:integer;not null;uniqueIndex:idx_batch_obat_no_batch"`
+	Obat               Obat   `gorm:"foreignKey:IdObat"`
+	NoBatch            string `gorm:"column:no_batch;type:varchar(50);not null;uniqueIndex:idx_batch_obat_no_batch"`
+	TanggalKedaluwarsa *int64 `gorm:"column:tanggal_kedaluwarsa;type:bigint;index"`
+	JumlahDiterima     int32  `gorm:"column:jumlah_diterima;type:integer;not null"`
+	Sisa               int32  `gorm:"column:sisa;type:integer;not null"`
+	TanggalDiterima    int64  `gorm:"column:tanggal_diterima;type:bigint;not null"`
+}
+
+func (BatchObat) TableName() string {
+	return "batch_obat"
+}
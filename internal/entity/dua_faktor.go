@@ -0,0 +1,15 @@
+package entity
+
+type DuaFaktor struct {
+	ID              int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdAkun          int32  `gorm:"column:id_akun;type:integer;not null"`
+	Role            string `gorm:"column:role;type:varchar(20);not null"`
+	Secret          string `gorm:"column:secret;type:varchar(64);not null"`
+	LangkahTerakhir int64  `gorm:"column:langkah_terakhir;type:bigint;not null;default:0"`
+	Dibuat          int64  `gorm:"column:dibuat;type:bigint;not null"`
+	Aktif           *int64 `gorm:"column:aktif;type:bigint"`
+}
+
+func (DuaFaktor) TableName() string {
+	return "dua_faktor"
+}
@@ -0,0 +1,17 @@
+package entity
+
+type HasilPemeriksaanLab struct {
+	ID               int32          `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdKontrolBalik   int32          `gorm:"column:id_kontrol_balik;type:integer;not null;uniqueIndex:uq_hasil_pemeriksaan_lab"`
+	KontrolBalik     KontrolBalik   `gorm:"foreignKey:IdKontrolBalik"`
+	IdPemeriksaanLab int32          `gorm:"column:id_pemeriksaan_lab;type:integer;not null;uniqueIndex:uq_hasil_pemeriksaan_lab"`
+	PemeriksaanLab   PemeriksaanLab `gorm:"foreignKey:IdPemeriksaanLab"`
+	Nilai            float64        `gorm:"column:nilai;type:numeric(10,2);not null"`
+	NilaiMin         *float64       `gorm:"column:nilai_min;type:numeric(10,2)"`
+	NilaiMaks        *float64       `gorm:"column:nilai_maks;type:numeric(10,2)"`
+	Flag             string         `gorm:"column:flag;type:varchar(20);not null"`
+}
+
+func (HasilPemeriksaanLab) TableName() string {
+	return "hasil_pemeriksaan_lab"
+}
@@ -0,0 +1,17 @@
+package entity
+
+type JadwalOperasional struct {
+	ID               int32          `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdAdminPuskesmas int32          `gorm:"column:id_admin_puskesmas;type:integer;not null;uniqueIndex:idx_jadwal_operasional_hari"`
+	AdminPuskesmas   AdminPuskesmas `gorm:"foreignKey:IdAdminPuskesmas"`
+	Hari             int16          `gorm:"column:hari;type:smallint;not null;uniqueIndex:idx_jadwal_operasional_hari"`
+	JamBuka          string         `gorm:"column:jam_buka;type:varchar(5);not null"`
+	JamTutup         string         `gorm:"column:jam_tutup;type:varchar(5);not null"`
+	DurasiSlot       int32          `gorm:"column:durasi_slot;type:integer;not null"`
+	KuotaSlot        int32          `gorm:"column:kuota_slot;type:integer;not null"`
+	KuotaHarian      int32          `gorm:"column:kuota_harian;type:integer;not null"`
+}
+
+func (JadwalOperasional) TableName() string {
+	return "jadwal_operasional"
+}
@@ -0,0 +1,13 @@
+package entity
+
+type KodeCadangan struct {
+	ID          int32     `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdDuaFaktor int32     `gorm:"column:id_dua_faktor;type:integer;not null;index"`
+	DuaFaktor   DuaFaktor `gorm:"foreignKey:IdDuaFaktor"`
+	Hash        string    `gorm:"column:hash;type:varchar(64);not null"`
+	Digunakan   *int64    `gorm:"column:digunakan;type:bigint"`
+}
+
+func (KodeCadangan) TableName() string {
+	return "kode_cadangan"
+}
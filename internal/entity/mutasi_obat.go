@@ -0,0 +1,18 @@
+package entity
+
+type MutasiObat struct {
+	ID                int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdObat            int32   `gorm:"column:id_obat;type:integer;not null;index"`
+	Obat              Obat    `gorm:"foreignKey:IdObat"`
+	IdPengambilanObat *int32  `gorm:"column:id_pengambilan_obat;type:integer;index"`
+	Perubahan         int32   `gorm:"column:perubahan;type:integer;not null"`
+	JumlahAkhir       int32   `gorm:"column:jumlah_akhir;type:integer;not null"`
+	Alasan            string  `gorm:"column:alasan;type:alasan_mutasi_obat_enum;not null"`
+	IdAktor           *int32  `gorm:"column:id_aktor;type:integer"`
+	RoleAktor         *string `gorm:"column:role_aktor;type:varchar(20)"`
+	Waktu             int64   `gorm:"column:waktu;type:bigint;not null"`
+}
+
+func (MutasiObat) TableName() string {
+	return "mutasi_obat"
+}
@@ -0,0 +1,22 @@
+package entity
+
+type Notifikasi struct {
+	ID              int32    `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdPengguna      int32    `gorm:"column:id_pengguna;type:integer;not null;index"`
+	Pengguna        Pengguna `gorm:"foreignKey:IdPengguna"`
+	Jenis           string   `gorm:"column:jenis;type:varchar(50);not null"`
+	Kunci           *string  `gorm:"column:kunci;type:varchar(100);uniqueIndex"`
+	Judul           string   `gorm:"column:judul;type:varchar(255);not null"`
+	Isi             string   `gorm:"column:isi;type:text;not null"`
+	Data            string   `gorm:"column:data;type:jsonb;not null"`
+	Status          string   `gorm:"column:status;type:varchar(20);not null"`
+	Percobaan       int32    `gorm:"column:percobaan;type:integer;not null;default:0"`
+	Error           *string  `gorm:"column:error;type:text"`
+	Dibuat          int64    `gorm:"column:dibuat;type:bigint;not null"`
+	KirimBerikutnya int64    `gorm:"column:kirim_berikutnya;type:bigint;not null"`
+	Terkirim        *int64   `gorm:"column:terkirim;type:bigint"`
+}
+
+func (Notifikasi) TableName() string {
+	return "notifikasi"
+}
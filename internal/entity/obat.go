@@ -4,8 +4,10 @@ type Obat struct {
 	ID            int32       `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
 	NamaObat      string      `gorm:"column:nama_obat;type:varchar(100);not null"`
 	Jumlah        int32       `gorm:"column:jumlah;type:integer;not null"`
+	StokMinimum   int32       `gorm:"column:stok_minimum;type:integer;not null;default:0"`
 	IdAdminApotek int32       `gorm:"column:id_admin_apotek;type:integer;not null"`
 	AdminApotek   AdminApotek `gorm:"foreignKey:IdAdminApotek"`
+	Dihapus       *int64      `gorm:"column:dihapus;type:bigint"`
 }
 
 func (Obat) TableName() string {
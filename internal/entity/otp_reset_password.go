@@ -0,0 +1,18 @@
+package entity
+
+type OtpResetPassword struct {
+	ID             int32    `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdPengguna     int32    `gorm:"column:id_pengguna;type:integer;not null;index"`
+	Pengguna       Pengguna `gorm:"foreignKey:IdPengguna"`
+	HashOtp        string   `gorm:"column:hash_otp;type:varchar(64);not null"`
+	Percobaan      int32    `gorm:"column:percobaan;type:integer;not null;default:0"`
+	Dibuat         int64    `gorm:"column:dibuat;type:bigint;not null"`
+	Kedaluwarsa    int64    `gorm:"column:kedaluwarsa;type:bigint;not null"`
+	Terverifikasi  *int64   `gorm:"column:terverifikasi;type:bigint"`
+	HashTokenReset *string  `gorm:"column:hash_token_reset;type:varchar(64);uniqueIndex"`
+	Digunakan      *int64   `gorm:"column:digunakan;type:bigint"`
+}
+
+func (OtpResetPassword) TableName() string {
+	return "otp_reset_password"
+}
@@ -0,0 +1,20 @@
+package entity
+
+type Outbox struct {
+	ID              int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdEvent         string  `gorm:"column:id_event;type:uuid;not null;uniqueIndex:uq_outbox_id_event"`
+	Jenis           string  `gorm:"column:jenis;type:varchar(50);not null"`
+	Agregat         string  `gorm:"column:agregat;type:varchar(50);not null"`
+	IdAgregat       int32   `gorm:"column:id_agregat;type:integer;not null"`
+	Data            string  `gorm:"column:data;type:jsonb;not null"`
+	Status          string  `gorm:"column:status;type:varchar(20);not null"`
+	Percobaan       int32   `gorm:"column:percobaan;type:integer;not null;default:0"`
+	Error           *string `gorm:"column:error;type:text"`
+	Dibuat          int64   `gorm:"column:dibuat;type:bigint;not null"`
+	KirimBerikutnya int64   `gorm:"column:kirim_berikutnya;type:bigint;not null"`
+	Terkirim        *int64  `gorm:"column:terkirim;type:bigint"`
+}
+
+func (Outbox) TableName() string {
+	return "outbox"
+}
@@ -0,0 +1,14 @@
+package entity
+
+type PemeriksaanLab struct {
+	ID        int32    `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Kode      string   `gorm:"column:kode;type:varchar(30);not null;uniqueIndex:uq_pemeriksaan_lab_kode"`
+	Nama      string   `gorm:"column:nama;type:varchar(100);not null"`
+	Satuan    string   `gorm:"column:satuan;type:varchar(30);not null"`
+	NilaiMin  *float64 `gorm:"column:nilai_min;type:numeric(10,2)"`
+	NilaiMaks *float64 `gorm:"column:nilai_maks;type:numeric(10,2)"`
+}
+
+func (PemeriksaanLab) TableName() string {
+	return "pemeriksaan_lab"
+}
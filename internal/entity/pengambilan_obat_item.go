@@ -0,0 +1,13 @@
+package entity
+
+type PengambilanObatItem struct {
+	ID                int32 `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdPengambilanObat int32 `gorm:"column:id_pengambilan_obat;type:integer;not null;index"`
+	IdObat            int32 `gorm:"column:id_obat;type:integer;not null"`
+	Obat              Obat  `gorm:"foreignKey:IdObat"`
+	Jumlah            int32 `gorm:"column:jumlah;type:integer;not null"`
+}
+
+func (PengambilanObatItem) TableName() string {
+	return "pengambilan_obat_item"
+}
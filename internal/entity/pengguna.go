@@ -9,6 +9,7 @@ type Pengguna struct {
 	Alamat          string `gorm:"column:alamat;type:varchar(500);not null"`
 	Username        string `gorm:"column:username;type:varchar(50);unique;not null"`
 	Password        string `gorm:"column:password;type:varchar(255);not null"`
+	VersiToken      int32  `gorm:"column:versi_token;type:integer;not null;default:1"`
 }
 
 func (Pengguna) TableName() string {
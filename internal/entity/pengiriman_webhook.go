@@ -0,0 +1,21 @@
+package entity
+
+type PengirimanWebhook struct {
+	ID              int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdWebhook       int32   `gorm:"column:id_webhook;type:integer;not null;uniqueIndex:uq_pengiriman_webhook_event"`
+	Webhook         Webhook `gorm:"foreignKey:IdWebhook"`
+	IdEvent         string  `gorm:"column:id_event;type:uuid;not null;uniqueIndex:uq_pengiriman_webhook_event"`
+	Jenis           string  `gorm:"column:jenis;type:varchar(50);not null"`
+	Payload         string  `gorm:"column:payload;type:jsonb;not null"`
+	Status          string  `gorm:"column:status;type:varchar(20);not null"`
+	Percobaan       int32   `gorm:"column:percobaan;type:integer;not null;default:0"`
+	StatusHttp      *int32  `gorm:"column:status_http;type:integer"`
+	Error           *string `gorm:"column:error;type:text"`
+	Dibuat          int64   `gorm:"column:dibuat;type:bigint;not null"`
+	KirimBerikutnya int64   `gorm:"column:kirim_berikutnya;type:bigint;not null"`
+	Terkirim        *int64  `gorm:"column:terkirim;type:bigint"`
+}
+
+func (PengirimanWebhook) TableName() string {
+	return "pengiriman_webhook"
+}
@@ -0,0 +1,15 @@
+package entity
+
+type PercobaanLogin struct {
+	ID       int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Role     *string `gorm:"column:role;type:varchar(20)"`
+	Username *string `gorm:"column:username;type:varchar(50)"`
+	Ip       *string `gorm:"column:ip;type:varchar(45)"`
+	Status   string  `gorm:"column:status;type:status_percobaan_login_enum;not null"`
+	IdAktor  *int32  `gorm:"column:id_aktor;type:integer"`
+	Waktu    int64   `gorm:"column:waktu;type:bigint;not null"`
+}
+
+func (PercobaanLogin) TableName() string {
+	return "percobaan_login"
+}
@@ -0,0 +1,14 @@
+package entity
+
+type PeringatanStokObat struct {
+	ID          int32 `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdObat      int32 `gorm:"column:id_obat;type:integer;not null;index"`
+	Obat        Obat  `gorm:"foreignKey:IdObat"`
+	Jumlah      int32 `gorm:"column:jumlah;type:integer;not null"`
+	StokMinimum int32 `gorm:"column:stok_minimum;type:integer;not null"`
+	Waktu       int64 `gorm:"column:waktu;type:bigint;not null"`
+}
+
+func (PeringatanStokObat) TableName() string {
+	return "peringatan_stok_obat"
+}
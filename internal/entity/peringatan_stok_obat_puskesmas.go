@@ -0,0 +1,10 @@
+package entity
+
+type PeringatanStokObatPuskesmas struct {
+	IdPeringatanStokObat int32 `gorm:"column:id_peringatan_stok_obat;primaryKey;type:integer;autoIncrement:false;not null"`
+	IdAdminPuskesmas     int32 `gorm:"column:id_admin_puskesmas;primaryKey;type:integer;autoIncrement:false;not null;index"`
+}
+
+func (PeringatanStokObatPuskesmas) TableName() string {
+	return "peringatan_stok_obat_puskesmas"
+}
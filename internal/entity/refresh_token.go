@@ -0,0 +1,14 @@
+package entity
+
+type RefreshToken struct {
+	ID        int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	IdSesi    string `gorm:"column:id_sesi;type:varchar(32);not null;index"`
+	Sesi      Sesi   `gorm:"foreignKey:IdSesi"`
+	Hash      string `gorm:"column:hash;type:varchar(64);not null;uniqueIndex"`
+	Dibuat    int64  `gorm:"column:dibuat;type:bigint;not null"`
+	Digunakan *int64 `gorm:"column:digunakan;type:bigint"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
@@ -0,0 +1,15 @@
+package entity
+
+type RiwayatJob struct {
+	ID      int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Nama    string  `gorm:"column:nama;type:varchar(50);not null"`
+	Mulai   int64   `gorm:"column:mulai;type:bigint;not null"`
+	Selesai int64   `gorm:"column:selesai;type:bigint;not null"`
+	Status  string  `gorm:"column:status;type:varchar(20);not null"`
+	Jumlah  int32   `gorm:"column:jumlah;type:integer;not null;default:0"`
+	Error   *string `gorm:"column:error;type:text"`
+}
+
+func (RiwayatJob) TableName() string {
+	return "riwayat_job"
+}
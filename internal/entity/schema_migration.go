@@ -0,0 +1,12 @@
+package entity
+
+type SchemaMigration struct {
+	Versi    int64  `gorm:"column:versi;primaryKey;autoIncrement:false;type:bigint;not null"`
+	Nama     string `gorm:"column:nama;type:varchar(255);not null"`
+	Checksum string `gorm:"column:checksum;type:varchar(64);not null"`
+	Waktu    int64  `gorm:"column:waktu;type:bigint;not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
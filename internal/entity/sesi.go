@@ -0,0 +1,15 @@
+package entity
+
+type Sesi struct {
+	ID          string `gorm:"column:id;primaryKey;type:varchar(32);not null"`
+	IdAkun      int32  `gorm:"column:id_akun;type:integer;not null;index:idx_sesi_akun"`
+	Role        string `gorm:"column:role;type:varchar(20);not null;index:idx_sesi_akun"`
+	VersiToken  int32  `gorm:"column:versi_token;type:integer;not null"`
+	Dibuat      int64  `gorm:"column:dibuat;type:bigint;not null"`
+	Kedaluwarsa int64  `gorm:"column:kedaluwarsa;type:bigint;not null"`
+	Dicabut     *int64 `gorm:"column:dicabut;type:bigint"`
+}
+
+func (Sesi) TableName() string {
+	return "sesi"
+}
@@ -0,0 +1,18 @@
+package entity
+
+type TantanganDuaFaktor struct {
+	ID          int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Hash        string `gorm:"column:hash;type:varchar(64);not null;uniqueIndex"`
+	IdAkun      int32  `gorm:"column:id_akun;type:integer;not null"`
+	Role        string `gorm:"column:role;type:varchar(20);not null"`
+	Username    string `gorm:"column:username;type:varchar(50);not null"`
+	VersiToken  int32  `gorm:"column:versi_token;type:integer;not null"`
+	Percobaan   int32  `gorm:"column:percobaan;type:integer;not null;default:0"`
+	Dibuat      int64  `gorm:"column:dibuat;type:bigint;not null"`
+	Kedaluwarsa int64  `gorm:"column:kedaluwarsa;type:bigint;not null"`
+	Digunakan   *int64 `gorm:"column:digunakan;type:bigint"`
+}
+
+func (TantanganDuaFaktor) TableName() string {
+	return "tantangan_dua_faktor"
+}
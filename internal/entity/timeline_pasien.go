@@ -0,0 +1,15 @@
+package entity
+
+// dibaca dari view timeline_pasien yang menggabungkan kontrol balik dan pengambilan obat, tidak untuk ditulis
+type TimelinePasien struct {
+	Kunci       string `gorm:"column:kunci;primaryKey"`
+	Jenis       string `gorm:"column:jenis"`
+	IdReferensi int32  `gorm:"column:id_referensi"`
+	IdPasien    int32  `gorm:"column:id_pasien"`
+	Tanggal     int64  `gorm:"column:tanggal"`
+	Status      string `gorm:"column:status"`
+}
+
+func (TimelinePasien) TableName() string {
+	return "timeline_pasien"
+}
@@ -0,0 +1,15 @@
+package entity
+
+type Webhook struct {
+	ID     int32          `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Nama   string         `gorm:"column:nama;type:varchar(100);not null"`
+	Url    string         `gorm:"column:url;type:varchar(500);not null"`
+	Secret string         `gorm:"column:secret;type:varchar(100);not null"`
+	Aktif  bool           `gorm:"column:aktif;type:boolean;not null;default:true"`
+	Event  []WebhookEvent `gorm:"foreignKey:IdWebhook"`
+	Dibuat int64          `gorm:"column:dibuat;type:bigint;not null"`
+}
+
+func (Webhook) TableName() string {
+	return "webhook"
+}
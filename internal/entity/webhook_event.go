@@ -0,0 +1,10 @@
+package entity
+
+type WebhookEvent struct {
+	IdWebhook int32  `gorm:"column:id_webhook;primaryKey;type:integer;not null"`
+	Jenis     string `gorm:"column:jenis;primaryKey;type:varchar(50);not null"`
+}
+
+func (WebhookEvent) TableName() string {
+	return "webhook_event"
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/constant"
+)
+
+// role yang tidak tercantum pada sebuah permission selalu ditolak
+type Policy map[string]string
+
+var (
+	superSemua      = Policy{constant.RoleAdminSuper: constant.ScopeSemua}
+	superPuskesmas  = Policy{constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik}
+	superApotek     = Policy{constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminApotek: constant.ScopeMilik}
+	semuaRoleSemua  = Policy{constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeSemua, constant.RoleAdminApotek: constant.ScopeSemua, constant.RolePengguna: constant.ScopeSemua}
+	semuaRoleMilik  = Policy{constant.RoleAdminSuper: constant.ScopeMilik, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RoleAdminApotek: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik}
+	hanyaPuskesmas  = Policy{constant.RoleAdminPuskesmas: constant.ScopeMilik}
+	hanyaApotek     = Policy{constant.RoleAdminApotek: constant.ScopeMilik}
+	hanyaPengguna   = Policy{constant.RolePengguna: constant.ScopeMilik}
+	hanyaAdminSuper = Policy{constant.RoleAdminSuper: constant.ScopeMilik}
+	semuaAdminMilik = Policy{constant.RoleAdminSuper: constant.ScopeMilik, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RoleAdminApotek: constant.ScopeMilik}
+)
+
+var Policies = map[string]Policy{
+	constant.PermissionAuthLogout:      semuaRoleMilik,
+	constant.PermissionAuthLogoutSemua: semuaRoleMilik,
+
+	constant.PermissionAdminSuperPasswordUpdate: hanyaAdminSuper,
+
+	constant.PermissionDuaFaktorGet:          semuaAdminMilik,
+	constant.PermissionDuaFaktorEnrol:        semuaAdminMilik,
+	constant.PermissionDuaFaktorAktifkan:     semuaAdminMilik,
+	constant.PermissionDuaFaktorNonaktifkan:  semuaAdminMilik,
+	constant.PermissionDuaFaktorKodeCadangan: semuaAdminMilik,
+	constant.PermissionDuaFaktorReset:        superSemua,
+
+	constant.PermissionPercobaanLoginList:      superSemua,
+	constant.PermissionPercobaanLoginBukaKunci: superSemua,
+
+	constant.PermissionAuditList: superSemua,
+
+	constant.PermissionNotifikasiList:       superSemua,
+	constant.PermissionNotifikasiKirimUlang: superSemua,
+
+	constant.PermissionRiwayatJobList: superSemua,
+
+	constant.PermissionEventList:       superSemua,
+	constant.PermissionEventKirimUlang: superSemua,
+
+	constant.PermissionWebhookList:         superSemua,
+	constant.PermissionWebhookGet:          superSemua,
+	constant.PermissionWebhookCreate:       superSemua,
+	constant.PermissionWebhookUpdate:       superSemua,
+	constant.PermissionWebhookDelete:       superSemua,
+	constant.PermissionWebhookRotasiSecret: superSemua,
+	constant.PermissionWebhookPengiriman:   superSemua,
+	constant.PermissionWebhookKirimUlang:   superSemua,
+
+	constant.PermissionAdminPuskesmasList:                  semuaRoleSemua,
+	constant.PermissionAdminPuskesmasCurrent:               hanyaPuskesmas,
+	constant.PermissionAdminPuskesmasCurrentProfileUpdate:  hanyaPuskesmas,
+	constant.PermissionAdminPuskesmasCurrentPasswordUpdate: hanyaPuskesmas,
+	constant.PermissionAdminPuskesmasGet:                   superSemua,
+	constant.PermissionAdminPuskesmasCreate:                superSemua,
+	constant.PermissionAdminPuskesmasUpdate:                superSemua,
+	constant.PermissionAdminPuskesmasDelete:                superSemua,
+
+	constant.PermissionJadwalOperasionalCurrent:       hanyaPuskesmas,
+	constant.PermissionJadwalOperasionalCurrentUpdate: hanyaPuskesmas,
+	constant.PermissionJadwalOperasionalGet:           semuaRoleSemua,
+	constant.PermissionJadwalOperasionalUpdate:        superSemua,
+
+	constant.PermissionAdminApotekList:                  semuaRoleSemua,
+	constant.PermissionAdminApotekCurrent:               hanyaApotek,
+	constant.PermissionAdminApotekCurrentProfileUpdate:  hanyaApotek,
+	constant.PermissionAdminApotekCurrentPasswordUpdate: hanyaApotek,
+	constant.PermissionAdminApotekGet:                   superSemua,
+	constant.PermissionAdminApotekCreate:                superSemua,
+	constant.PermissionAdminApotekUpdate:                superSemua,
+	constant.PermissionAdminApotekDelete:                superSemua,
+
+	constant.PermissionPenggunaCurrent:                     hanyaPengguna,
+	constant.PermissionPenggunaCurrentProfileUpdate:        hanyaPengguna,
+	constant.PermissionPenggunaCurrentPasswordUpdate:       hanyaPengguna,
+	constant.PermissionPenggunaCurrentTokenPerangkatUpdate: hanyaPengguna,
+	constant.PermissionPenggunaList:                        {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeSemua},
+	constant.PermissionPenggunaGet:                         superSemua,
+	constant.PermissionPenggunaCreate:                      superSemua,
+	constant.PermissionPenggunaUpdate:                      superSemua,
+	constant.PermissionPenggunaDelete:                      superSemua,
+
+	constant.PermissionObatList:         {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminApotek: constant.ScopeMilik, constant.RoleAdminPuskesmas: constant.ScopeSemua},
+	constant.PermissionObatKedaluwarsa:  superApotek,
+	constant.PermissionObatPeringatan:   {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminApotek: constant.ScopeMilik, constant.RoleAdminPuskesmas: constant.ScopeMilik},
+	constant.PermissionObatGet:          superApotek,
+	constant.PermissionObatCreate:       superApotek,
+	constant.PermissionObatUpdate:       superApotek,
+	constant.PermissionObatDelete:       superApotek,
+	constant.PermissionObatMutasi:       superApotek,
+	constant.PermissionObatRestok:       superApotek,
+	constant.PermissionObatBatch:        superApotek,
+	constant.PermissionObatKoreksiBatch: superApotek,
+
+	constant.PermissionPasienSearch:     {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionPasienGet:        superPuskesmas,
+	constant.PermissionPasienCreate:     superPuskesmas,
+	constant.PermissionPasienUpdate:     superPuskesmas,
+	constant.PermissionPasienDelete:     superPuskesmas,
+	constant.PermissionPasienSelesai:    superPuskesmas,
+	constant.PermissionPasienTimeline:   {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionPasienTandaVital: {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionPasienHasilLab:   {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+
+	constant.PermissionKontrolBalikSearch:         {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionKontrolBalikSlot:           {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RolePengguna: constant.ScopeSemua},
+	constant.PermissionKontrolBalikGet:            superPuskesmas,
+	constant.PermissionKontrolBalikCreate:         superPuskesmas,
+	constant.PermissionKontrolBalikUpdate:         superPuskesmas,
+	constant.PermissionKontrolBalikDelete:         superPuskesmas,
+	constant.PermissionKontrolBalikSelesai:        superPuskesmas,
+	constant.PermissionKontrolBalikBatal:          superPuskesmas,
+	constant.PermissionKontrolBalikHasilLab:       superPuskesmas,
+	constant.PermissionKontrolBalikHasilLabUpdate: superPuskesmas,
+
+	constant.PermissionPemeriksaanLabList:   {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeSemua},
+	constant.PermissionPemeriksaanLabGet:    {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeSemua},
+	constant.PermissionPemeriksaanLabCreate: superSemua,
+	constant.PermissionPemeriksaanLabUpdate: superSemua,
+	constant.PermissionPemeriksaanLabDelete: superSemua,
+
+	constant.PermissionPengambilanObatSearch:    {constant.RoleAdminSuper: constant.ScopeSemua, constant.RoleAdminPuskesmas: constant.ScopeMilik, constant.RoleAdminApotek: constant.ScopeMilik, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionPengambilanObatGetByResi: superApotek,
+	constant.PermissionPengambilanObatGet:       superPuskesmas,
+	constant.PermissionPengambilanObatQrCode:    {constant.RoleAdminSuper: constant.ScopeSemua, constant.RolePengguna: constant.ScopeMilik},
+	constant.PermissionPengambilanObatCreate:    superPuskesmas,
+	constant.PermissionPengambilanObatUpdate:    superPuskesmas,
+	constant.PermissionPengambilanObatDelete:    superPuskesmas,
+	constant.PermissionPengambilanObatBatal:     superPuskesmas,
+	constant.PermissionPengambilanObatDiambil:   superApotek,
+
+	constant.PermissionArtikelCreate: superPuskesmas,
+	constant.PermissionArtikelUpdate: superPuskesmas,
+	constant.PermissionArtikelDelete: superPuskesmas,
+}
+
+// permission yang belum ada di tabel membuat server gagal start, bukan terbuka untuk semua role
+func PolicyMiddleware(permission string) fiber.Handler {
+	policy, ok := Policies[permission]
+	if !ok {
+		panic("permission " + permission + " belum terdaftar pada policy")
+	}
+	return func(ctx fiber.Ctx) error {
+		auth := GetAuth(ctx)
+		scope, ok := policy[auth.Role]
+		if !ok {
+			slog.Warn("Forbidden", "permission", permission, "role", auth.Role, "id", auth.ID)
+			return fiber.ErrForbidden
+		}
+		auth.Scope = scope
+		return ctx.Next()
+	}
+}
@@ -7,12 +7,12 @@ type AdminApotekResponse struct {
 	Alamat           string `json:"alamat"`
 	WaktuOperasional string `json:"waktuOperasional"`
 	Username         string `json:"username,omitempty"`
-	Token            string `json:"token,omitempty"`
 }
 type AdminApotekLoginRequest struct {
 	Username     string `json:"username" validate:"required,min=6,max=50,not_contain_space"`
 	Password     string `json:"password" validate:"required,min=6,max=255,is_password_format,not_contain_space"`
-	TokenCaptcha string `json:"tokenCaptcha" validate:"required,min=100"`
+	TokenCaptcha string `json:"tokenCaptcha" validate:"required,max=4096"`
+	Ip           string `json:"-"`
 }
 type AdminApotekPasswordUpdateRequest struct {
 	ID              int32  `validate:"required,numeric"`
@@ -27,8 +27,8 @@ type AdminApotekProfileUpdateRequest struct {
 	Alamat           string `json:"alamat" mod:"normalize_spaces" validate:"required,min=3,max=1000"`
 	WaktuOperasional string `json:"waktuOperasional" mod:"normalize_spaces" validate:"required,min=3,max=1000"`
 }
-type AdminApotekVerifyRequest struct {
-	ID int32 `validate:"required,numeric"`
+type AdminApotekListRequest struct {
+	PageRequest
 }
 type AdminApotekGetRequest struct {
 	ID int32 `validate:"required,numeric"`
@@ -7,12 +7,12 @@ type AdminPuskesmasResponse struct {
 	Alamat           string `json:"alamat"`
 	Username         string `json:"username,omitempty"`
 	WaktuOperasional string `json:"waktuOperasional"`
-	Token            string `json:"token,omitempty"`
 }
 type AdminPuskesmasLoginRequest struct {
 	Username     string `json:"username" validate:"required,min=6,max=50,not_contain_space"`
 	Password     string `json:"password" validate:"required,min=6,max=255,is_password_format,not_contain_space"`
-	TokenCaptcha string `json:"tokenCaptcha" validate:"required,min=100"`
+	TokenCaptcha string `json:"tokenCaptcha" validate:"required,max=4096"`
+	Ip           string `json:"-"`
 }
 type AdminPuskesmasPasswordUpdateRequest struct {
 	ID              int32  `validate:"required,numeric"`
@@ -27,8 +27,8 @@ type AdminPuskesmasProfileUpdateRequest struct {
 	Alamat           string `json:"alamat" mod:"normalize_spaces" validate:"required,min=3,max=1000"`
 	WaktuOperasional string `json:"waktuOperasional" mod:"normalize_spaces" validate:"required,min=3,max=1000"`
 }
-type AdminPuskesmasVerifyRequest struct {
-	ID int32 `validate:"required,numeric"`
+type AdminPuskesmasListRequest struct {
+	PageRequest
 }
 type AdminPuskesmasGetRequest struct {
 	ID int32 `validate:"required,numeric"`
@@ -15,6 +15,7 @@ type ArtikelGetRequest struct {
 	ID int32 `validate:"required,numeric"`
 }
 type ArtikelSearchRequest struct {
+	PageRequest
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric,gte=0"`
 }
 type ArtikelCreateRequest struct {
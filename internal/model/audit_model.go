@@ -0,0 +1,26 @@
+package model
+
+import "encoding/json"
+
+type AuditResponse struct {
+	ID        int32           `json:"id"`
+	IdAktor   *int32          `json:"idAktor,omitempty"`
+	RoleAktor *string         `json:"roleAktor,omitempty"`
+	Aksi      string          `json:"aksi"`
+	Entitas   string          `json:"entitas"`
+	IdEntitas int32           `json:"idEntitas"`
+	Perubahan json.RawMessage `json:"perubahan"`
+	Waktu     int64           `json:"waktu"`
+}
+type PerubahanAudit struct {
+	Sebelum any `json:"sebelum"`
+	Sesudah any `json:"sesudah"`
+}
+type AuditListRequest struct {
+	Entitas   string `validate:"omitempty,oneof=kontrol_balik pengambilan_obat pasien obat batch_obat pemeriksaan_lab"`
+	IdEntitas int32  `validate:"omitempty,numeric,min=1"`
+	Aksi      string `validate:"omitempty,oneof=buat ubah hapus batal selesai diambil restok koreksi"`
+	RoleAktor string `validate:"omitempty,oneof=super puskesmas apotek pengguna"`
+	IdAktor   int32  `validate:"omitempty,numeric,min=1"`
+	PageRequest
+}
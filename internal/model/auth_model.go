@@ -1,6 +1,55 @@
 package model
 
+import "prb_care_api/internal/constant"
+
 type Auth struct {
-	ID   int32
-	Role string
+	ID         int32
+	Role       string
+	VersiToken int32
+	IdSesi     string
+	Scope      string
+}
+
+// id pemilik untuk membatasi data sesuai scope policy, 0 berarti tidak dibatasi
+func (a *Auth) ScopeAdminPuskesmas() int32 {
+	return a.scope(constant.RoleAdminPuskesmas)
+}
+
+func (a *Auth) ScopeAdminApotek() int32 {
+	return a.scope(constant.RoleAdminApotek)
+}
+
+func (a *Auth) ScopePengguna() int32 {
+	return a.scope(constant.RolePengguna)
+}
+
+func (a *Auth) scope(role string) int32 {
+	if a.Scope == constant.ScopeMilik && a.Role == role {
+		return a.ID
+	}
+	return 0
+}
+
+// bila akun memakai 2fa, login hanya mengembalikan tokenDuaFaktor dan langkah berikutnya pada duaFaktor
+type TokenResponse struct {
+	Token          string   `json:"token,omitempty"`
+	RefreshToken   string   `json:"refreshToken,omitempty"`
+	DuaFaktor      string   `json:"duaFaktor,omitempty"`
+	TokenDuaFaktor string   `json:"tokenDuaFaktor,omitempty"`
+	KodeCadangan   []string `json:"kodeCadangan,omitempty"`
+}
+
+type AuthVerifyRequest struct {
+	ID         int32  `validate:"required,numeric"`
+	Role       string `validate:"required,oneof=super puskesmas apotek pengguna"`
+	VersiToken int32  `validate:"required,numeric"`
+	IdSesi     string `validate:"required,len=32,hexadecimal"`
+}
+
+type AuthRefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required,max=100"`
+}
+
+type AuthLogoutRequest struct {
+	Aktor *Auth `json:"-" validate:"required"`
 }
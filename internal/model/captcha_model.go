@@ -4,6 +4,6 @@ type CaptchaResponse struct {
 	Success bool `json:"success"`
 }
 type CaptchaRequest struct {
-	Secret       string `json:"secret"`
-	TokenCaptcha string `json:"response"`
+	Secret       string
+	TokenCaptcha string
 }
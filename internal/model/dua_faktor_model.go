@@ -0,0 +1,37 @@
+package model
+
+type DuaFaktorResponse struct {
+	Aktif            bool  `json:"aktif"`
+	Wajib            bool  `json:"wajib"`
+	SisaKodeCadangan int64 `json:"sisaKodeCadangan"`
+}
+type DuaFaktorEnrolResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauthUri"`
+	QrCode     string `json:"qrCode"`
+}
+type DuaFaktorKodeCadanganResponse struct {
+	KodeCadangan []string `json:"kodeCadangan"`
+}
+type DuaFaktorGetRequest struct {
+	Aktor *Auth `json:"-" validate:"required"`
+}
+type DuaFaktorEnrolRequest struct {
+	Aktor *Auth `json:"-" validate:"required"`
+}
+type DuaFaktorKodeRequest struct {
+	Kode  string `json:"kode" validate:"required,max=20"`
+	Aktor *Auth  `json:"-" validate:"required"`
+}
+type DuaFaktorResetRequest struct {
+	Role   string `validate:"required,oneof=super puskesmas apotek"`
+	IdAkun int32  `validate:"required,numeric"`
+}
+type DuaFaktorLoginEnrolRequest struct {
+	TokenDuaFaktor string `json:"tokenDuaFaktor" validate:"required,max=100"`
+}
+type DuaFaktorLoginRequest struct {
+	TokenDuaFaktor string `json:"tokenDuaFaktor" validate:"required,max=100"`
+	Kode           string `json:"kode" validate:"required,max=20"`
+	Ip             string `json:"-"`
+}
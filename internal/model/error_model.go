@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+)
+
+type ErrorResponse struct {
+	Kode   string               `json:"kode"`
+	Error  string               `json:"error"`
+	Detail []FieldErrorResponse `json:"detail,omitempty"`
+}
+type FieldErrorResponse struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+	Pesan string `json:"pesan"`
+}
+
+// dikembalikan service saat request tidak lolos validasi, ErrorHandler menerjemahkannya menjadi detail per field
+type ValidationError struct {
+	Errors validator.ValidationErrors
+}
+
+func (e *ValidationError) Error() string {
+	return e.Errors.Error()
+}
+
+// error domain yang dikenali lewat kode stabil, status http dan pesannya diambil ErrorHandler dari katalog
+type AppError struct {
+	Kode  string
+	Param []any
+}
+
+func NewAppError(kode string, param ...any) *AppError {
+	return &AppError{Kode: kode, Param: param}
+}
+
+func (e *AppError) Error() string {
+	if len(e.Param) == 0 {
+		return e.Kode
+	}
+	return fmt.Sprint(e.Kode, e.Param)
+}
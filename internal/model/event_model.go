@@ -0,0 +1,28 @@
+package model
+
+import "encoding/json"
+
+type EventResponse struct {
+	ID              int32           `json:"id"`
+	IdEvent         string          `json:"idEvent"`
+	Jenis           string          `json:"jenis"`
+	Agregat         string          `json:"agregat"`
+	IdAgregat       int32           `json:"idAgregat"`
+	Data            json.RawMessage `json:"data"`
+	Status          string          `json:"status"`
+	Percobaan       int32           `json:"percobaan"`
+	Error           *string         `json:"error,omitempty"`
+	Dibuat          int64           `json:"dibuat"`
+	KirimBerikutnya int64           `json:"kirimBerikutnya"`
+	Terkirim        *int64          `json:"terkirim,omitempty"`
+}
+type EventListRequest struct {
+	Jenis     string `validate:"omitempty,oneof=pasien_selesai kontrol_balik_dibuat kontrol_balik_diubah kontrol_balik_batal kontrol_balik_selesai pengambilan_obat_dibuat pengambilan_obat_diubah pengambilan_obat_batal pengambilan_obat_diambil"`
+	Agregat   string `validate:"omitempty,oneof=pasien kontrol_balik pengambilan_obat"`
+	IdAgregat int32  `validate:"omitempty,numeric,min=1"`
+	Status    string `validate:"omitempty,oneof=menunggu terkirim gagal"`
+	PageRequest
+}
+type EventKirimUlangRequest struct {
+	ID int32 `validate:"required,numeric"`
+}
@@ -4,5 +4,5 @@ import "mime/multipart"
 
 type File struct {
 	Name       string
-	FileHeader *multipart.FileHeader `validate:"image=1200x630+500"`
+	FileHeader *multipart.FileHeader `form:"banner" validate:"image=1200x630+500"`
 }
@@ -0,0 +1,57 @@
+package model
+
+type HasilPemeriksaanLabResponse struct {
+	ID             int32                   `json:"id"`
+	IdKontrolBalik int32                   `json:"idKontrolBalik"`
+	PemeriksaanLab *PemeriksaanLabResponse `json:"pemeriksaanLab"`
+	Nilai          float64                 `json:"nilai"`
+	NilaiMin       *float64                `json:"nilaiMin"`
+	NilaiMaks      *float64                `json:"nilaiMaks"`
+	Flag           string                  `json:"flag"`
+}
+
+type HasilPemeriksaanLabGetRequest struct {
+	ID               int32 `validate:"required,numeric"`
+	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+}
+type HasilPemeriksaanLabUpdateRequest struct {
+	ID               int32                                  `json:"id" validate:"required,numeric"`
+	IdAdminPuskesmas int32                                  `validate:"omitempty,numeric"`
+	Hasil            []HasilPemeriksaanLabItemUpdateRequest `json:"hasil" validate:"required,max=50,unique=IdPemeriksaanLab,dive"`
+	Aktor            *Auth                                  `json:"-"`
+}
+type HasilPemeriksaanLabItemUpdateRequest struct {
+	IdPemeriksaanLab int32    `json:"idPemeriksaanLab" validate:"required,numeric"`
+	Nilai            *float64 `json:"nilai" validate:"required,gte=0,lt=100000000"`
+}
+
+type RiwayatHasilPemeriksaanLabResponse struct {
+	IdPasien    int32                           `json:"idPasien"`
+	Pemeriksaan []RiwayatPemeriksaanLabResponse `json:"pemeriksaan"`
+}
+
+type RiwayatPemeriksaanLabResponse struct {
+	PemeriksaanLab PemeriksaanLabResponse  `json:"pemeriksaanLab"`
+	Jumlah         int                     `json:"jumlah"`
+	JumlahAbnormal int                     `json:"jumlahAbnormal"`
+	Terakhir       *TitikHasilLabResponse  `json:"terakhir,omitempty"`
+	Seri           []TitikHasilLabResponse `json:"seri"`
+}
+
+type TitikHasilLabResponse struct {
+	IdKontrolBalik int32    `json:"idKontrolBalik"`
+	TanggalKontrol int64    `json:"tanggalKontrol"`
+	Nilai          float64  `json:"nilai"`
+	NilaiMin       *float64 `json:"nilaiMin"`
+	NilaiMaks      *float64 `json:"nilaiMaks"`
+	Flag           string   `json:"flag"`
+}
+
+type RiwayatHasilPemeriksaanLabRequest struct {
+	ID               int32  `validate:"required,numeric"`
+	IdPengguna       int32  `validate:"omitempty,numeric"`
+	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
+	Kode             string `validate:"omitempty,max=30"`
+	TanggalMulai     int64  `validate:"omitempty,gte=0"`
+	TanggalSelesai   int64  `validate:"omitempty,gte=0"`
+}
@@ -0,0 +1,26 @@
+package model
+
+type JadwalOperasionalResponse struct {
+	Hari        int16  `json:"hari"`
+	JamBuka     string `json:"jamBuka"`
+	JamTutup    string `json:"jamTutup"`
+	DurasiSlot  int32  `json:"durasiSlot"`
+	KuotaSlot   int32  `json:"kuotaSlot"`
+	KuotaHarian int32  `json:"kuotaHarian"`
+}
+
+type JadwalOperasionalRequest struct {
+	Hari        int16  `json:"hari" validate:"numeric,gte=0,lte=6"`
+	JamBuka     string `json:"jamBuka" validate:"required,datetime=15:04"`
+	JamTutup    string `json:"jamTutup" validate:"required,datetime=15:04"`
+	DurasiSlot  int32  `json:"durasiSlot" validate:"required,numeric,gt=0,lte=720"`
+	KuotaSlot   int32  `json:"kuotaSlot" validate:"required,numeric,gt=0"`
+	KuotaHarian int32  `json:"kuotaHarian" validate:"numeric,gte=0"`
+}
+type JadwalOperasionalGetRequest struct {
+	IdAdminPuskesmas int32 `validate:"required,numeric"`
+}
+type JadwalOperasionalUpdateRequest struct {
+	IdAdminPuskesmas int32                      `validate:"required,numeric"`
+	Jadwal           []JadwalOperasionalRequest `json:"jadwal" validate:"max=7,unique=Hari,dive"`
+}
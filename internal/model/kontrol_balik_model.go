@@ -1,23 +1,29 @@
 package model
 
 type KontrolBalikResponse struct {
-	ID             int32           `json:"id"`
-	NoAntrean      int32           `json:"noAntrean"`
-	IdPasien       int32           `json:"idPasien,omitempty"`
-	PasienResponse *PasienResponse `json:"pasien,omitempty"`
-	BeratBadan     int32           `json:"beratBadan"`
-	TinggiBadan    int32           `json:"tinggiBadan"`
-	TekananDarah   string          `json:"tekananDarah"`
-	DenyutNadi     int32           `json:"denyutNadi"`
-	HasilLab       string          `json:"hasilLab"`
-	HasilEkg       string          `json:"hasilEkg"`
-	TanggalKontrol int64           `json:"tanggalKontrol"`
-	HasilDiagnosa  string          `json:"hasilDiagnosa"`
-	Keluhan        string          `json:"keluhan"`
-	Status         string          `json:"status,omitempty"`
+	ID                    int32           `json:"id"`
+	NoAntrean             int32           `json:"noAntrean"`
+	IdPasien              int32           `json:"idPasien,omitempty"`
+	PasienResponse        *PasienResponse `json:"pasien,omitempty"`
+	BeratBadan            int32           `json:"beratBadan"`
+	TinggiBadan           int32           `json:"tinggiBadan"`
+	TekananDarah          string          `json:"tekananDarah"`
+	TekananDarahSistolik  int32           `json:"tekananDarahSistolik"`
+	TekananDarahDiastolik int32           `json:"tekananDarahDiastolik"`
+	TekananDarahLama      *string         `json:"tekananDarahLama,omitempty"`
+	Imt                   float64         `json:"imt"`
+	DenyutNadi            int32           `json:"denyutNadi"`
+	HasilLab              string          `json:"hasilLab"`
+	HasilEkg              string          `json:"hasilEkg"`
+	TanggalKontrol        int64           `json:"tanggalKontrol"`
+	WaktuKontrol          int64           `json:"waktuKontrol,omitempty"`
+	HasilDiagnosa         string          `json:"hasilDiagnosa"`
+	Keluhan               string          `json:"keluhan"`
+	Status                string          `json:"status,omitempty"`
 }
 
 type KontrolBalikSearchRequest struct {
+	PageRequest
 	IdPengguna       int32  `validate:"omitempty,numeric"`
 	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
 	Status           string `validate:"omitempty,oneof=menunggu selesai batal"`
@@ -30,33 +36,54 @@ type KontrolBalikCreateRequest struct {
 	IdPasien         int32 `json:"idPasien" validate:"required,numeric"`
 	TanggalKontrol   int64 `json:"tanggalKontrol" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
 }
 type KontrolBalikUpdateRequest struct {
-	ID               int32  `json:"id" validate:"required,numeric"`
-	NoAntrean        int32  `json:"noAntrean" validate:"required,numeric,gt=0"`
-	IdPasien         int32  `json:"idPasien" validate:"required,numeric"`
-	TanggalKontrol   int64  `json:"tanggalKontrol" validate:"required,numeric"`
-	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
-	BeratBadan       int32  `json:"beratBadan" validate:"numeric,gte=0"`
-	TinggiBadan      int32  `json:"tinggiBadan" validate:"numeric,gte=0"`
-	TekananDarah     string `json:"tekananDarah" mod:"normalize_spaces" validate:"max=20"`
-	DenyutNadi       int32  `json:"denyutNadi" validate:"numeric,gte=0"`
-	HasilLab         string `json:"hasilLab"`
-	HasilEkg         string `json:"hasilEkg"`
-	HasilDiagnosa    string `json:"hasilDiagnosa"`
-	Keluhan          string `json:"keluhan"`
+	ID                    int32  `json:"id" validate:"required,numeric"`
+	NoAntrean             int32  `json:"noAntrean" validate:"required,numeric,gt=0"`
+	IdPasien              int32  `json:"idPasien" validate:"required,numeric"`
+	TanggalKontrol        int64  `json:"tanggalKontrol" validate:"required,numeric"`
+	IdAdminPuskesmas      int32  `validate:"omitempty,numeric"`
+	BeratBadan            int32  `json:"beratBadan" validate:"numeric,gte=0"`
+	TinggiBadan           int32  `json:"tinggiBadan" validate:"numeric,gte=0"`
+	TekananDarah          string `json:"tekananDarah" validate:"omitempty,tekanan_darah"`
+	TekananDarahSistolik  int32  `json:"tekananDarahSistolik" validate:"required_with=TekananDarahDiastolik,omitempty,numeric,gte=40,lte=300,gtfield=TekananDarahDiastolik"`
+	TekananDarahDiastolik int32  `json:"tekananDarahDiastolik" validate:"required_with=TekananDarahSistolik,omitempty,numeric,gte=20,lte=200"`
+	DenyutNadi            int32  `json:"denyutNadi" validate:"numeric,gte=0"`
+	HasilLab              string `json:"hasilLab"`
+	HasilEkg              string `json:"hasilEkg"`
+	HasilDiagnosa         string `json:"hasilDiagnosa"`
+	Keluhan               string `json:"keluhan"`
+	Aktor                 *Auth  `json:"-"`
 }
 type KontrolBalikDeleteRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
 }
 
 type KontrolBalikSelesaiRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
 }
 
 type KontrolBalikBatalRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
+}
+
+type KontrolBalikSlotRequest struct {
+	IdAdminPuskesmas int32 `validate:"required,numeric"`
+	TanggalMulai     int64 `validate:"required,numeric"`
+	TanggalSelesai   int64 `validate:"required,numeric,gtefield=TanggalMulai"`
+}
+
+type KontrolBalikSlotResponse struct {
+	WaktuMulai   int64 `json:"waktuMulai"`
+	WaktuSelesai int64 `json:"waktuSelesai"`
+	Kuota        int32 `json:"kuota"`
+	Terisi       int32 `json:"terisi"`
+	Tersedia     int32 `json:"tersedia"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import "encoding/json"
+
+type NotifikasiResponse struct {
+	ID              int32           `json:"id"`
+	IdPengguna      int32           `json:"idPengguna"`
+	Jenis           string          `json:"jenis"`
+	Judul           string          `json:"judul"`
+	Isi             string          `json:"isi"`
+	Data            json.RawMessage `json:"data"`
+	Status          string          `json:"status"`
+	Percobaan       int32           `json:"percobaan"`
+	Error           *string         `json:"error,omitempty"`
+	Dibuat          int64           `json:"dibuat"`
+	KirimBerikutnya int64           `json:"kirimBerikutnya"`
+	Terkirim        *int64          `json:"terkirim,omitempty"`
+}
+type NotifikasiListRequest struct {
+	IdPengguna int32  `validate:"omitempty,numeric,min=1"`
+	Jenis      string `validate:"omitempty,oneof=kontrol_balik_dibuat kontrol_balik_dijadwalkan_ulang kontrol_balik_batal pengambilan_obat_dibuat pengambilan_obat_batal kontrol_balik_pengingat_h1 kontrol_balik_pengingat_hari_ini pengambilan_obat_pengingat_h1 pengambilan_obat_pengingat_hari_ini"`
+	Status     string `validate:"omitempty,oneof=menunggu terkirim gagal"`
+	PageRequest
+}
+type NotifikasiKirimUlangRequest struct {
+	ID int32 `validate:"required,numeric"`
+}
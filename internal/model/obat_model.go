@@ -1,14 +1,17 @@
 package model
 
 type ObatResponse struct {
-	ID            int32                `json:"id"`
-	IdAdminApotek int32                `json:"idAdminApotek,omitempty"`
-	AdminApotek   *AdminApotekResponse `json:"adminApotek,omitempty"`
-	NamaObat      string               `json:"namaObat"`
-	Jumlah        int32                `json:"jumlah"`
+	ID             int32                `json:"id"`
+	IdAdminApotek  int32                `json:"idAdminApotek,omitempty"`
+	AdminApotek    *AdminApotekResponse `json:"adminApotek,omitempty"`
+	NamaObat       string               `json:"namaObat"`
+	Jumlah         int32                `json:"jumlah"`
+	JumlahTersedia *int32               `json:"jumlahTersedia,omitempty"`
+	StokMinimum    int32                `json:"stokMinimum"`
 }
 
 type ObatListRequest struct {
+	PageRequest
 	IdAdminApotek int32 `validate:"omitempty,numeric"`
 }
 type ObatGetRequest struct {
@@ -16,18 +19,98 @@ type ObatGetRequest struct {
 	IdAdminApotek int32 `validate:"omitempty,numeric"`
 }
 type ObatCreateRequest struct {
-	NamaObat      string `json:"namaObat" mod:"normalize_spaces" validate:"required,min=3,max=50"`
-	Jumlah        int32  `json:"jumlah" validate:"required,numeric,gt=0"`
-	IdAdminApotek int32  `validate:"required,numeric"`
+	NamaObat           string `json:"namaObat" mod:"normalize_spaces" validate:"required,min=3,max=50"`
+	Jumlah             int32  `json:"jumlah" validate:"required,numeric,gt=0"`
+	StokMinimum        int32  `json:"stokMinimum" validate:"numeric,gte=0"`
+	NoBatch            string `json:"noBatch" mod:"normalize_spaces" validate:"omitempty,max=50"`
+	TanggalKedaluwarsa int64  `json:"tanggalKedaluwarsa" validate:"omitempty,numeric"`
+	IdAdminApotek      int32  `validate:"required,numeric"`
+	Aktor              *Auth  `json:"-"`
 }
 type ObatUpdateRequest struct {
 	ID                 int32  `json:"id" validate:"required,numeric"`
 	NamaObat           string `json:"namaObat" mod:"normalize_spaces" validate:"required,min=3,max=50"`
-	Jumlah             int32  `json:"jumlah" validate:"numeric,gte=0"`
+	StokMinimum        int32  `json:"stokMinimum" validate:"numeric,gte=0"`
 	CurrentAdminApotek bool   `validate:"omitempty"`
 	IdAdminApotek      int32  `json:"idAdminApotek" validate:"required,numeric"`
+	Aktor              *Auth  `json:"-"`
 }
 type ObatDeleteRequest struct {
 	ID            int32 `json:"id" validate:"required,numeric"`
 	IdAdminApotek int32 `validate:"omitempty,numeric"`
+	Aktor         *Auth `json:"-"`
+}
+type ObatRestokRequest struct {
+	ID                 int32  `json:"id" validate:"required,numeric"`
+	Jumlah             int32  `json:"jumlah" validate:"required,numeric,gt=0"`
+	NoBatch            string `json:"noBatch" mod:"normalize_spaces" validate:"required,max=50"`
+	TanggalKedaluwarsa int64  `json:"tanggalKedaluwarsa" validate:"required,numeric"`
+	IdAdminApotek      int32  `validate:"omitempty,numeric"`
+	Aktor              *Auth  `json:"-"`
+}
+
+type MutasiObatGetRequest struct {
+	IdObat        int32 `validate:"required,numeric"`
+	IdAdminApotek int32 `validate:"omitempty,numeric"`
+}
+
+type MutasiObatResponse struct {
+	ID                int32   `json:"id"`
+	IdPengambilanObat *int32  `json:"idPengambilanObat,omitempty"`
+	Perubahan         int32   `json:"perubahan"`
+	JumlahAkhir       int32   `json:"jumlahAkhir"`
+	Alasan            string  `json:"alasan"`
+	IdAktor           *int32  `json:"idAktor,omitempty"`
+	RoleAktor         *string `json:"roleAktor,omitempty"`
+	Waktu             int64   `json:"waktu"`
+}
+
+type MutasiObatListResponse struct {
+	IdObat      int32                `json:"idObat"`
+	Jumlah      int32                `json:"jumlah"`
+	TotalMutasi int64                `json:"totalMutasi"`
+	Sesuai      bool                 `json:"sesuai"`
+	Mutasi      []MutasiObatResponse `json:"mutasi"`
+}
+
+type BatchObatResponse struct {
+	ID                 int32         `json:"id"`
+	IdObat             int32         `json:"idObat"`
+	Obat               *ObatResponse `json:"obat,omitempty"`
+	NoBatch            string        `json:"noBatch"`
+	TanggalKedaluwarsa *int64        `json:"tanggalKedaluwarsa,omitempty"`
+	JumlahDiterima     int32         `json:"jumlahDiterima"`
+	Sisa               int32         `json:"sisa"`
+	TanggalDiterima    int64         `json:"tanggalDiterima"`
+}
+
+type BatchObatListRequest struct {
+	IdObat        int32 `validate:"required,numeric"`
+	IdAdminApotek int32 `validate:"omitempty,numeric"`
+}
+
+type BatchObatKoreksiRequest struct {
+	ID            int32 `json:"id" validate:"required,numeric"`
+	IdObat        int32 `json:"idObat" validate:"required,numeric"`
+	Sisa          int32 `json:"sisa" validate:"numeric,gte=0"`
+	IdAdminApotek int32 `validate:"omitempty,numeric"`
+	Aktor         *Auth `json:"-"`
+}
+
+type BatchObatKedaluwarsaRequest struct {
+	Hari          int32 `validate:"numeric,gte=0,lte=365"`
+	IdAdminApotek int32 `validate:"omitempty,numeric"`
+}
+
+type PeringatanStokObatListRequest struct {
+	IdAdminApotek    int32 `validate:"omitempty,numeric"`
+	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+}
+
+type PeringatanStokObatResponse struct {
+	ID          int32         `json:"id"`
+	Obat        *ObatResponse `json:"obat"`
+	Jumlah      int32         `json:"jumlah"`
+	StokMinimum int32         `json:"stokMinimum"`
+	Waktu       int64         `json:"waktu"`
 }
@@ -0,0 +1,25 @@
+package model
+
+type PageRequest struct {
+	Page           int    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor         string `query:"cursor" validate:"omitempty,max=500"`
+	Sort           string `query:"sort" validate:"omitempty,max=50"`
+	Order          string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Cari           string `query:"cari" validate:"omitempty,max=100"`
+	TanggalMulai   int64  `query:"tanggalMulai" validate:"omitempty,gte=0"`
+	TanggalSelesai int64  `query:"tanggalSelesai" validate:"omitempty,gte=0"`
+}
+
+type PageMetadata struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPage  int64  `json:"totalPage"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type PageResponse[T any] struct {
+	Data     []T           `json:"data"`
+	Metadata *PageMetadata `json:"metadata"`
+}
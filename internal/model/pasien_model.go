@@ -12,6 +12,7 @@ type PasienResponse struct {
 }
 
 type PasienSearchRequest struct {
+	PageRequest
 	IdPengguna       int32  `validate:"omitempty,numeric"`
 	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
 	Status           string `validate:"omitempty,oneof=aktif selesai"`
@@ -26,6 +27,7 @@ type PasienCreateRequest struct {
 	IdPengguna       int32  `json:"idPengguna" validate:"required,numeric"`
 	IdAdminPuskesmas int32  `json:"idAdminPuskesmas" validate:"required,numeric"`
 	TanggalDaftar    int64  `json:"tanggalDaftar" validate:"required,numeric"`
+	Aktor            *Auth  `json:"-"`
 }
 type PasienUpdateRequest struct {
 	ID                    int32  `json:"id" validate:"required,numeric"`
@@ -34,13 +36,33 @@ type PasienUpdateRequest struct {
 	CurrentAdminPuskesmas bool   `validate:"omitempty"`
 	IdAdminPuskesmas      int32  `json:"idAdminPuskesmas" validate:"required,numeric"`
 	TanggalDaftar         int64  `json:"tanggalDaftar" validate:"required,numeric"`
+	Aktor                 *Auth  `json:"-"`
 }
 type PasienDeleteRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
 }
 
 type PasienSelesaiRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
+}
+
+type PasienTimelineResponse struct {
+	Jenis           string                   `json:"jenis"`
+	Tanggal         int64                    `json:"tanggal"`
+	Status          string                   `json:"status"`
+	KontrolBalik    *KontrolBalikResponse    `json:"kontrolBalik,omitempty"`
+	PengambilanObat *PengambilanObatResponse `json:"pengambilanObat,omitempty"`
+}
+
+type PasienTimelineRequest struct {
+	PageRequest
+	ID               int32  `validate:"required,numeric"`
+	IdPengguna       int32  `validate:"omitempty,numeric"`
+	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
+	Jenis            string `validate:"omitempty,oneof=kontrol_balik pengambilan_obat"`
+	Status           string `validate:"omitempty,oneof=menunggu selesai diambil batal"`
 }
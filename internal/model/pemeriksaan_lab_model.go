@@ -0,0 +1,38 @@
+package model
+
+type PemeriksaanLabResponse struct {
+	ID        int32    `json:"id"`
+	Kode      string   `json:"kode"`
+	Nama      string   `json:"nama"`
+	Satuan    string   `json:"satuan"`
+	NilaiMin  *float64 `json:"nilaiMin"`
+	NilaiMaks *float64 `json:"nilaiMaks"`
+}
+
+type PemeriksaanLabListRequest struct {
+	PageRequest
+}
+type PemeriksaanLabGetRequest struct {
+	ID int32 `validate:"required,numeric"`
+}
+type PemeriksaanLabCreateRequest struct {
+	Kode      string   `json:"kode" mod:"trim,ucase" validate:"required,max=30,not_contain_space"`
+	Nama      string   `json:"nama" mod:"normalize_spaces" validate:"required,max=100"`
+	Satuan    string   `json:"satuan" mod:"normalize_spaces" validate:"required,max=30"`
+	NilaiMin  *float64 `json:"nilaiMin" validate:"omitempty,gte=0,lt=100000000"`
+	NilaiMaks *float64 `json:"nilaiMaks" validate:"omitempty,gte=0,lt=100000000"`
+	Aktor     *Auth    `json:"-"`
+}
+type PemeriksaanLabUpdateRequest struct {
+	ID        int32    `json:"id" validate:"required,numeric"`
+	Kode      string   `json:"kode" mod:"trim,ucase" validate:"required,max=30,not_contain_space"`
+	Nama      string   `json:"nama" mod:"normalize_spaces" validate:"required,max=100"`
+	Satuan    string   `json:"satuan" mod:"normalize_spaces" validate:"required,max=30"`
+	NilaiMin  *float64 `json:"nilaiMin" validate:"omitempty,gte=0,lt=100000000"`
+	NilaiMaks *float64 `json:"nilaiMaks" validate:"omitempty,gte=0,lt=100000000"`
+	Aktor     *Auth    `json:"-"`
+}
+type PemeriksaanLabDeleteRequest struct {
+	ID    int32 `json:"id" validate:"required,numeric"`
+	Aktor *Auth `json:"-"`
+}
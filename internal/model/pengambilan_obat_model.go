@@ -1,18 +1,30 @@
 package model
 
 type PengambilanObatResponse struct {
-	ID                 int32           `json:"id"`
-	Resi               string          `json:"resi,omitempty"`
-	IdPasien           int32           `json:"idPasien,omitempty"`
-	PasienResponse     *PasienResponse `json:"pasien,omitempty"`
-	IdObat             int32           `json:"idObat,omitempty"`
-	Obat               *ObatResponse   `json:"obat,omitempty"`
-	TanggalPengambilan int64           `json:"tanggalPengambilan"`
-	Jumlah             int32           `json:"jumlah"`
-	Status             string          `json:"status,omitempty"`
+	ID                 int32                         `json:"id"`
+	Resi               string                        `json:"resi,omitempty"`
+	IdPasien           int32                         `json:"idPasien,omitempty"`
+	PasienResponse     *PasienResponse               `json:"pasien,omitempty"`
+	IdAdminApotek      int32                         `json:"idAdminApotek,omitempty"`
+	AdminApotek        *AdminApotekResponse          `json:"adminApotek,omitempty"`
+	Obat               []PengambilanObatItemResponse `json:"obat"`
+	TanggalPengambilan int64                         `json:"tanggalPengambilan"`
+	Status             string                        `json:"status,omitempty"`
+}
+
+type PengambilanObatItemResponse struct {
+	IdObat   int32  `json:"idObat"`
+	NamaObat string `json:"namaObat,omitempty"`
+	Jumlah   int32  `json:"jumlah"`
+}
+
+type PengambilanObatItemRequest struct {
+	IdObat int32 `json:"idObat" validate:"required,numeric"`
+	Jumlah int32 `json:"jumlah" validate:"required,numeric,gt=0"`
 }
 
 type PengambilanObatSearchRequest struct {
+	PageRequest
 	IdPengguna       int32  `validate:"omitempty,numeric"`
 	IdAdminPuskesmas int32  `validate:"omitempty,numeric"`
 	IdAdminApotek    int32  `validate:"omitempty,numeric"`
@@ -24,31 +36,50 @@ type PengambilanObatGetRequest struct {
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
 }
 type PengambilanObatCreateRequest struct {
-	IdPasien           int32 `json:"idPasien" validate:"required,numeric"`
-	IdObat             int32 `json:"idObat" validate:"required,numeric"`
-	Jumlah             int32 `json:"jumlah" validate:"required,numeric,gt=0"`
-	TanggalPengambilan int64 `json:"tanggalPengambilan" validate:"required,numeric"`
-	IdAdminPuskesmas   int32 `validate:"omitempty,numeric"`
+	IdPasien           int32                        `json:"idPasien" validate:"required,numeric"`
+	Obat               []PengambilanObatItemRequest `json:"obat" validate:"required,min=1,max=50,unique=IdObat,dive"`
+	TanggalPengambilan int64                        `json:"tanggalPengambilan" validate:"required,numeric"`
+	IdAdminPuskesmas   int32                        `validate:"omitempty,numeric"`
+	Aktor              *Auth                        `json:"-"`
 }
 type PengambilanObatUpdateRequest struct {
-	ID                 int32 `json:"id" validate:"required,numeric"`
-	IdPasien           int32 `json:"idPasien" validate:"required,numeric"`
-	IdObat             int32 `json:"idObat" validate:"required,numeric"`
-	Jumlah             int32 `json:"jumlah" validate:"required,numeric,gt=0"`
-	TanggalPengambilan int64 `json:"tanggalPengambilan" validate:"required,numeric"`
-	IdAdminPuskesmas   int32 `validate:"omitempty,numeric"`
+	ID                 int32                        `json:"id" validate:"required,numeric"`
+	IdPasien           int32                        `json:"idPasien" validate:"required,numeric"`
+	Obat               []PengambilanObatItemRequest `json:"obat" validate:"required,min=1,max=50,unique=IdObat,dive"`
+	TanggalPengambilan int64                        `json:"tanggalPengambilan" validate:"required,numeric"`
+	IdAdminPuskesmas   int32                        `validate:"omitempty,numeric"`
+	Aktor              *Auth                        `json:"-"`
 }
 type PengambilanObatDeleteRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
 }
 
 type PengambilanObatDiambilRequest struct {
 	ID            int32 `json:"id" validate:"required,numeric"`
 	IdAdminApotek int32 `validate:"omitempty,numeric"`
+	Aktor         *Auth `json:"-"`
 }
 
 type PengambilanObatBatalRequest struct {
 	ID               int32 `json:"id" validate:"required,numeric"`
 	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	Aktor            *Auth `json:"-"`
+}
+
+type PengambilanObatResiRequest struct {
+	Resi          string `validate:"required,len=10,alphanum"`
+	IdAdminApotek int32  `validate:"omitempty,numeric"`
+}
+
+type PengambilanObatQrCodeRequest struct {
+	ID         int32  `validate:"required,numeric"`
+	IdPengguna int32  `validate:"omitempty,numeric"`
+	Format     string `validate:"omitempty,oneof=png svg"`
+}
+
+type PengambilanObatQrCodeResponse struct {
+	ContentType string
+	Data        []byte
 }
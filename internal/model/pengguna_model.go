@@ -7,7 +7,6 @@ type PenggunaResponse struct {
 	TeleponKeluarga string `json:"teleponKeluarga"`
 	Alamat          string `json:"alamat"`
 	Username        string `json:"username,omitempty"`
-	Token           string `json:"token,omitempty"`
 }
 
 type PenggunaRegisterRequest struct {
@@ -17,13 +16,14 @@ type PenggunaRegisterRequest struct {
 	Alamat          string `json:"alamat" mod:"normalize_spaces" validate:"required,min=3,max=500"`
 	Username        string `json:"username" validate:"required,min=6,max=50,not_contain_space"`
 	Password        string `json:"password" validate:"required,min=6,max=255,is_password_format,not_contain_space"`
-	TokenCaptcha    string `json:"tokenCaptcha" validate:"required,min=100"`
+	TokenCaptcha    string `json:"tokenCaptcha" validate:"required,max=4096"`
 }
 
 type PenggunaLoginRequest struct {
 	Username     string `json:"username" validate:"required,min=6,max=50,not_contain_space"`
 	Password     string `json:"password" validate:"required,min=6,max=255,is_password_format,not_contain_space"`
-	TokenCaptcha string `json:"tokenCaptcha" validate:"required,min=100"`
+	TokenCaptcha string `json:"tokenCaptcha" validate:"required,max=4096"`
+	Ip           string `json:"-"`
 }
 type PenggunaPasswordUpdateRequest struct {
 	ID              int32  `validate:"required,numeric"`
@@ -39,8 +39,8 @@ type PenggunaProfileUpdateRequest struct {
 	Alamat          string `json:"alamat" mod:"normalize_spaces" validate:"required,min=3,max=500"`
 }
 
-type PenggunaVerifyRequest struct {
-	ID int32 `validate:"required,numeric"`
+type PenggunaListRequest struct {
+	PageRequest
 }
 type PenggunaGetRequest struct {
 	ID int32 `validate:"required,numeric"`
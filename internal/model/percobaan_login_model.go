@@ -0,0 +1,22 @@
+package model
+
+type PercobaanLoginResponse struct {
+	ID       int32   `json:"id"`
+	Role     *string `json:"role,omitempty"`
+	Username *string `json:"username,omitempty"`
+	Ip       *string `json:"ip,omitempty"`
+	Status   string  `json:"status"`
+	IdAktor  *int32  `json:"idAktor,omitempty"`
+	Waktu    int64   `json:"waktu"`
+}
+type PercobaanLoginListRequest struct {
+	Role   string `validate:"omitempty,oneof=super puskesmas apotek pengguna"`
+	Status string `validate:"omitempty,oneof=berhasil gagal ditolak dibuka"`
+	PageRequest
+}
+type PercobaanLoginBukaKunciRequest struct {
+	Role     string `json:"role" validate:"required_with=Username,omitempty,oneof=super puskesmas apotek pengguna"`
+	Username string `json:"username" validate:"required_without=Ip,omitempty,max=50"`
+	Ip       string `json:"ip" validate:"required_without=Username,omitempty,ip"`
+	Aktor    *Auth  `json:"-" validate:"required"`
+}
@@ -0,0 +1,18 @@
+package model
+
+type LupaPasswordRequest struct {
+	Telepon      string `json:"telepon" validate:"required,min=10,max=16,not_contain_space"`
+	TokenCaptcha string `json:"tokenCaptcha" validate:"required,max=4096"`
+}
+type VerifikasiOtpRequest struct {
+	Telepon string `json:"telepon" validate:"required,min=10,max=16,not_contain_space"`
+	Otp     string `json:"otp" validate:"required,len=6,numeric"`
+}
+type VerifikasiOtpResponse struct {
+	TokenReset string `json:"tokenReset"`
+}
+type ResetPasswordRequest struct {
+	TokenReset      string `json:"tokenReset" validate:"required,max=64"`
+	NewPassword     string `json:"newPassword" validate:"required,min=6,max=255,is_password_format,not_contain_space"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=NewPassword"`
+}
@@ -0,0 +1,16 @@
+package model
+
+type RiwayatJobResponse struct {
+	ID      int32   `json:"id"`
+	Nama    string  `json:"nama"`
+	Mulai   int64   `json:"mulai"`
+	Selesai int64   `json:"selesai"`
+	Status  string  `json:"status"`
+	Jumlah  int32   `json:"jumlah"`
+	Error   *string `json:"error,omitempty"`
+}
+type RiwayatJobListRequest struct {
+	Nama   string `validate:"omitempty,oneof=pengingat batal_kontrol_balik_terlewat batal_pengambilan_obat_terlewat"`
+	Status string `validate:"omitempty,oneof=berhasil gagal"`
+	PageRequest
+}
@@ -0,0 +1,46 @@
+package model
+
+type TandaVitalResponse struct {
+	IdPasien  int32                       `json:"idPasien"`
+	Ambang    AmbangTandaVitalResponse    `json:"ambang"`
+	Ringkasan RingkasanTandaVitalResponse `json:"ringkasan"`
+	Seri      []TitikTandaVitalResponse   `json:"seri"`
+}
+
+type TitikTandaVitalResponse struct {
+	IdKontrolBalik        int32    `json:"idKontrolBalik"`
+	TanggalKontrol        int64    `json:"tanggalKontrol"`
+	BeratBadan            int32    `json:"beratBadan"`
+	TinggiBadan           int32    `json:"tinggiBadan"`
+	Imt                   float64  `json:"imt"`
+	TekananDarahSistolik  int32    `json:"tekananDarahSistolik"`
+	TekananDarahDiastolik int32    `json:"tekananDarahDiastolik"`
+	DenyutNadi            int32    `json:"denyutNadi"`
+	Peringatan            []string `json:"peringatan"`
+}
+
+type AmbangTandaVitalResponse struct {
+	SistolikMin    int     `json:"sistolikMin"`
+	SistolikMaks   int     `json:"sistolikMaks"`
+	DiastolikMin   int     `json:"diastolikMin"`
+	DiastolikMaks  int     `json:"diastolikMaks"`
+	DenyutNadiMin  int     `json:"denyutNadiMin"`
+	DenyutNadiMaks int     `json:"denyutNadiMaks"`
+	ImtMin         float64 `json:"imtMin"`
+	ImtMaks        float64 `json:"imtMaks"`
+}
+
+type RingkasanTandaVitalResponse struct {
+	Jumlah                      int                      `json:"jumlah"`
+	JumlahTekananDarahTinggi    int                      `json:"jumlahTekananDarahTinggi"`
+	TekananDarahTidakTerkontrol bool                     `json:"tekananDarahTidakTerkontrol"`
+	Terakhir                    *TitikTandaVitalResponse `json:"terakhir,omitempty"`
+}
+
+type TandaVitalRequest struct {
+	ID               int32 `validate:"required,numeric"`
+	IdPengguna       int32 `validate:"omitempty,numeric"`
+	IdAdminPuskesmas int32 `validate:"omitempty,numeric"`
+	TanggalMulai     int64 `validate:"omitempty,gte=0"`
+	TanggalSelesai   int64 `validate:"omitempty,gte=0"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "encoding/json"
+
+type WebhookResponse struct {
+	ID     int32    `json:"id"`
+	Nama   string   `json:"nama"`
+	Url    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Event  []string `json:"event"`
+	Aktif  bool     `json:"aktif"`
+	Dibuat int64    `json:"dibuat"`
+}
+type WebhookListRequest struct {
+	PageRequest
+}
+type WebhookGetRequest struct {
+	ID int32 `validate:"required,numeric"`
+}
+type WebhookCreateRequest struct {
+	Nama  string   `json:"nama" mod:"normalize_spaces" validate:"required,min=3,max=100"`
+	Url   string   `json:"url" validate:"required,http_url,max=500"`
+	Event []string `json:"event" validate:"required,min=1,unique,dive,oneof=pasien_selesai kontrol_balik_dibuat kontrol_balik_diubah kontrol_balik_batal kontrol_balik_selesai pengambilan_obat_dibuat pengambilan_obat_diubah pengambilan_obat_batal pengambilan_obat_diambil"`
+}
+type WebhookUpdateRequest struct {
+	ID    int32    `json:"id" validate:"required,numeric"`
+	Nama  string   `json:"nama" mod:"normalize_spaces" validate:"required,min=3,max=100"`
+	Url   string   `json:"url" validate:"required,http_url,max=500"`
+	Event []string `json:"event" validate:"required,min=1,unique,dive,oneof=pasien_selesai kontrol_balik_dibuat kontrol_balik_diubah kontrol_balik_batal kontrol_balik_selesai pengambilan_obat_dibuat pengambilan_obat_diubah pengambilan_obat_batal pengambilan_obat_diambil"`
+	Aktif bool     `json:"aktif"`
+}
+type WebhookDeleteRequest struct {
+	ID int32 `json:"id" validate:"required,numeric"`
+}
+type WebhookRotasiSecretRequest struct {
+	ID int32 `json:"id" validate:"required,numeric"`
+}
+
+type PengirimanWebhookResponse struct {
+	ID              int32           `json:"id"`
+	IdWebhook       int32           `json:"idWebhook"`
+	IdEvent         string          `json:"idEvent"`
+	Jenis           string          `json:"jenis"`
+	Payload         json.RawMessage `json:"payload"`
+	Status          string          `json:"status"`
+	Percobaan       int32           `json:"percobaan"`
+	StatusHttp      *int32          `json:"statusHttp,omitempty"`
+	Error           *string         `json:"error,omitempty"`
+	Dibuat          int64           `json:"dibuat"`
+	KirimBerikutnya int64           `json:"kirimBerikutnya"`
+	Terkirim        *int64          `json:"terkirim,omitempty"`
+}
+type PengirimanWebhookListRequest struct {
+	IdWebhook int32  `validate:"omitempty,numeric,min=1"`
+	Jenis     string `validate:"omitempty,oneof=pasien_selesai kontrol_balik_dibuat kontrol_balik_diubah kontrol_balik_batal kontrol_balik_selesai pengambilan_obat_dibuat pengambilan_obat_diubah pengambilan_obat_batal pengambilan_obat_diambil"`
+	Status    string `validate:"omitempty,oneof=menunggu terkirim gagal"`
+	PageRequest
+}
+type PengirimanWebhookKirimUlangRequest struct {
+	ID int32 `validate:"required,numeric"`
+}
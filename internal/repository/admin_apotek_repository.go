@@ -33,6 +33,22 @@ func (r *AdminApotekRepository) CountByTelepon(db *gorm.DB, telepon any) (int64,
 	}
 	return count, nil
 }
-func (r *AdminApotekRepository) FindAll(db *gorm.DB, adminApotek *[]entity.AdminApotek) error {
-	return db.Find(adminApotek).Error
+
+var adminApotekPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":         "admin_apotek.id",
+		"namaApotek": "admin_apotek.nama_apotek",
+		"username":   "admin_apotek.username",
+	},
+	SortDefault: "id",
+	IdColumn:    "admin_apotek.id",
+	CariColumn:  []string{"admin_apotek.nama_apotek", "admin_apotek.username", "admin_apotek.alamat"},
+}
+
+func (r *AdminApotekRepository) Search(db *gorm.DB, adminApotek *[]entity.AdminApotek, page *PageQuery) (*PageResult, error) {
+	return Paginate(db, adminApotek, page, adminApotekPageOption)
+}
+
+func (r *AdminApotekRepository) IncrementVersiTokenById(db *gorm.DB, id int32) error {
+	return db.Model(new(entity.AdminApotek)).Where("id = ?", id).Update("versi_token", gorm.Expr("versi_token + 1")).Error
 }
@@ -30,9 +30,25 @@ func (r *AdminPuskesmasRepository) CountByTelepon(db *gorm.DB, telepon any) (int
 	}
 	return count, nil
 }
-func (r *AdminPuskesmasRepository) FindAll(db *gorm.DB, adminPuskesmas *[]entity.AdminPuskesmas) error {
-	return db.Find(adminPuskesmas).Error
+
+var adminPuskesmasPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":            "admin_puskesmas.id",
+		"namaPuskesmas": "admin_puskesmas.nama_puskesmas",
+		"username":      "admin_puskesmas.username",
+	},
+	SortDefault: "id",
+	IdColumn:    "admin_puskesmas.id",
+	CariColumn:  []string{"admin_puskesmas.nama_puskesmas", "admin_puskesmas.username", "admin_puskesmas.alamat"},
+}
+
+func (r *AdminPuskesmasRepository) Search(db *gorm.DB, adminPuskesmas *[]entity.AdminPuskesmas, page *PageQuery) (*PageResult, error) {
+	return Paginate(db, adminPuskesmas, page, adminPuskesmasPageOption)
 }
 func (r *AdminPuskesmasRepository) FindById(db *gorm.DB, adminPuskesmas *entity.AdminPuskesmas, id int32) error {
 	return db.Where("id = ?", id).First(adminPuskesmas).Error
 }
+
+func (r *AdminPuskesmasRepository) IncrementVersiTokenById(db *gorm.DB, id int32) error {
+	return db.Model(new(entity.AdminPuskesmas)).Where("id = ?", id).Update("versi_token", gorm.Expr("versi_token + 1")).Error
+}
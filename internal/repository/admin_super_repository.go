@@ -19,3 +19,7 @@ func (r *AdminSuperRepository) FindByUsername(db *gorm.DB, adminSuper *entity.Ad
 func (r *AdminSuperRepository) FindById(db *gorm.DB, adminSuper *entity.AdminSuper, id int32) error {
 	return db.Where("id = ?", id).First(adminSuper).Error
 }
+
+func (r *AdminSuperRepository) IncrementVersiTokenById(db *gorm.DB, id int32) error {
+	return db.Model(new(entity.AdminSuper)).Where("id = ?", id).Update("versi_token", gorm.Expr("versi_token + 1")).Error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type AlokasiBatchObatRepository struct {
+	Repository[entity.AlokasiBatchObat]
+}
+
+func NewAlokasiBatchObatRepository() *AlokasiBatchObatRepository {
+	return &AlokasiBatchObatRepository{}
+}
+
+func (r *AlokasiBatchObatRepository) FindAllByIdPengambilanObatItem(db *gorm.DB, alokasiBatchObat *[]entity.AlokasiBatchObat, idPengambilanObatItem []int32) error {
+	return db.Where("id_pengambilan_obat_item IN ?", idPengambilanObatItem).Order("id").Find(alokasiBatchObat).Error
+}
+func (r *AlokasiBatchObatRepository) DeleteByIdPengambilanObatItem(db *gorm.DB, idPengambilanObatItem []int32) error {
+	return db.Where("id_pengambilan_obat_item IN ?", idPengambilanObatItem).Delete(&entity.AlokasiBatchObat{}).Error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type AntreanKontrolBalikRepository struct {
+	Repository[entity.AntreanKontrolBalik]
+}
+
+func NewAntreanKontrolBalikRepository() *AntreanKontrolBalikRepository {
+	return &AntreanKontrolBalikRepository{}
+}
+
+func (r *AntreanKontrolBalikRepository) FirstOrCreateAndLockForUpdate(db *gorm.DB, antrean *entity.AntreanKontrolBalik) error {
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(antrean).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_admin_puskesmas = ?", antrean.IdAdminPuskesmas).
+		Where("tanggal_kontrol = ?", antrean.TanggalKontrol).
+		First(antrean).Error
+}
@@ -13,12 +13,24 @@ func NewArtikelRepository() *ArtikelRepository {
 	return &ArtikelRepository{}
 }
 
-func (r *ArtikelRepository) Search(db *gorm.DB, artikel *[]entity.Artikel, idAdminPuskesmas int32) error {
+var artikelPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":               "artikel.id",
+		"judul":            "artikel.judul",
+		"tanggalPublikasi": "artikel.tanggal_publikasi",
+	},
+	SortDefault: "id",
+	IdColumn:    "artikel.id",
+	CariColumn:  []string{"artikel.judul", "artikel.ringkasan"},
+	DateColumn:  "artikel.tanggal_publikasi",
+}
+
+func (r *ArtikelRepository) Search(db *gorm.DB, artikel *[]entity.Artikel, idAdminPuskesmas int32, page *PageQuery) (*PageResult, error) {
 	query := db
 	if idAdminPuskesmas != 0 {
 		query = query.Where("id_admin_puskesmas = ?", idAdminPuskesmas)
 	}
-	return query.Preload("AdminPuskesmas").Find(artikel).Error
+	return Paginate(query.Preload("AdminPuskesmas"), artikel, page, artikelPageOption)
 }
 
 func (r *ArtikelRepository) FindById(db *gorm.DB, artikel *entity.Artikel, id int32) error {
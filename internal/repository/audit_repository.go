@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type AuditRepository struct {
+	Repository[entity.Audit]
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+var auditPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":    "id",
+		"waktu": "waktu",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	DateColumn:  "waktu",
+}
+
+func (r *AuditRepository) Search(db *gorm.DB, audit *[]entity.Audit, entitas string, idEntitas int32, aksi string, roleAktor string, idAktor int32, page *PageQuery) (*PageResult, error) {
+	query := db
+	if entitas != "" {
+		query = query.Where("entitas = ?", entitas)
+	}
+	if idEntitas != 0 {
+		query = query.Where("id_entitas = ?", idEntitas)
+	}
+	if aksi != "" {
+		query = query.Where("aksi = ?", aksi)
+	}
+	if roleAktor != "" {
+		query = query.Where("role_aktor = ?", roleAktor)
+	}
+	if idAktor != 0 {
+		query = query.Where("id_aktor = ?", idAktor)
+	}
+	return Paginate(query, audit, page, auditPageOption)
+}
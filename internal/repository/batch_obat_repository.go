@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type BatchObatRepository struct {
+	Repository[entity.BatchObat]
+}
+
+func NewBatchObatRepository() *BatchObatRepository {
+	return &BatchObatRepository{}
+}
+
+func (r *BatchObatRepository) FindAllByIdObat(db *gorm.DB, batchObat *[]entity.BatchObat, idObat int32) error {
+	return db.Where("id_obat = ?", idObat).
+		Order("tanggal_kedaluwarsa ASC NULLS LAST").
+		Order("id").
+		Find(batchObat).Error
+}
+func (r *BatchObatRepository) FindAllTersediaByIdObatAndLockForUpdate(db *gorm.DB, batchObat *[]entity.BatchObat, idObat int32, sekarang int64) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_obat = ?", idObat).
+		Where("sisa > 0").
+		Where("tanggal_kedaluwarsa IS NULL OR tanggal_kedaluwarsa > ?", sekarang).
+		Order("tanggal_kedaluwarsa ASC NULLS LAST").
+		Order("id").
+		Find(batchObat).Error
+}
+func (r *BatchObatRepository) FindByIdAndIdObatAndLockForUpdate(db *gorm.DB, batchObat *entity.BatchObat, id int32, idObat int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("id_obat = ?", idObat).
+		First(batchObat).Error
+}
+func (r *BatchObatRepository) FindByIdAndLockForUpdate(db *gorm.DB, batchObat *entity.BatchObat, id int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(batchObat).Error
+}
+func (r *BatchObatRepository) FindByIdObatAndNoBatchAndLockForUpdate(db *gorm.DB, batchObat *entity.BatchObat, idObat int32, noBatch string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_obat = ?", idObat).
+		Where("no_batch = ?", noBatch).
+		First(batchObat).Error
+}
+func (r *BatchObatRepository) FindAllKedaluwarsa(db *gorm.DB, batchObat *[]entity.BatchObat, batas int64) error {
+	return db.Joins("JOIN obat ON obat.id = batch_obat.id_obat").
+		Where("obat.dihapus IS NULL").
+		Where("batch_obat.sisa > 0").
+		Where("batch_obat.tanggal_kedaluwarsa <= ?", batas).
+		Preload("Obat.AdminApotek").
+		Order("batch_obat.tanggal_kedaluwarsa").
+		Find(batchObat).Error
+}
+func (r *BatchObatRepository) FindAllKedaluwarsaByIdAdminApotek(db *gorm.DB, batchObat *[]entity.BatchObat, batas int64, idAdminApotek int32) error {
+	return db.Joins("JOIN obat ON obat.id = batch_obat.id_obat").
+		Where("obat.id_admin_apotek = ?", idAdminApotek).
+		Where("obat.dihapus IS NULL").
+		Where("batch_obat.sisa > 0").
+		Where("batch_obat.tanggal_kedaluwarsa <= ?", batas).
+		Preload("Obat").
+		Order("batch_obat.tanggal_kedaluwarsa").
+		Find(batchObat).Error
+}
+func (r *BatchObatRepository) SumSisaKedaluwarsaByIdObat(db *gorm.DB, idObat []int32, sekarang int64) (map[int32]int32, error) {
+	var rows []struct {
+		IdObat int32
+		Sisa   int32
+	}
+	if err := db.Model(&entity.BatchObat{}).
+		Select("id_obat, COALESCE(SUM(sisa), 0) AS sisa").
+		Where("id_obat IN ?", idObat).
+		Where("sisa > 0").
+		Where("tanggal_kedaluwarsa <= ?", sekarang).
+		Group("id_obat").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	sisa := make(map[int32]int32, len(rows))
+	for _, row := range rows {
+		sisa[row.IdObat] = row.Sisa
+	}
+	return sisa, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type DuaFaktorRepository struct {
+	Repository[entity.DuaFaktor]
+}
+
+func NewDuaFaktorRepository() *DuaFaktorRepository {
+	return &DuaFaktorRepository{}
+}
+
+func (r *DuaFaktorRepository) FindByIdAkunAndRole(db *gorm.DB, duaFaktor *entity.DuaFaktor, idAkun int32, role string) error {
+	return db.Where("id_akun = ? AND role = ?", idAkun, role).First(duaFaktor).Error
+}
+
+func (r *DuaFaktorRepository) FindByIdAkunAndRoleAndLockForUpdate(db *gorm.DB, duaFaktor *entity.DuaFaktor, idAkun int32, role string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id_akun = ? AND role = ?", idAkun, role).First(duaFaktor).Error
+}
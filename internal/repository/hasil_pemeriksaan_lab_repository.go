@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type HasilPemeriksaanLabRepository struct {
+	Repository[entity.HasilPemeriksaanLab]
+}
+
+func NewHasilPemeriksaanLabRepository() *HasilPemeriksaanLabRepository {
+	return &HasilPemeriksaanLabRepository{}
+}
+
+func (r *HasilPemeriksaanLabRepository) FindAllByIdKontrolBalik(db *gorm.DB, hasil *[]entity.HasilPemeriksaanLab, idKontrolBalik int32) error {
+	return db.Preload("PemeriksaanLab").
+		Where("id_kontrol_balik = ?", idKontrolBalik).
+		Order("id").
+		Find(hasil).Error
+}
+func (r *HasilPemeriksaanLabRepository) FindByIdPemeriksaanLab(db *gorm.DB, hasil *entity.HasilPemeriksaanLab, idPemeriksaanLab int32) error {
+	return db.Where("id_pemeriksaan_lab = ?", idPemeriksaanLab).First(hasil).Error
+}
+
+// idPemeriksaanLab 0 berarti semua pemeriksaan
+func (r *HasilPemeriksaanLabRepository) FindAllByIdPasienAndTanggalKontrolBetweenAndStatusOrStatus(db *gorm.DB, hasil *[]entity.HasilPemeriksaanLab, idPasien int32, idPemeriksaanLab int32, tanggalAwal int64, tanggalAkhir int64, status1 string, status2 string) error {
+	query := db.Joins("JOIN kontrol_balik ON kontrol_balik.id = hasil_pemeriksaan_lab.id_kontrol_balik").
+		Where("kontrol_balik.id_pasien = ?", idPasien).
+		Where("kontrol_balik.tanggal_kontrol BETWEEN ? AND ?", tanggalAwal, tanggalAkhir).
+		Where("kontrol_balik.status = ? OR kontrol_balik.status = ?", status1, status2)
+	if idPemeriksaanLab != 0 {
+		query = query.Where("hasil_pemeriksaan_lab.id_pemeriksaan_lab = ?", idPemeriksaanLab)
+	}
+	return query.Preload("KontrolBalik").
+		Preload("PemeriksaanLab").
+		Order("kontrol_balik.tanggal_kontrol").
+		Order("hasil_pemeriksaan_lab.id").
+		Find(hasil).Error
+}
+func (r *HasilPemeriksaanLabRepository) DeleteByIdKontrolBalik(db *gorm.DB, idKontrolBalik int32) error {
+	return db.Where("id_kontrol_balik = ?", idKontrolBalik).Delete(&entity.HasilPemeriksaanLab{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type JadwalOperasionalRepository struct {
+	Repository[entity.JadwalOperasional]
+}
+
+func NewJadwalOperasionalRepository() *JadwalOperasionalRepository {
+	return &JadwalOperasionalRepository{}
+}
+
+func (r *JadwalOperasionalRepository) FindAllByIdAdminPuskesmas(db *gorm.DB, jadwal *[]entity.JadwalOperasional, idAdminPuskesmas int32) error {
+	return db.Where("id_admin_puskesmas = ?", idAdminPuskesmas).Order("hari").Find(jadwal).Error
+}
+func (r *JadwalOperasionalRepository) FindByIdAdminPuskesmasAndHariAndLockForUpdate(db *gorm.DB, jadwal *entity.JadwalOperasional, idAdminPuskesmas int32, hari int16) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_admin_puskesmas = ?", idAdminPuskesmas).
+		Where("hari = ?", hari).
+		First(jadwal).Error
+}
+func (r *JadwalOperasionalRepository) CountByIdAdminPuskesmas(db *gorm.DB, idAdminPuskesmas int32) (int64, error) {
+	var count int64
+	if err := db.Model(&entity.JadwalOperasional{}).Where("id_admin_puskesmas = ?", idAdminPuskesmas).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// baris yang sudah ada diperbarui di tempat agar kontrol balik yang menunggu kunci barisnya tetap menemukannya
+func (r *JadwalOperasionalRepository) Upsert(db *gorm.DB, jadwal *entity.JadwalOperasional) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id_admin_puskesmas"}, {Name: "hari"}},
+		DoUpdates: clause.AssignmentColumns([]string{"jam_buka", "jam_tutup", "durasi_slot", "kuota_slot", "kuota_harian"}),
+	}).Create(jadwal).Error
+}
+func (r *JadwalOperasionalRepository) DeleteByIdAdminPuskesmasAndHariNotIn(db *gorm.DB, idAdminPuskesmas int32, hari []int16) error {
+	query := db.Where("id_admin_puskesmas = ?", idAdminPuskesmas)
+	if len(hari) > 0 {
+		query = query.Where("hari NOT IN ?", hari)
+	}
+	return query.Delete(&entity.JadwalOperasional{}).Error
+}
+func (r *JadwalOperasionalRepository) DeleteByIdAdminPuskesmas(db *gorm.DB, idAdminPuskesmas int32) error {
+	return db.Where("id_admin_puskesmas = ?", idAdminPuskesmas).Delete(&entity.JadwalOperasional{}).Error
+}
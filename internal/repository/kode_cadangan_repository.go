@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type KodeCadanganRepository struct {
+	Repository[entity.KodeCadangan]
+}
+
+func NewKodeCadanganRepository() *KodeCadanganRepository {
+	return &KodeCadanganRepository{}
+}
+
+func (r *KodeCadanganRepository) FindSisaByIdDuaFaktorAndHashAndLockForUpdate(db *gorm.DB, kodeCadangan *entity.KodeCadangan, idDuaFaktor int32, hash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id_dua_faktor = ? AND hash = ? AND digunakan IS NULL", idDuaFaktor, hash).First(kodeCadangan).Error
+}
+
+func (r *KodeCadanganRepository) CountSisaByIdDuaFaktor(db *gorm.DB, idDuaFaktor int32) (int64, error) {
+	var count int64
+	if err := db.Model(&entity.KodeCadangan{}).Where("id_dua_faktor = ? AND digunakan IS NULL", idDuaFaktor).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *KodeCadanganRepository) DeleteByIdDuaFaktor(db *gorm.DB, idDuaFaktor int32) error {
+	return db.Where("id_dua_faktor = ?", idDuaFaktor).Delete(&entity.KodeCadangan{}).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"prb_care_api/internal/entity"
 )
 
@@ -13,34 +14,76 @@ func NewKontrolBalikRepository() *KontrolBalikRepository {
 	return &KontrolBalikRepository{}
 }
 
-func (r *KontrolBalikRepository) Search(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, status string) error {
-	query := db
+var kontrolBalikPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":             "kontrol_balik.id",
+		"noAntrean":      "kontrol_balik.no_antrean",
+		"tanggalKontrol": "kontrol_balik.tanggal_kontrol",
+	},
+	SortDefault: "id",
+	IdColumn:    "kontrol_balik.id",
+	CariColumn:  []string{"pasien.no_rekam_medis", "pengguna.nama_lengkap"},
+	DateColumn:  "kontrol_balik.tanggal_kontrol",
+}
+
+func (r *KontrolBalikRepository) Search(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna")
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("kontrol_balik.status = ?", status)
 	}
-	return query.Preload("Pasien.AdminPuskesmas").Preload("Pasien.Pengguna").Find(&kontrolBalik).Error
+	return Paginate(query.Preload("Pasien.AdminPuskesmas").Preload("Pasien.Pengguna"), kontrolBalik, page, kontrolBalikPageOption)
 }
-func (r *KontrolBalikRepository) SearchAsAdminPuskesmas(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idAdminPuskesmas int32, status string) error {
+func (r *KontrolBalikRepository) SearchAsAdminPuskesmas(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idAdminPuskesmas int32, status string, page *PageQuery) (*PageResult, error) {
 	query := db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").
 		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas)
 	if status != "" {
 		query = query.Where("kontrol_balik.status = ?", status)
 	}
-	return query.Preload("Pasien.Pengguna").Find(&kontrolBalik).Error
+	return Paginate(query.Preload("Pasien.Pengguna"), kontrolBalik, page, kontrolBalikPageOption)
 }
-func (r *KontrolBalikRepository) SearchAsPengguna(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idPengguna int32, status string) error {
+func (r *KontrolBalikRepository) SearchAsPengguna(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idPengguna int32, status string, page *PageQuery) (*PageResult, error) {
 	query := db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").
 		Where("pasien.id_pengguna = ?", idPengguna)
 	if status != "" {
 		query = query.Where("kontrol_balik.status = ?", status)
 	}
-	return query.Preload("Pasien.AdminPuskesmas").Find(&kontrolBalik).Error
+	return Paginate(query.Preload("Pasien.AdminPuskesmas"), kontrolBalik, page, kontrolBalikPageOption)
+}
+func (r *KontrolBalikRepository) FindById(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32) error {
+	return db.Where("id = ?", id).First(&kontrolBalik).Error
+}
+func (r *KontrolBalikRepository) FindByIdAndIdAdminPuskesmas(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32, idAdminPuskesmas int32) error {
+	return db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
+		Where("kontrol_balik.id = ?", id).
+		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).
+		First(&kontrolBalik).Error
 }
 func (r *KontrolBalikRepository) FindByIdAndStatus(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32, status string) error {
 	return db.Where("id = ?", id).
 		Where("status = ?", status).
 		First(&kontrolBalik).Error
 }
+func (r *KontrolBalikRepository) FindByIdAndStatusAndLockForUpdate(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32, status string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("status = ?", status).
+		First(kontrolBalik).Error
+}
+func (r *KontrolBalikRepository) FindAllByTanggalKontrolBeforeAndStatus(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, tanggal int64, status string) error {
+	return db.Where("tanggal_kontrol < ?", tanggal).
+		Where("status = ?", status).
+		Order("id").
+		Find(kontrolBalik).Error
+}
+func (r *KontrolBalikRepository) FindAllByTanggalKontrolBetweenAndStatus(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, tanggalAwal int64, tanggalAkhir int64, status string) error {
+	return db.Preload("Pasien").
+		Where("tanggal_kontrol >= ? AND tanggal_kontrol < ?", tanggalAwal, tanggalAkhir).
+		Where("status = ?", status).
+		Find(kontrolBalik).Error
+}
 func (r *KontrolBalikRepository) FindByIdAndIdAdminPuskesmasAndStatus(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32, idAdminPuskesmas int32, status string) error {
 	return db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
 		Where("kontrol_balik.id = ?", id).
@@ -61,23 +104,47 @@ func (r *KontrolBalikRepository) FindByIdAndStatusOrStatus(db *gorm.DB, kontrolB
 		Where("status = ? OR status = ?", status1, status2).
 		First(&kontrolBalik).Error
 }
+func (r *KontrolBalikRepository) FindByIdAndStatusOrStatusAndLockForUpdate(db *gorm.DB, kontrolBalik *entity.KontrolBalik, id int32, status1 string, status2 string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("status = ? OR status = ?", status1, status2).
+		First(kontrolBalik).Error
+}
 func (r *KontrolBalikRepository) FindByIdPasienAndStatus(db *gorm.DB, kontrolBalik *entity.KontrolBalik, idPasien int32, status string) error {
 	return db.Where("id_pasien = ?", idPasien).
 		Where("status = ?", status).
 		First(&kontrolBalik).Error
 }
+func (r *KontrolBalikRepository) FindAllByIdPasienAndTanggalKontrolBetweenAndStatusOrStatus(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idPasien int32, tanggalAwal int64, tanggalAkhir int64, status1 string, status2 string) error {
+	return db.Where("id_pasien = ?", idPasien).
+		Where("tanggal_kontrol BETWEEN ? AND ?", tanggalAwal, tanggalAkhir).
+		Where("status = ? OR status = ?", status1, status2).
+		Order("tanggal_kontrol").
+		Order("id").
+		Find(kontrolBalik).Error
+}
+func (r *KontrolBalikRepository) FindAllById(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, id []int32) error {
+	return db.Where("id IN ?", id).Find(kontrolBalik).Error
+}
 func (r *KontrolBalikRepository) FindByIdPasien(db *gorm.DB, kontrolBalik *entity.KontrolBalik, idPasien int32) error {
 	return db.Where("id_pasien = ?", idPasien).
 		First(&kontrolBalik).Error
 }
+func (r *KontrolBalikRepository) FindAllByIdAdminPuskesmasAndTanggalKontrolBetweenAndStatusOrStatus(db *gorm.DB, kontrolBalik *[]entity.KontrolBalik, idAdminPuskesmas int32, tanggalAwal int64, tanggalAkhir int64, status1 string, status2 string) error {
+	return db.Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
+		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).
+		Where("kontrol_balik.tanggal_kontrol >= ? AND kontrol_balik.tanggal_kontrol < ?", tanggalAwal, tanggalAkhir).
+		Where("kontrol_balik.status = ? OR kontrol_balik.status = ?", status1, status2).
+		Find(kontrolBalik).Error
+}
 
-func (r *KontrolBalikRepository) FindMaksNoAntreanByTanggalKontrolAndIdAdminPuskesmasAndStatus(db *gorm.DB, tanggalKontrol int64, idAdminPuskesmas int32, status string) (int32, error) {
+func (r *KontrolBalikRepository) FindMaksNoAntreanByIdAdminPuskesmasAndTanggalAntreanAndStatus(db *gorm.DB, idAdminPuskesmas int32, tanggalAntrean int64, status string) (int32, error) {
 	var maxNoAntrean *int32
-	err := db.Model(&entity.KontrolBalik{}).Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
-		Where("kontrol_balik.tanggal_kontrol = ?", tanggalKontrol).
-		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).
-		Where("kontrol_balik.status = ?", status).
-		Select("MAX(kontrol_balik.no_antrean)").
+	err := db.Model(&entity.KontrolBalik{}).
+		Where("id_admin_puskesmas = ?", idAdminPuskesmas).
+		Where("tanggal_antrean = ?", tanggalAntrean).
+		Where("status = ?", status).
+		Select("MAX(no_antrean)").
 		Scan(&maxNoAntrean).Error
 	if err != nil {
 		return 0, err
@@ -88,15 +155,14 @@ func (r *KontrolBalikRepository) FindMaksNoAntreanByTanggalKontrolAndIdAdminPusk
 	return *maxNoAntrean, nil
 }
 
-func (r *KontrolBalikRepository) CountByNoAntreanAndTanggalKontrolAndIdAdminPuskesmasAndStatus(db *gorm.DB, noAntrean int32, tanggalKontrol int64, idAdminPuskesmas int32, status string) (int64, error) {
+func (r *KontrolBalikRepository) CountByNoAntreanAndIdAdminPuskesmasAndTanggalAntreanAndStatus(db *gorm.DB, noAntrean int32, idAdminPuskesmas int32, tanggalAntrean int64, status string) (int64, error) {
 	var count int64
 	if err :=
 		db.Model(&entity.KontrolBalik{}).
-			Joins("JOIN pasien ON pasien.id = kontrol_balik.id_pasien").
-			Where("kontrol_balik.no_antrean = ?", noAntrean).
-			Where("kontrol_balik.tanggal_kontrol = ?", tanggalKontrol).
-			Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).
-			Where("kontrol_balik.status = ?", status).
+			Where("no_antrean = ?", noAntrean).
+			Where("id_admin_puskesmas = ?", idAdminPuskesmas).
+			Where("tanggal_antrean = ?", tanggalAntrean).
+			Where("status = ?", status).
 			Count(&count).Error; err != nil {
 		return 0, err
 	}
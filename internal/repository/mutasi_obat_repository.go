@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type MutasiObatRepository struct {
+	Repository[entity.MutasiObat]
+}
+
+func NewMutasiObatRepository() *MutasiObatRepository {
+	return &MutasiObatRepository{}
+}
+
+func (r *MutasiObatRepository) FindAllByIdObat(db *gorm.DB, mutasiObat *[]entity.MutasiObat, idObat int32) error {
+	return db.Where("id_obat = ?", idObat).Order("id desc").Find(mutasiObat).Error
+}
+func (r *MutasiObatRepository) SumPerubahanByIdObat(db *gorm.DB, idObat int32) (int64, error) {
+	var total int64
+	if err := db.Model(&entity.MutasiObat{}).Where("id_obat = ?", idObat).Select("COALESCE(SUM(perubahan), 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
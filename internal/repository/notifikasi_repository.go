@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+)
+
+type NotifikasiRepository struct {
+	Repository[entity.Notifikasi]
+}
+
+func NewNotifikasiRepository() *NotifikasiRepository {
+	return &NotifikasiRepository{}
+}
+
+var notifikasiPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":     "id",
+		"dibuat": "dibuat",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	CariColumn:  []string{"judul", "isi"},
+	DateColumn:  "dibuat",
+}
+
+// baris yang sedang dikunci instance lain dilewati agar antrean bisa diproses paralel
+func (r *NotifikasiRepository) FindAllSiapKirimAndLockForUpdate(db *gorm.DB, notifikasi *[]entity.Notifikasi, sekarang int64, batas int) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND kirim_berikutnya <= ?", constant.StatusNotifikasiMenunggu, sekarang).
+		Order("kirim_berikutnya").Limit(batas).Find(notifikasi).Error
+}
+
+func (r *NotifikasiRepository) CreateOrIgnoreByKunci(db *gorm.DB, notifikasi *entity.Notifikasi) error {
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "kunci"}}, DoNothing: true}).Create(notifikasi).Error
+}
+
+func (r *NotifikasiRepository) FindByIdAndLockForUpdate(db *gorm.DB, notifikasi *entity.Notifikasi, id int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(notifikasi).Error
+}
+
+func (r *NotifikasiRepository) Search(db *gorm.DB, notifikasi *[]entity.Notifikasi, idPengguna int32, jenis string, status string, page *PageQuery) (*PageResult, error) {
+	query := db
+	if idPengguna != 0 {
+		query = query.Where("id_pengguna = ?", idPengguna)
+	}
+	if jenis != "" {
+		query = query.Where("jenis = ?", jenis)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, notifikasi, page, notifikasiPageOption)
+}
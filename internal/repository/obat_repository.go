@@ -14,24 +14,37 @@ func NewObatRepository() *ObatRepository {
 	return &ObatRepository{}
 }
 
-func (r *ObatRepository) FindAll(db *gorm.DB, obat *[]entity.Obat) error {
-	return db.Preload("AdminApotek").Find(obat).Error
+var obatPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":       "obat.id",
+		"namaObat": "obat.nama_obat",
+		"jumlah":   "obat.jumlah",
+	},
+	SortDefault: "id",
+	IdColumn:    "obat.id",
+	CariColumn:  []string{"obat.nama_obat"},
 }
-func (r *ObatRepository) FindAllByIdAdminApotek(db *gorm.DB, obat *[]entity.Obat, idAdminApotek int32) error {
-	return db.Where("id_admin_apotek = ?", idAdminApotek).Find(obat).Error
+
+func (r *ObatRepository) Search(db *gorm.DB, obat *[]entity.Obat, page *PageQuery) (*PageResult, error) {
+	return Paginate(db.Preload("AdminApotek").Where("obat.dihapus IS NULL"), obat, page, obatPageOption)
+}
+func (r *ObatRepository) SearchByIdAdminApotek(db *gorm.DB, obat *[]entity.Obat, idAdminApotek int32, page *PageQuery) (*PageResult, error) {
+	return Paginate(db.Where("id_admin_apotek = ?", idAdminApotek).Where("obat.dihapus IS NULL"), obat, page, obatPageOption)
 }
 func (r *ObatRepository) FindById(db *gorm.DB, obat *entity.Obat, id int32) error {
-	return db.Where("id = ?", id).First(obat).Error
+	return db.Where("id = ?", id).Where("dihapus IS NULL").First(obat).Error
 }
 func (r *ObatRepository) FindByIdAndIdAdminApotek(db *gorm.DB, obat *entity.Obat, id int32, idAdminApotek int32) error {
-	return db.Where("id = ?", id).Where("id_admin_apotek = ?", idAdminApotek).First(obat).Error
+	return db.Where("id = ?", id).Where("id_admin_apotek = ?", idAdminApotek).Where("dihapus IS NULL").First(obat).Error
 }
 func (r *ObatRepository) FindByIdAndLockForUpdate(db *gorm.DB, obat *entity.Obat, id int32) error {
-	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(obat).Error
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Where("dihapus IS NULL").First(obat).Error
 }
 func (r *ObatRepository) FindByIdAndIdAdminApotekAndLockForUpdate(db *gorm.DB, obat *entity.Obat, id int32, idAdminApotek int32) error {
-	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Where("id_admin_apotek = ?", idAdminApotek).First(obat).Error
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Where("id_admin_apotek = ?", idAdminApotek).Where("dihapus IS NULL").First(obat).Error
 }
+
+// obat yang dihapus ikut dihitung karena riwayat mutasinya masih milik apotek tersebut
 func (r *ObatRepository) FindByIdAdminApotek(db *gorm.DB, obat *entity.Obat, idAdminApotek int32) error {
 	return db.Where("id_admin_apotek = ?", idAdminApotek).First(obat).Error
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type OtpResetPasswordRepository struct {
+	Repository[entity.OtpResetPassword]
+}
+
+func NewOtpResetPasswordRepository() *OtpResetPasswordRepository {
+	return &OtpResetPasswordRepository{}
+}
+
+func (r *OtpResetPasswordRepository) CountByIdPenggunaSejak(db *gorm.DB, idPengguna int32, sejak int64) (int64, error) {
+	var count int64
+	if err := db.Model(&entity.OtpResetPassword{}).Where("id_pengguna = ? AND dibuat > ?", idPengguna, sejak).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *OtpResetPasswordRepository) FindTerakhirByIdPengguna(db *gorm.DB, otpResetPassword *entity.OtpResetPassword, idPengguna int32) error {
+	return db.Where("id_pengguna = ?", idPengguna).Order("id DESC").First(otpResetPassword).Error
+}
+
+func (r *OtpResetPasswordRepository) FindTerakhirByIdPenggunaAndLockForUpdate(db *gorm.DB, otpResetPassword *entity.OtpResetPassword, idPengguna int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id_pengguna = ?", idPengguna).Order("id DESC").First(otpResetPassword).Error
+}
+
+func (r *OtpResetPasswordRepository) FindByHashTokenResetAndLockForUpdate(db *gorm.DB, otpResetPassword *entity.OtpResetPassword, hashTokenReset string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash_token_reset = ?", hashTokenReset).First(otpResetPassword).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+)
+
+type OutboxRepository struct {
+	Repository[entity.Outbox]
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+var outboxPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":     "id",
+		"dibuat": "dibuat",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	DateColumn:  "dibuat",
+}
+
+// event yang lebih lama dikirim lebih dulu, baris yang sedang dikunci instance lain dilewati
+func (r *OutboxRepository) FindAllSiapKirimAndLockForUpdate(db *gorm.DB, outbox *[]entity.Outbox, sekarang int64, batas int) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND kirim_berikutnya <= ?", constant.StatusOutboxMenunggu, sekarang).
+		Order("id").Limit(batas).Find(outbox).Error
+}
+
+func (r *OutboxRepository) FindByIdAndLockForUpdate(db *gorm.DB, outbox *entity.Outbox, id int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(outbox).Error
+}
+
+func (r *OutboxRepository) Search(db *gorm.DB, outbox *[]entity.Outbox, jenis string, agregat string, idAgregat int32, status string, page *PageQuery) (*PageResult, error) {
+	query := db
+	if jenis != "" {
+		query = query.Where("jenis = ?", jenis)
+	}
+	if agregat != "" {
+		query = query.Where("agregat = ?", agregat)
+	}
+	if idAgregat != 0 {
+		query = query.Where("id_agregat = ?", idAgregat)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, outbox, page, outboxPageOption)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"reflect"
+	"strings"
+)
+
+const PageLimitDefault = 20
+
+var ErrPageTidakValid = errors.New("parameter halaman tidak valid")
+
+// menyebut parameter query yang tidak valid agar klien tahu apa yang harus diperbaiki
+type PageParameterError struct {
+	Parameter string
+}
+
+func (e *PageParameterError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPageTidakValid, e.Parameter)
+}
+
+func (e *PageParameterError) Unwrap() error {
+	return ErrPageTidakValid
+}
+
+type PageQuery struct {
+	Page   int
+	Limit  int
+	Cursor string
+	Sort   string
+	Order  string
+	Cari   string
+	Dari   int64
+	Sampai int64
+}
+
+// kolom yang boleh dipakai untuk urutan, pencarian dan filter tanggal pada satu daftar
+type PageOption struct {
+	Sort        map[string]string
+	SortDefault string
+	DescDefault bool
+	IdColumn    string
+	CariColumn  []string
+	DateColumn  string
+}
+
+type PageResult struct {
+	Total      int64
+	NextCursor string
+}
+
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Nilai any    `json:"v"`
+	ID    any    `json:"i"`
+}
+
+// kolom sort harus milik tabel utama karena nilainya dibaca dari entity untuk cursor berikutnya
+func Paginate[T any](db *gorm.DB, out *[]T, page *PageQuery, option *PageOption) (*PageResult, error) {
+	sort := option.SortDefault
+	if page.Sort != "" {
+		sort = page.Sort
+	}
+	column, ok := option.Sort[sort]
+	if !ok {
+		return nil, &PageParameterError{Parameter: "sort"}
+	}
+	desc := option.DescDefault
+	if page.Order != "" {
+		desc = page.Order == "desc"
+	}
+	limit := page.Limit
+	if limit <= 0 {
+		limit = PageLimitDefault
+	}
+
+	var cursor *pageCursor
+	if page.Cursor != "" {
+		var err error
+		cursor, err = decodePageCursor(page.Cursor)
+		if err != nil || cursor.Sort != sort || cursor.Desc != desc {
+			return nil, &PageParameterError{Parameter: "cursor"}
+		}
+	}
+
+	if page.Cari != "" && len(option.CariColumn) > 0 {
+		pola := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(page.Cari) + "%"
+		kondisi := make([]string, len(option.CariColumn))
+		args := make([]any, len(option.CariColumn))
+		for i, c := range option.CariColumn {
+			kondisi[i] = c + " ILIKE ?"
+			args[i] = pola
+		}
+		db = db.Where("("+strings.Join(kondisi, " OR ")+")", args...)
+	}
+	if option.DateColumn != "" && page.Dari > 0 {
+		db = db.Where(option.DateColumn+" >= ?", page.Dari)
+	}
+	if option.DateColumn != "" && page.Sampai > 0 {
+		db = db.Where(option.DateColumn+" <= ?", page.Sampai)
+	}
+	query := db.Session(&gorm.Session{})
+
+	result := new(PageResult)
+	if err := query.Model(new(T)).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	arah, operator := "ASC", ">"
+	if desc {
+		arah, operator = "DESC", "<"
+	}
+	find := query.Order(column + " " + arah).Order(option.IdColumn + " " + arah).Limit(limit + 1)
+	if cursor != nil {
+		find = find.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", column, operator, column, option.IdColumn, operator), cursor.Nilai, cursor.Nilai, cursor.ID)
+	} else if page.Page > 1 {
+		find = find.Offset((page.Page - 1) * limit)
+	}
+
+	found := find.Find(out)
+	if found.Error != nil {
+		return nil, found.Error
+	}
+	if len(*out) <= limit {
+		return result, nil
+	}
+
+	*out = (*out)[:limit]
+	nextCursor, err := encodePageCursor(found, (*out)[limit-1], column, option.IdColumn, sort, desc)
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = nextCursor
+	return result, nil
+}
+
+func encodePageCursor(db *gorm.DB, last any, column string, idColumn string, sort string, desc bool) (string, error) {
+	nilai, err := pageFieldValue(db, last, column)
+	if err != nil {
+		return "", err
+	}
+	id, err := pageFieldValue(db, last, idColumn)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(pageCursor{Sort: sort, Desc: desc, Nilai: nilai, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageCursor(s string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	cursor := new(pageCursor)
+	if err := decoder.Decode(cursor); err != nil {
+		return nil, err
+	}
+	// angka tetap bulat agar nilai bigint tidak kehilangan presisi
+	for _, v := range []*any{&cursor.Nilai, &cursor.ID} {
+		switch n := (*v).(type) {
+		case json.Number:
+			i, err := n.Int64()
+			if err != nil {
+				return nil, err
+			}
+			*v = i
+		case string, bool:
+		default:
+			return nil, ErrPageTidakValid
+		}
+	}
+	return cursor, nil
+}
+
+func pageFieldValue(db *gorm.DB, row any, column string) (any, error) {
+	name := column[strings.LastIndex(column, ".")+1:]
+	field := db.Statement.Schema.LookUpField(name)
+	if field == nil {
+		return nil, fmt.Errorf("kolom %s tidak ditemukan pada %s", name, db.Statement.Schema.Name)
+	}
+	nilai, _ := field.ValueOf(db.Statement.Context, reflect.ValueOf(row))
+	return nilai, nil
+}
@@ -13,26 +13,47 @@ func NewPasienRepository() *PasienRepository {
 	return &PasienRepository{}
 }
 
-func (r *PasienRepository) Search(db *gorm.DB, pasien *[]entity.Pasien, status string) error {
-	query := db.Preload("AdminPuskesmas").Preload("Pengguna")
+var pasienPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":            "pasien.id",
+		"noRekamMedis":  "pasien.no_rekam_medis",
+		"tanggalDaftar": "pasien.tanggal_daftar",
+	},
+	SortDefault: "id",
+	IdColumn:    "pasien.id",
+	CariColumn:  []string{"pasien.no_rekam_medis", "pengguna.nama_lengkap"},
+	DateColumn:  "pasien.tanggal_daftar",
+}
+
+func (r *PasienRepository) Search(db *gorm.DB, pasien *[]entity.Pasien, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").Preload("AdminPuskesmas").Preload("Pengguna")
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("pasien.status = ?", status)
 	}
-	return query.Find(pasien).Error
+	return Paginate(query, pasien, page, pasienPageOption)
 }
-func (r *PasienRepository) SearchAsAdminPuskesmas(db *gorm.DB, pasien *[]entity.Pasien, idAdminPuskesmas int32, status string) error {
-	query := db.Where("id_admin_puskesmas = ?", idAdminPuskesmas).Preload("Pengguna")
+func (r *PasienRepository) SearchAsAdminPuskesmas(db *gorm.DB, pasien *[]entity.Pasien, idAdminPuskesmas int32, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).Preload("Pengguna")
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("pasien.status = ?", status)
 	}
-	return query.Find(pasien).Error
+	return Paginate(query, pasien, page, pasienPageOption)
 }
-func (r *PasienRepository) SearchAsPengguna(db *gorm.DB, pasien *[]entity.Pasien, idPengguna int32, status string) error {
-	query := db.Where("id_pengguna = ?", idPengguna).Preload("AdminPuskesmas")
+func (r *PasienRepository) SearchAsPengguna(db *gorm.DB, pasien *[]entity.Pasien, idPengguna int32, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").Where("pasien.id_pengguna = ?", idPengguna).Preload("AdminPuskesmas")
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("pasien.status = ?", status)
 	}
-	return query.Find(pasien).Error
+	return Paginate(query, pasien, page, pasienPageOption)
+}
+func (r *PasienRepository) FindById(db *gorm.DB, pasien *entity.Pasien, id int32) error {
+	return db.Where("id = ?", id).First(pasien).Error
+}
+func (r *PasienRepository) FindByIdAndIdAdminPuskesmas(db *gorm.DB, pasien *entity.Pasien, id int32, idAdminPuskesmas int32) error {
+	return db.Where("id = ?", id).Where("id_admin_puskesmas = ?", idAdminPuskesmas).First(pasien).Error
+}
+func (r *PasienRepository) FindByIdAndIdPengguna(db *gorm.DB, pasien *entity.Pasien, id int32, idPengguna int32) error {
+	return db.Where("id = ?", id).Where("id_pengguna = ?", idPengguna).First(pasien).Error
 }
 func (r *PasienRepository) FindByIdAndStatus(db *gorm.DB, pasien *entity.Pasien, id int32, status string) error {
 	return db.Where("id = ?", id).Where("status = ?", status).First(pasien).Error
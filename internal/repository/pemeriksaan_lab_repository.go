@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type PemeriksaanLabRepository struct {
+	Repository[entity.PemeriksaanLab]
+}
+
+func NewPemeriksaanLabRepository() *PemeriksaanLabRepository {
+	return &PemeriksaanLabRepository{}
+}
+
+var pemeriksaanLabPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":   "id",
+		"kode": "kode",
+		"nama": "nama",
+	},
+	SortDefault: "id",
+	IdColumn:    "id",
+	CariColumn:  []string{"kode", "nama"},
+}
+
+func (r *PemeriksaanLabRepository) Search(db *gorm.DB, pemeriksaanLab *[]entity.PemeriksaanLab, page *PageQuery) (*PageResult, error) {
+	return Paginate(db, pemeriksaanLab, page, pemeriksaanLabPageOption)
+}
+func (r *PemeriksaanLabRepository) FindById(db *gorm.DB, pemeriksaanLab *entity.PemeriksaanLab, id int32) error {
+	return db.Where("id = ?", id).First(pemeriksaanLab).Error
+}
+func (r *PemeriksaanLabRepository) FindByKode(db *gorm.DB, pemeriksaanLab *entity.PemeriksaanLab, kode string) error {
+	return db.Where("kode = ?", kode).First(pemeriksaanLab).Error
+}
+func (r *PemeriksaanLabRepository) FindAllById(db *gorm.DB, pemeriksaanLab *[]entity.PemeriksaanLab, id []int32) error {
+	return db.Where("id IN ?", id).Find(pemeriksaanLab).Error
+}
+func (r *PemeriksaanLabRepository) CountByKode(db *gorm.DB, kode string) (int64, error) {
+	var count int64
+	if err := db.Model(&entity.PemeriksaanLab{}).Where("kode = ?", kode).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
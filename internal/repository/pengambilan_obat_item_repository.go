@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type PengambilanObatItemRepository struct {
+	Repository[entity.PengambilanObatItem]
+}
+
+func NewPengambilanObatItemRepository() *PengambilanObatItemRepository {
+	return &PengambilanObatItemRepository{}
+}
+
+func (r *PengambilanObatItemRepository) FindAllByIdPengambilanObat(db *gorm.DB, item *[]entity.PengambilanObatItem, idPengambilanObat int32) error {
+	return db.Where("id_pengambilan_obat = ?", idPengambilanObat).Find(item).Error
+}
+func (r *PengambilanObatItemRepository) DeleteByIdPengambilanObat(db *gorm.DB, idPengambilanObat int32) error {
+	return db.Where("id_pengambilan_obat = ?", idPengambilanObat).Delete(&entity.PengambilanObatItem{}).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"prb_care_api/internal/entity"
 )
 
@@ -13,52 +14,90 @@ func NewPengambilanObatRepository() *PengambilanObatRepository {
 	return &PengambilanObatRepository{}
 }
 
-func (r *PengambilanObatRepository) Search(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, status string) error {
-	query := db
+var pengambilanObatPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":                 "pengambilan_obat.id",
+		"resi":               "pengambilan_obat.resi",
+		"tanggalPengambilan": "pengambilan_obat.tanggal_pengambilan",
+	},
+	SortDefault: "id",
+	IdColumn:    "pengambilan_obat.id",
+	CariColumn:  []string{"pengambilan_obat.resi", "pasien.no_rekam_medis", "pengguna.nama_lengkap"},
+	DateColumn:  "pengambilan_obat.tanggal_pengambilan",
+}
+
+func (r *PengambilanObatRepository) Search(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna")
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("pengambilan_obat.status = ?", status)
 	}
-	return query.Preload("Pasien.AdminPuskesmas").
+	query = query.Preload("Pasien.AdminPuskesmas").
 		Preload("Pasien.Pengguna").
-		Preload("Obat.AdminApotek").
-		Find(&pengambilanObat).Error
+		Preload("AdminApotek").
+		Preload("Item.Obat")
+	return Paginate(query, pengambilanObat, page, pengambilanObatPageOption)
 }
-func (r *PengambilanObatRepository) SearchAsAdminPuskesmas(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idAdminPuskesmas int32, status string) error {
+func (r *PengambilanObatRepository) SearchAsAdminPuskesmas(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idAdminPuskesmas int32, status string, page *PageQuery) (*PageResult, error) {
 	query := db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").
 		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas)
 	if status != "" {
 		query = query.Where("pengambilan_obat.status = ?", status)
 	}
-	return query.
+	query = query.
 		Preload("Pasien.Pengguna").
-		Preload("Obat.AdminApotek").
-		Find(&pengambilanObat).Error
+		Preload("AdminApotek").
+		Preload("Item.Obat")
+	return Paginate(query, pengambilanObat, page, pengambilanObatPageOption)
 }
-func (r *PengambilanObatRepository) SearchAsAdminApotek(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idAdminApotek int32, status string) error {
-	query := db.Joins("JOIN obat ON obat.id = pengambilan_obat.id_obat").
-		Where("obat.id_admin_apotek = ?", idAdminApotek)
+func (r *PengambilanObatRepository) SearchAsAdminApotek(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idAdminApotek int32, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").
+		Where("pengambilan_obat.id_admin_apotek = ?", idAdminApotek)
 	if status != "" {
 		query = query.Where("pengambilan_obat.status = ?", status)
 	}
-	return query.Preload("Pasien.AdminPuskesmas").
+	query = query.Preload("Pasien.AdminPuskesmas").
 		Preload("Pasien.Pengguna").
-		Find(&pengambilanObat).Error
+		Preload("Item.Obat")
+	return Paginate(query, pengambilanObat, page, pengambilanObatPageOption)
 }
-func (r *PengambilanObatRepository) SearchAsPengguna(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idPengguna int32, status string) error {
+func (r *PengambilanObatRepository) SearchAsPengguna(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, idPengguna int32, status string, page *PageQuery) (*PageResult, error) {
 	query := db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
+		Joins("JOIN pengguna ON pengguna.id = pasien.id_pengguna").
 		Where("pasien.id_pengguna = ?", idPengguna)
 	if status != "" {
 		query = query.Where("pengambilan_obat.status = ?", status)
 	}
-	return query.Preload("Pasien.AdminPuskesmas").
-		Preload("Obat.AdminApotek").
-		Find(&pengambilanObat).Error
+	query = query.Preload("Pasien.AdminPuskesmas").
+		Preload("AdminApotek").
+		Preload("Item.Obat")
+	return Paginate(query, pengambilanObat, page, pengambilanObatPageOption)
 }
 func (r *PengambilanObatRepository) FindByIdAndStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, status string) error {
 	return db.Where("id = ?", id).
 		Where("status = ?", status).
 		First(&pengambilanObat).Error
 }
+func (r *PengambilanObatRepository) FindByIdAndStatusAndLockForUpdate(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, status string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("status = ?", status).
+		First(pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindAllByTanggalPengambilanBeforeAndStatus(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, tanggal int64, status string) error {
+	return db.Where("tanggal_pengambilan < ?", tanggal).
+		Where("status = ?", status).
+		Order("id").
+		Find(pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindAllByTanggalPengambilanBetweenAndStatus(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, tanggalAwal int64, tanggalAkhir int64, status string) error {
+	return db.Preload("Pasien").
+		Where("tanggal_pengambilan >= ? AND tanggal_pengambilan < ?", tanggalAwal, tanggalAkhir).
+		Where("status = ?", status).
+		Find(pengambilanObat).Error
+}
 func (r *PengambilanObatRepository) FindByIdAndIdAdminPuskesmasAndStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idAdminPuskesmas int32, status string) error {
 	return db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
 		Where("pengambilan_obat.id = ?", id).
@@ -66,13 +105,27 @@ func (r *PengambilanObatRepository) FindByIdAndIdAdminPuskesmasAndStatus(db *gor
 		Where("pengambilan_obat.status = ?", status).
 		First(&pengambilanObat).Error
 }
-func (r *PengambilanObatRepository) FindByIdAndIdAdminApotekAndStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idAdminApotek int32, status string) error {
-	return db.Joins("JOIN obat ON obat.id = pengambilan_obat.id_obat").
+func (r *PengambilanObatRepository) FindByIdAndIdAdminPuskesmasAndStatusAndLockForUpdate(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idAdminPuskesmas int32, status string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "pengambilan_obat"}}).
+		Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
 		Where("pengambilan_obat.id = ?", id).
-		Where("obat.id_admin_apotek = ?", idAdminApotek).
+		Where("pasien.id_admin_puskesmas = ?", idAdminPuskesmas).
 		Where("pengambilan_obat.status = ?", status).
+		First(pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindByIdAndIdAdminApotekAndStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idAdminApotek int32, status string) error {
+	return db.Where("id = ?", id).
+		Where("id_admin_apotek = ?", idAdminApotek).
+		Where("status = ?", status).
 		First(&pengambilanObat).Error
 }
+func (r *PengambilanObatRepository) FindByIdAndIdAdminApotekAndStatusAndLockForUpdate(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idAdminApotek int32, status string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("id_admin_apotek = ?", idAdminApotek).
+		Where("status = ?", status).
+		First(pengambilanObat).Error
+}
 func (r *PengambilanObatRepository) FindByIdAndStatusOrStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, status1 string, status2 string) error {
 	return db.Where("id = ?", id).
 		Where("status = ? OR status = ?", status1, status2).
@@ -92,8 +145,47 @@ func (r *PengambilanObatRepository) FindByIdPasienAndStatus(db *gorm.DB, pengamb
 		First(&pengambilanObat).Error
 }
 func (r *PengambilanObatRepository) FindByIdObat(db *gorm.DB, pengambilanObat *entity.PengambilanObat, idObat int32) error {
-	return db.Where("id_obat = ?", idObat).First(&pengambilanObat).Error
+	return db.Joins("JOIN pengambilan_obat_item ON pengambilan_obat_item.id_pengambilan_obat = pengambilan_obat.id").
+		Where("pengambilan_obat_item.id_obat = ?", idObat).
+		First(&pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindAllById(db *gorm.DB, pengambilanObat *[]entity.PengambilanObat, id []int32) error {
+	return db.Where("id IN ?", id).
+		Preload("AdminApotek").
+		Preload("Item.Obat").
+		Find(pengambilanObat).Error
 }
 func (r *PengambilanObatRepository) FindByIdPasien(db *gorm.DB, pengambilanObat *entity.PengambilanObat, idPasien int32) error {
 	return db.Where("id_pasien = ?", idPasien).First(&pengambilanObat).Error
 }
+func (r *PengambilanObatRepository) FindByIdAndIdPenggunaAndStatus(db *gorm.DB, pengambilanObat *entity.PengambilanObat, id int32, idPengguna int32, status string) error {
+	return db.Joins("JOIN pasien ON pasien.id = pengambilan_obat.id_pasien").
+		Where("pengambilan_obat.id = ?", id).
+		Where("pasien.id_pengguna = ?", idPengguna).
+		Where("pengambilan_obat.status = ?", status).
+		First(&pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindByResi(db *gorm.DB, pengambilanObat *entity.PengambilanObat, resi string) error {
+	return db.Where("resi = ?", resi).
+		Preload("Pasien.Pengguna").
+		Preload("Pasien.AdminPuskesmas").
+		Preload("AdminApotek").
+		Preload("Item.Obat").
+		First(&pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) FindByResiAndIdAdminApotek(db *gorm.DB, pengambilanObat *entity.PengambilanObat, resi string, idAdminApotek int32) error {
+	return db.Where("resi = ?", resi).
+		Where("id_admin_apotek = ?", idAdminApotek).
+		Preload("Pasien.Pengguna").
+		Preload("Pasien.AdminPuskesmas").
+		Preload("AdminApotek").
+		Preload("Item.Obat").
+		First(&pengambilanObat).Error
+}
+func (r *PengambilanObatRepository) CountByResi(db *gorm.DB, resi any) (int64, error) {
+	var count int64
+	if err := db.Model(&entity.PengambilanObat{}).Where("resi = ?", resi).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"prb_care_api/internal/entity"
 )
 
@@ -16,6 +17,12 @@ func NewPenggunaRepository() *PenggunaRepository {
 func (r *PenggunaRepository) FindByUsername(db *gorm.DB, pengguna *entity.Pengguna, username string) error {
 	return db.Where("username = ?", username).First(pengguna).Error
 }
+func (r *PenggunaRepository) FindByTelepon(db *gorm.DB, pengguna *entity.Pengguna, telepon string) error {
+	return db.Where("telepon = ?", telepon).First(pengguna).Error
+}
+func (r *PenggunaRepository) FindByTeleponAndLockForUpdate(db *gorm.DB, pengguna *entity.Pengguna, telepon string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("telepon = ?", telepon).First(pengguna).Error
+}
 func (r *PenggunaRepository) FindById(db *gorm.DB, pengguna *entity.Pengguna, id int32) error {
 	return db.Where("id = ?", id).First(pengguna).Error
 }
@@ -33,6 +40,27 @@ func (r *PenggunaRepository) CountByTelepon(db *gorm.DB, telepon any) (int64, er
 	}
 	return count, nil
 }
-func (r *PenggunaRepository) FindAll(db *gorm.DB, pengguna *[]entity.Pengguna) error {
-	return db.Find(pengguna).Error
+
+var penggunaPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":          "pengguna.id",
+		"namaLengkap": "pengguna.nama_lengkap",
+		"username":    "pengguna.username",
+	},
+	SortDefault: "id",
+	IdColumn:    "pengguna.id",
+	CariColumn:  []string{"pengguna.nama_lengkap", "pengguna.username", "pengguna.telepon"},
+}
+
+func (r *PenggunaRepository) Search(db *gorm.DB, pengguna *[]entity.Pengguna, page *PageQuery) (*PageResult, error) {
+	return Paginate(db, pengguna, page, penggunaPageOption)
+}
+
+func (r *PenggunaRepository) IncrementVersiTokenById(db *gorm.DB, id int32) error {
+	return db.Model(new(entity.Pengguna)).Where("id = ?", id).Update("versi_token", gorm.Expr("versi_token + 1")).Error
+}
+
+// hanya dikosongkan bila pengguna belum mendaftarkan token baru
+func (r *PenggunaRepository) ClearTokenPerangkatByIdAndTokenPerangkat(db *gorm.DB, id int32, tokenPerangkat string) error {
+	return db.Model(new(entity.Pengguna)).Where("id = ? AND token_perangkat = ?", id, tokenPerangkat).Update("token_perangkat", "").Error
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+)
+
+type PengirimanWebhookRepository struct {
+	Repository[entity.PengirimanWebhook]
+}
+
+func NewPengirimanWebhookRepository() *PengirimanWebhookRepository {
+	return &PengirimanWebhookRepository{}
+}
+
+var pengirimanWebhookPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":     "id",
+		"dibuat": "dibuat",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	DateColumn:  "dibuat",
+}
+
+// event yang sama tidak diantrekan dua kali untuk satu webhook walaupun outbox mengirimnya lebih dari sekali
+func (r *PengirimanWebhookRepository) CreateOrIgnoreByIdWebhookAndIdEvent(db *gorm.DB, pengiriman *entity.PengirimanWebhook) error {
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id_webhook"}, {Name: "id_event"}}, DoNothing: true}).Create(pengiriman).Error
+}
+
+// baris yang sedang dikunci instance lain dilewati agar antrean bisa diproses paralel
+func (r *PengirimanWebhookRepository) FindAllSiapKirimAndLockForUpdate(db *gorm.DB, pengiriman *[]entity.PengirimanWebhook, sekarang int64, batas int) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND kirim_berikutnya <= ?", constant.StatusPengirimanWebhookMenunggu, sekarang).
+		Order("id").Limit(batas).Find(pengiriman).Error
+}
+
+func (r *PengirimanWebhookRepository) FindByIdAndLockForUpdate(db *gorm.DB, pengiriman *entity.PengirimanWebhook, id int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(pengiriman).Error
+}
+
+func (r *PengirimanWebhookRepository) Search(db *gorm.DB, pengiriman *[]entity.PengirimanWebhook, idWebhook int32, jenis string, status string, page *PageQuery) (*PageResult, error) {
+	query := db
+	if idWebhook != 0 {
+		query = query.Where("id_webhook = ?", idWebhook)
+	}
+	if jenis != "" {
+		query = query.Where("jenis = ?", jenis)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, pengiriman, page, pengirimanWebhookPageOption)
+}
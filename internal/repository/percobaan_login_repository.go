@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+)
+
+type PercobaanLoginRepository struct {
+	Repository[entity.PercobaanLogin]
+}
+
+func NewPercobaanLoginRepository() *PercobaanLoginRepository {
+	return &PercobaanLoginRepository{}
+}
+
+var percobaanLoginPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":    "id",
+		"waktu": "waktu",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	CariColumn:  []string{"username", "ip"},
+	DateColumn:  "waktu",
+}
+
+type GagalLogin struct {
+	Jumlah   int64
+	Terakhir int64
+}
+
+// gagal yang dihitung hanya yang terjadi setelah login berhasil atau kunci dibuka terakhir kali
+func (r *PercobaanLoginRepository) CountGagalByRoleAndUsername(db *gorm.DB, role string, username string, sejak int64) (*GagalLogin, error) {
+	reset := db.Model(new(entity.PercobaanLogin)).Select("COALESCE(MAX(id), 0)").
+		Where("role = ? AND username = ? AND status IN ?", role, username, []string{constant.StatusPercobaanLoginBerhasil, constant.StatusPercobaanLoginDibuka})
+	gagal := new(GagalLogin)
+	err := db.Model(new(entity.PercobaanLogin)).Select("COUNT(*) AS jumlah, COALESCE(MAX(waktu), 0) AS terakhir").
+		Where("role = ? AND username = ? AND status = ? AND waktu >= ?", role, username, constant.StatusPercobaanLoginGagal, sejak).
+		Where("id > (?)", reset).
+		Scan(gagal).Error
+	return gagal, err
+}
+
+func (r *PercobaanLoginRepository) CountGagalByIp(db *gorm.DB, ip string, sejak int64) (*GagalLogin, error) {
+	reset := db.Model(new(entity.PercobaanLogin)).Select("COALESCE(MAX(id), 0)").
+		Where("ip = ? AND username IS NULL AND status = ?", ip, constant.StatusPercobaanLoginDibuka)
+	gagal := new(GagalLogin)
+	err := db.Model(new(entity.PercobaanLogin)).Select("COUNT(*) AS jumlah, COALESCE(MAX(waktu), 0) AS terakhir").
+		Where("ip = ? AND status = ? AND waktu >= ?", ip, constant.StatusPercobaanLoginGagal, sejak).
+		Where("id > (?)", reset).
+		Scan(gagal).Error
+	return gagal, err
+}
+
+func (r *PercobaanLoginRepository) Search(db *gorm.DB, percobaanLogin *[]entity.PercobaanLogin, role string, status string, page *PageQuery) (*PageResult, error) {
+	query := db
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, percobaanLogin, page, percobaanLoginPageOption)
+}
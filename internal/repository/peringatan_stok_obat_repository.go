@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type PeringatanStokObatRepository struct {
+	Repository[entity.PeringatanStokObat]
+}
+
+func NewPeringatanStokObatRepository() *PeringatanStokObatRepository {
+	return &PeringatanStokObatRepository{}
+}
+
+func (r *PeringatanStokObatRepository) FindAll(db *gorm.DB, peringatanStokObat *[]entity.PeringatanStokObat) error {
+	return db.Preload("Obat.AdminApotek").Order("id desc").Find(peringatanStokObat).Error
+}
+func (r *PeringatanStokObatRepository) FindAllByIdAdminApotek(db *gorm.DB, peringatanStokObat *[]entity.PeringatanStokObat, idAdminApotek int32) error {
+	return db.Joins("JOIN obat ON obat.id = peringatan_stok_obat.id_obat").
+		Where("obat.id_admin_apotek = ?", idAdminApotek).
+		Preload("Obat").
+		Order("peringatan_stok_obat.id desc").
+		Find(peringatanStokObat).Error
+}
+func (r *PeringatanStokObatRepository) FindAllByIdAdminPuskesmas(db *gorm.DB, peringatanStokObat *[]entity.PeringatanStokObat, idAdminPuskesmas int32) error {
+	return db.Joins("JOIN peringatan_stok_obat_puskesmas ON peringatan_stok_obat_puskesmas.id_peringatan_stok_obat = peringatan_stok_obat.id").
+		Where("peringatan_stok_obat_puskesmas.id_admin_puskesmas = ?", idAdminPuskesmas).
+		Preload("Obat.AdminApotek").
+		Order("peringatan_stok_obat.id desc").
+		Find(peringatanStokObat).Error
+}
+func (r *PeringatanStokObatRepository) CreatePenerimaByIdObatAndStatusPengambilanObat(db *gorm.DB, idPeringatanStokObat int32, idObat int32, status string) error {
+	return db.Exec("INSERT INTO peringatan_stok_obat_puskesmas (id_peringatan_stok_obat, id_admin_puskesmas) "+
+		"SELECT DISTINCT ?::integer, pasien.id_admin_puskesmas FROM pengambilan_obat "+
+		"JOIN pengambilan_obat_item ON pengambilan_obat_item.id_pengambilan_obat = pengambilan_obat.id "+
+		"JOIN pasien ON pasien.id = pengambilan_obat.id_pasien "+
+		"WHERE pengambilan_obat_item.id_obat = ? AND pengambilan_obat.status = ?",
+		idPeringatanStokObat, idObat, status).Error
+}
+func (r *PeringatanStokObatRepository) DeleteByIdObat(db *gorm.DB, idObat int32) error {
+	if err := db.Where("id_peringatan_stok_obat IN (?)", db.Model(&entity.PeringatanStokObat{}).Select("id").Where("id_obat = ?", idObat)).
+		Delete(&entity.PeringatanStokObatPuskesmas{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id_obat = ?", idObat).Delete(&entity.PeringatanStokObat{}).Error
+}
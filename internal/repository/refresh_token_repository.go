@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type RefreshTokenRepository struct {
+	Repository[entity.RefreshToken]
+}
+
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{}
+}
+
+func (r *RefreshTokenRepository) FindByHashAndLockForUpdate(db *gorm.DB, refreshToken *entity.RefreshToken, hash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(refreshToken).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type RiwayatJobRepository struct {
+	Repository[entity.RiwayatJob]
+}
+
+func NewRiwayatJobRepository() *RiwayatJobRepository {
+	return &RiwayatJobRepository{}
+}
+
+var riwayatJobPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":    "id",
+		"mulai": "mulai",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	DateColumn:  "mulai",
+}
+
+func (r *RiwayatJobRepository) Search(db *gorm.DB, riwayatJob *[]entity.RiwayatJob, nama string, status string, page *PageQuery) (*PageResult, error) {
+	query := db
+	if nama != "" {
+		query = query.Where("nama = ?", nama)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, riwayatJob, page, riwayatJobPageOption)
+}
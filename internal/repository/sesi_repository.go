@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type SesiRepository struct {
+	Repository[entity.Sesi]
+}
+
+func NewSesiRepository() *SesiRepository {
+	return &SesiRepository{}
+}
+
+func (r *SesiRepository) FindAktifByIdAndIdAkunAndRole(db *gorm.DB, sesi *entity.Sesi, id string, idAkun int32, role string, sekarang int64) error {
+	return db.Where("id = ?", id).
+		Where("id_akun = ?", idAkun).
+		Where("role = ?", role).
+		Where("dicabut IS NULL").
+		Where("kedaluwarsa > ?", sekarang).
+		First(sesi).Error
+}
+func (r *SesiRepository) FindByIdAndLockForUpdate(db *gorm.DB, sesi *entity.Sesi, id string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(sesi).Error
+}
+func (r *SesiRepository) RevokeByIdAkunAndRole(db *gorm.DB, idAkun int32, role string, waktu int64) error {
+	return db.Model(&entity.Sesi{}).
+		Where("id_akun = ?", idAkun).
+		Where("role = ?", role).
+		Where("dicabut IS NULL").
+		Update("dicabut", waktu).Error
+}
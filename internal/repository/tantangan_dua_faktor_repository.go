@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type TantanganDuaFaktorRepository struct {
+	Repository[entity.TantanganDuaFaktor]
+}
+
+func NewTantanganDuaFaktorRepository() *TantanganDuaFaktorRepository {
+	return &TantanganDuaFaktorRepository{}
+}
+
+func (r *TantanganDuaFaktorRepository) FindByHashAndLockForUpdate(db *gorm.DB, tantanganDuaFaktor *entity.TantanganDuaFaktor, hash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(tantanganDuaFaktor).Error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type TimelinePasienRepository struct{}
+
+func NewTimelinePasienRepository() *TimelinePasienRepository {
+	return &TimelinePasienRepository{}
+}
+
+// kunci unik lintas jenis sehingga cursor tetap stabil saat tanggal sama
+var timelinePasienPageOption = &PageOption{
+	Sort: map[string]string{
+		"tanggal": "tanggal",
+	},
+	SortDefault: "tanggal",
+	IdColumn:    "kunci",
+	DateColumn:  "tanggal",
+}
+
+func (r *TimelinePasienRepository) SearchByIdPasien(db *gorm.DB, timeline *[]entity.TimelinePasien, idPasien int32, jenis string, status string, page *PageQuery) (*PageResult, error) {
+	query := db.Where("id_pasien = ?", idPasien)
+	if jenis != "" {
+		query = query.Where("jenis = ?", jenis)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return Paginate(query, timeline, page, timelinePasienPageOption)
+}
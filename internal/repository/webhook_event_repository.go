@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"prb_care_api/internal/entity"
+)
+
+type WebhookEventRepository struct {
+	Repository[entity.WebhookEvent]
+}
+
+func NewWebhookEventRepository() *WebhookEventRepository {
+	return &WebhookEventRepository{}
+}
+
+func (r *WebhookEventRepository) DeleteByIdWebhook(db *gorm.DB, idWebhook int32) error {
+	return db.Where("id_webhook = ?", idWebhook).Delete(&entity.WebhookEvent{}).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"prb_care_api/internal/entity"
+)
+
+type WebhookRepository struct {
+	Repository[entity.Webhook]
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{}
+}
+
+var webhookPageOption = &PageOption{
+	Sort: map[string]string{
+		"id":     "id",
+		"nama":   "nama",
+		"dibuat": "dibuat",
+	},
+	SortDefault: "id",
+	DescDefault: true,
+	IdColumn:    "id",
+	CariColumn:  []string{"nama", "url"},
+	DateColumn:  "dibuat",
+}
+
+func (r *WebhookRepository) FindById(db *gorm.DB, webhook *entity.Webhook, id int32) error {
+	return db.Preload("Event").Where("id = ?", id).First(webhook).Error
+}
+
+func (r *WebhookRepository) FindByIdAndLockForUpdate(db *gorm.DB, webhook *entity.Webhook, id int32) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(webhook).Error
+}
+
+func (r *WebhookRepository) FindAllByAktifAndEvent(db *gorm.DB, webhook *[]entity.Webhook, aktif bool, jenis string) error {
+	return db.Where("aktif = ? AND id IN (?)", aktif, db.Model(&entity.WebhookEvent{}).Select("id_webhook").Where("jenis = ?", jenis)).
+		Find(webhook).Error
+}
+
+func (r *WebhookRepository) Search(db *gorm.DB, webhook *[]entity.Webhook, page *PageQuery) (*PageResult, error) {
+	return Paginate(db.Preload("Event"), webhook, page, webhookPageOption)
+}
@@ -6,22 +6,37 @@ import (
 	"github.com/gofiber/fiber/v3/middleware/static"
 	"github.com/spf13/viper"
 	"os"
+	"prb_care_api/internal/constant"
 	"prb_care_api/internal/controller"
+	"prb_care_api/internal/middleware"
 )
 
 type Config struct {
-	App                       *fiber.App
-	AuthMiddleware            fiber.Handler
-	AdminSuperController      *controller.AdminSuperController
-	AdminPuskesmasController  *controller.AdminPuskesmasController
-	AdminApotekController     *controller.AdminApotekController
-	PenggunaController        *controller.PenggunaController
-	ObatController            *controller.ObatController
-	PasienController          *controller.PasienController
-	KontrolBalikController    *controller.KontrolBalikController
-	PengambilanObatController *controller.PengambilanObatController
-	ArtikelController         *controller.ArtikelController
-	Config                    *viper.Viper
+	App                           *fiber.App
+	AuthMiddleware                fiber.Handler
+	AuthController                *controller.AuthController
+	AdminSuperController          *controller.AdminSuperController
+	PercobaanLoginController      *controller.PercobaanLoginController
+	ResetPasswordController       *controller.ResetPasswordController
+	DuaFaktorController           *controller.DuaFaktorController
+	AuditController               *controller.AuditController
+	NotifikasiController          *controller.NotifikasiController
+	EventController               *controller.EventController
+	WebhookController             *controller.WebhookController
+	RiwayatJobController          *controller.RiwayatJobController
+	AdminPuskesmasController      *controller.AdminPuskesmasController
+	AdminApotekController         *controller.AdminApotekController
+	PenggunaController            *controller.PenggunaController
+	ObatController                *controller.ObatController
+	PasienController              *controller.PasienController
+	TandaVitalController          *controller.TandaVitalController
+	HasilPemeriksaanLabController *controller.HasilPemeriksaanLabController
+	PemeriksaanLabController      *controller.PemeriksaanLabController
+	KontrolBalikController        *controller.KontrolBalikController
+	PengambilanObatController     *controller.PengambilanObatController
+	ArtikelController             *controller.ArtikelController
+	JadwalOperasionalController   *controller.JadwalOperasionalController
+	Config                        *viper.Viper
 }
 
 func (c *Config) SetupGuestRoute() {
@@ -36,6 +51,14 @@ func (c *Config) SetupGuestRoute() {
 	c.App.Post("/api/admin-apotek/login", c.AdminApotekController.Login)
 	c.App.Post("/api/pengguna/login", c.PenggunaController.Login)
 	c.App.Post("/api/pengguna/register", c.PenggunaController.Register)
+	c.App.Post("/api/pengguna/lupa-password", c.ResetPasswordController.LupaPassword)
+	c.App.Post("/api/pengguna/lupa-password/verifikasi", c.ResetPasswordController.VerifikasiOtp)
+	c.App.Post("/api/pengguna/lupa-password/reset", c.ResetPasswordController.ResetPassword)
+	c.App.Post("/api/auth/refresh", c.AuthController.Refresh)
+	c.App.Post("/api/auth/dua-faktor", c.DuaFaktorController.Login)
+	c.App.Post("/api/auth/dua-faktor/enrol", c.DuaFaktorController.LoginEnrol)
+	c.App.Get("/api/artikel", c.ArtikelController.Search)
+	c.App.Get("/api/artikel/:id", c.ArtikelController.Get)
 
 	c.App.Use("/static*", func(ctx fiber.Ctx) error {
 		ctx.Set("Cache-Control", "public, max-age=31536000")
@@ -49,71 +72,127 @@ func (c *Config) SetupGuestRoute() {
 
 func (c *Config) SetupAuthRoute() {
 	c.App.Use(c.AuthMiddleware)
-
-	c.App.Patch("/api/admin-super/current/password", c.AdminSuperController.PasswordUpdate)
-
-	c.App.Get("/api/admin-puskesmas", c.AdminPuskesmasController.List)
-	c.App.Get("/api/admin-puskesmas/current", c.AdminPuskesmasController.Current)
-	c.App.Patch("/api/admin-puskesmas/current", c.AdminPuskesmasController.CurrentProfileUpdate)
-	c.App.Patch("/api/admin-puskesmas/current/password", c.AdminPuskesmasController.CurrentPasswordUpdate)
-	c.App.Get("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Get)
-	c.App.Post("/api/admin-puskesmas", c.AdminPuskesmasController.Create)
-	c.App.Patch("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Update)
-	c.App.Delete("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Delete)
-
-	c.App.Get("/api/admin-apotek", c.AdminApotekController.List)
-	c.App.Get("/api/admin-apotek/current", c.AdminApotekController.Current)
-	c.App.Patch("/api/admin-apotek/current", c.AdminApotekController.CurrentProfileUpdate)
-	c.App.Patch("/api/admin-apotek/current/password", c.AdminApotekController.CurrentPasswordUpdate)
-	c.App.Get("/api/admin-apotek/:id", c.AdminApotekController.Get)
-	c.App.Post("/api/admin-apotek", c.AdminApotekController.Create)
-	c.App.Patch("/api/admin-apotek/:id", c.AdminApotekController.Update)
-	c.App.Delete("/api/admin-apotek/:id", c.AdminApotekController.Delete)
-
-	c.App.Get("/api/pengguna/current", c.PenggunaController.Current)
-	c.App.Patch("/api/pengguna/current", c.PenggunaController.CurrentProfileUpdate)
-	c.App.Patch("/api/pengguna/current/password", c.PenggunaController.CurrentPasswordUpdate)
-	c.App.Patch("/api/pengguna/current/perangkat", c.PenggunaController.CurrentTokenPerangkatUpdate)
-	c.App.Get("/api/pengguna", c.PenggunaController.List)
-	c.App.Get("/api/pengguna/:id", c.PenggunaController.Get)
-	c.App.Post("/api/pengguna", c.PenggunaController.Create)
-	c.App.Patch("/api/pengguna/:id", c.PenggunaController.Update)
-	c.App.Delete("/api/pengguna/:id", c.PenggunaController.Delete)
-
-	c.App.Get("/api/obat", c.ObatController.List)
-	c.App.Get("/api/obat/:id", c.ObatController.Get)
-	c.App.Post("/api/obat", c.ObatController.Create)
-	c.App.Patch("/api/obat/:id", c.ObatController.Update)
-	c.App.Delete("/api/obat/:id", c.ObatController.Delete)
-
-	c.App.Get("/api/pasien", c.PasienController.Search)
-	c.App.Get("/api/pasien/:id", c.PasienController.Get)
-	c.App.Post("/api/pasien", c.PasienController.Create)
-	c.App.Patch("/api/pasien/:id", c.PasienController.Update)
-	c.App.Delete("/api/pasien/:id", c.PasienController.Delete)
-	c.App.Patch("/api/pasien/:id/selesai", c.PasienController.Selesai)
-
-	c.App.Get("/api/kontrol-balik", c.KontrolBalikController.Search)
-	c.App.Get("/api/kontrol-balik/:id", c.KontrolBalikController.Get)
-	c.App.Post("/api/kontrol-balik", c.KontrolBalikController.Create)
-	c.App.Patch("/api/kontrol-balik/:id", c.KontrolBalikController.Update)
-	c.App.Delete("/api/kontrol-balik/:id", c.KontrolBalikController.Delete)
-	c.App.Patch("/api/kontrol-balik/:id/selesai", c.KontrolBalikController.Selesai)
-	c.App.Patch("/api/kontrol-balik/:id/batal", c.KontrolBalikController.Batal)
-
-	c.App.Get("/api/pengambilan-obat", c.PengambilanObatController.Search)
-	c.App.Get("/api/pengambilan-obat/:id", c.PengambilanObatController.Get)
-	c.App.Post("/api/pengambilan-obat", c.PengambilanObatController.Create)
-	c.App.Patch("/api/pengambilan-obat/:id", c.PengambilanObatController.Update)
-	c.App.Delete("/api/pengambilan-obat/:id", c.PengambilanObatController.Delete)
-	c.App.Patch("/api/pengambilan-obat/:id/batal", c.PengambilanObatController.Batal)
-	c.App.Patch("/api/pengambilan-obat/:id/diambil", c.PengambilanObatController.Diambil)
-
-	c.App.Get("/api/artikel", c.ArtikelController.Search)
-	c.App.Get("/api/artikel/:id", c.ArtikelController.Get)
-	c.App.Post("/api/artikel", c.ArtikelController.Create)
-	c.App.Patch("/api/artikel/:id", c.ArtikelController.Update)
-	c.App.Delete("/api/artikel/:id", c.ArtikelController.Delete)
+	// fiber menjalankan argumen middleware sebelum handler, sehingga policy ditulis setelah handler
+	can := middleware.PolicyMiddleware
+
+	c.App.Post("/api/auth/logout", c.AuthController.Logout, can(constant.PermissionAuthLogout))
+	c.App.Post("/api/auth/logout-semua", c.AuthController.LogoutSemua, can(constant.PermissionAuthLogoutSemua))
+
+	c.App.Patch("/api/admin-super/current/password", c.AdminSuperController.PasswordUpdate, can(constant.PermissionAdminSuperPasswordUpdate))
+
+	c.App.Get("/api/dua-faktor", c.DuaFaktorController.Get, can(constant.PermissionDuaFaktorGet))
+	c.App.Post("/api/dua-faktor/enrol", c.DuaFaktorController.Enrol, can(constant.PermissionDuaFaktorEnrol))
+	c.App.Post("/api/dua-faktor/aktifkan", c.DuaFaktorController.Aktifkan, can(constant.PermissionDuaFaktorAktifkan))
+	c.App.Post("/api/dua-faktor/nonaktifkan", c.DuaFaktorController.Nonaktifkan, can(constant.PermissionDuaFaktorNonaktifkan))
+	c.App.Post("/api/dua-faktor/kode-cadangan", c.DuaFaktorController.KodeCadangan, can(constant.PermissionDuaFaktorKodeCadangan))
+	c.App.Delete("/api/dua-faktor/:role/:id", c.DuaFaktorController.Reset, can(constant.PermissionDuaFaktorReset))
+
+	c.App.Get("/api/percobaan-login", c.PercobaanLoginController.List, can(constant.PermissionPercobaanLoginList))
+	c.App.Post("/api/percobaan-login/buka-kunci", c.PercobaanLoginController.BukaKunci, can(constant.PermissionPercobaanLoginBukaKunci))
+
+	c.App.Get("/api/audit", c.AuditController.List, can(constant.PermissionAuditList))
+
+	c.App.Get("/api/notifikasi", c.NotifikasiController.List, can(constant.PermissionNotifikasiList))
+	c.App.Post("/api/notifikasi/:id/kirim-ulang", c.NotifikasiController.KirimUlang, can(constant.PermissionNotifikasiKirimUlang))
+
+	c.App.Get("/api/riwayat-job", c.RiwayatJobController.List, can(constant.PermissionRiwayatJobList))
+
+	c.App.Get("/api/event", c.EventController.List, can(constant.PermissionEventList))
+	c.App.Post("/api/event/:id/kirim-ulang", c.EventController.KirimUlang, can(constant.PermissionEventKirimUlang))
+
+	c.App.Get("/api/webhook", c.WebhookController.List, can(constant.PermissionWebhookList))
+	c.App.Get("/api/webhook/pengiriman", c.WebhookController.ListPengiriman, can(constant.PermissionWebhookPengiriman))
+	c.App.Post("/api/webhook/pengiriman/:id/kirim-ulang", c.WebhookController.KirimUlang, can(constant.PermissionWebhookKirimUlang))
+	c.App.Get("/api/webhook/:id", c.WebhookController.Get, can(constant.PermissionWebhookGet))
+	c.App.Post("/api/webhook", c.WebhookController.Create, can(constant.PermissionWebhookCreate))
+	c.App.Patch("/api/webhook/:id", c.WebhookController.Update, can(constant.PermissionWebhookUpdate))
+	c.App.Delete("/api/webhook/:id", c.WebhookController.Delete, can(constant.PermissionWebhookDelete))
+	c.App.Post("/api/webhook/:id/rotasi-secret", c.WebhookController.RotasiSecret, can(constant.PermissionWebhookRotasiSecret))
+
+	c.App.Get("/api/admin-puskesmas", c.AdminPuskesmasController.List, can(constant.PermissionAdminPuskesmasList))
+	c.App.Get("/api/admin-puskesmas/current", c.AdminPuskesmasController.Current, can(constant.PermissionAdminPuskesmasCurrent))
+	c.App.Patch("/api/admin-puskesmas/current", c.AdminPuskesmasController.CurrentProfileUpdate, can(constant.PermissionAdminPuskesmasCurrentProfileUpdate))
+	c.App.Patch("/api/admin-puskesmas/current/password", c.AdminPuskesmasController.CurrentPasswordUpdate, can(constant.PermissionAdminPuskesmasCurrentPasswordUpdate))
+	c.App.Get("/api/admin-puskesmas/current/jadwal", c.JadwalOperasionalController.Current, can(constant.PermissionJadwalOperasionalCurrent))
+	c.App.Put("/api/admin-puskesmas/current/jadwal", c.JadwalOperasionalController.CurrentUpdate, can(constant.PermissionJadwalOperasionalCurrentUpdate))
+	c.App.Get("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Get, can(constant.PermissionAdminPuskesmasGet))
+	c.App.Post("/api/admin-puskesmas", c.AdminPuskesmasController.Create, can(constant.PermissionAdminPuskesmasCreate))
+	c.App.Patch("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Update, can(constant.PermissionAdminPuskesmasUpdate))
+	c.App.Delete("/api/admin-puskesmas/:id", c.AdminPuskesmasController.Delete, can(constant.PermissionAdminPuskesmasDelete))
+	c.App.Get("/api/admin-puskesmas/:id/jadwal", c.JadwalOperasionalController.Get, can(constant.PermissionJadwalOperasionalGet))
+	c.App.Put("/api/admin-puskesmas/:id/jadwal", c.JadwalOperasionalController.Update, can(constant.PermissionJadwalOperasionalUpdate))
+
+	c.App.Get("/api/admin-apotek", c.AdminApotekController.List, can(constant.PermissionAdminApotekList))
+	c.App.Get("/api/admin-apotek/current", c.AdminApotekController.Current, can(constant.PermissionAdminApotekCurrent))
+	c.App.Patch("/api/admin-apotek/current", c.AdminApotekController.CurrentProfileUpdate, can(constant.PermissionAdminApotekCurrentProfileUpdate))
+	c.App.Patch("/api/admin-apotek/current/password", c.AdminApotekController.CurrentPasswordUpdate, can(constant.PermissionAdminApotekCurrentPasswordUpdate))
+	c.App.Get("/api/admin-apotek/:id", c.AdminApotekController.Get, can(constant.PermissionAdminApotekGet))
+	c.App.Post("/api/admin-apotek", c.AdminApotekController.Create, can(constant.PermissionAdminApotekCreate))
+	c.App.Patch("/api/admin-apotek/:id", c.AdminApotekController.Update, can(constant.PermissionAdminApotekUpdate))
+	c.App.Delete("/api/admin-apotek/:id", c.AdminApotekController.Delete, can(constant.PermissionAdminApotekDelete))
+
+	c.App.Get("/api/pengguna/current", c.PenggunaController.Current, can(constant.PermissionPenggunaCurrent))
+	c.App.Patch("/api/pengguna/current", c.PenggunaController.CurrentProfileUpdate, can(constant.PermissionPenggunaCurrentProfileUpdate))
+	c.App.Patch("/api/pengguna/current/password", c.PenggunaController.CurrentPasswordUpdate, can(constant.PermissionPenggunaCurrentPasswordUpdate))
+	c.App.Patch("/api/pengguna/current/perangkat", c.PenggunaController.CurrentTokenPerangkatUpdate, can(constant.PermissionPenggunaCurrentTokenPerangkatUpdate))
+	c.App.Get("/api/pengguna", c.PenggunaController.List, can(constant.PermissionPenggunaList))
+	c.App.Get("/api/pengguna/:id", c.PenggunaController.Get, can(constant.PermissionPenggunaGet))
+	c.App.Post("/api/pengguna", c.PenggunaController.Create, can(constant.PermissionPenggunaCreate))
+	c.App.Patch("/api/pengguna/:id", c.PenggunaController.Update, can(constant.PermissionPenggunaUpdate))
+	c.App.Delete("/api/pengguna/:id", c.PenggunaController.Delete, can(constant.PermissionPenggunaDelete))
+
+	c.App.Get("/api/obat", c.ObatController.List, can(constant.PermissionObatList))
+	c.App.Get("/api/obat/batch/kedaluwarsa", c.ObatController.Kedaluwarsa, can(constant.PermissionObatKedaluwarsa))
+	c.App.Get("/api/obat/peringatan", c.ObatController.Peringatan, can(constant.PermissionObatPeringatan))
+	c.App.Get("/api/obat/:id", c.ObatController.Get, can(constant.PermissionObatGet))
+	c.App.Post("/api/obat", c.ObatController.Create, can(constant.PermissionObatCreate))
+	c.App.Patch("/api/obat/:id", c.ObatController.Update, can(constant.PermissionObatUpdate))
+	c.App.Delete("/api/obat/:id", c.ObatController.Delete, can(constant.PermissionObatDelete))
+	c.App.Get("/api/obat/:id/mutasi", c.ObatController.Mutasi, can(constant.PermissionObatMutasi))
+	c.App.Patch("/api/obat/:id/restok", c.ObatController.Restok, can(constant.PermissionObatRestok))
+	c.App.Get("/api/obat/:id/batch", c.ObatController.Batch, can(constant.PermissionObatBatch))
+	c.App.Patch("/api/obat/:id/batch/:idBatch", c.ObatController.KoreksiBatch, can(constant.PermissionObatKoreksiBatch))
+
+	c.App.Get("/api/pasien", c.PasienController.Search, can(constant.PermissionPasienSearch))
+	c.App.Get("/api/pasien/:id", c.PasienController.Get, can(constant.PermissionPasienGet))
+	c.App.Get("/api/pasien/:id/timeline", c.PasienController.Timeline, can(constant.PermissionPasienTimeline))
+	c.App.Get("/api/pasien/:id/tanda-vital", c.TandaVitalController.Get, can(constant.PermissionPasienTandaVital))
+	c.App.Get("/api/pasien/:id/hasil-lab", c.HasilPemeriksaanLabController.Riwayat, can(constant.PermissionPasienHasilLab))
+	c.App.Post("/api/pasien", c.PasienController.Create, can(constant.PermissionPasienCreate))
+	c.App.Patch("/api/pasien/:id", c.PasienController.Update, can(constant.PermissionPasienUpdate))
+	c.App.Delete("/api/pasien/:id", c.PasienController.Delete, can(constant.PermissionPasienDelete))
+	c.App.Patch("/api/pasien/:id/selesai", c.PasienController.Selesai, can(constant.PermissionPasienSelesai))
+
+	c.App.Get("/api/kontrol-balik", c.KontrolBalikController.Search, can(constant.PermissionKontrolBalikSearch))
+	c.App.Get("/api/kontrol-balik/slot", c.KontrolBalikController.Slot, can(constant.PermissionKontrolBalikSlot))
+	c.App.Get("/api/kontrol-balik/:id", c.KontrolBalikController.Get, can(constant.PermissionKontrolBalikGet))
+	c.App.Post("/api/kontrol-balik", c.KontrolBalikController.Create, can(constant.PermissionKontrolBalikCreate))
+	c.App.Patch("/api/kontrol-balik/:id", c.KontrolBalikController.Update, can(constant.PermissionKontrolBalikUpdate))
+	c.App.Delete("/api/kontrol-balik/:id", c.KontrolBalikController.Delete, can(constant.PermissionKontrolBalikDelete))
+	c.App.Patch("/api/kontrol-balik/:id/selesai", c.KontrolBalikController.Selesai, can(constant.PermissionKontrolBalikSelesai))
+	c.App.Patch("/api/kontrol-balik/:id/batal", c.KontrolBalikController.Batal, can(constant.PermissionKontrolBalikBatal))
+	c.App.Get("/api/kontrol-balik/:id/hasil-lab", c.HasilPemeriksaanLabController.Get, can(constant.PermissionKontrolBalikHasilLab))
+	c.App.Put("/api/kontrol-balik/:id/hasil-lab", c.HasilPemeriksaanLabController.Update, can(constant.PermissionKontrolBalikHasilLabUpdate))
+
+	c.App.Get("/api/pemeriksaan-lab", c.PemeriksaanLabController.List, can(constant.PermissionPemeriksaanLabList))
+	c.App.Get("/api/pemeriksaan-lab/:id", c.PemeriksaanLabController.Get, can(constant.PermissionPemeriksaanLabGet))
+	c.App.Post("/api/pemeriksaan-lab", c.PemeriksaanLabController.Create, can(constant.PermissionPemeriksaanLabCreate))
+	c.App.Patch("/api/pemeriksaan-lab/:id", c.PemeriksaanLabController.Update, can(constant.PermissionPemeriksaanLabUpdate))
+	c.App.Delete("/api/pemeriksaan-lab/:id", c.PemeriksaanLabController.Delete, can(constant.PermissionPemeriksaanLabDelete))
+
+	c.App.Get("/api/pengambilan-obat", c.PengambilanObatController.Search, can(constant.PermissionPengambilanObatSearch))
+	c.App.Get("/api/pengambilan-obat/resi/:resi", c.PengambilanObatController.GetByResi, can(constant.PermissionPengambilanObatGetByResi))
+	c.App.Get("/api/pengambilan-obat/:id", c.PengambilanObatController.Get, can(constant.PermissionPengambilanObatGet))
+	c.App.Get("/api/pengambilan-obat/:id/qr", c.PengambilanObatController.QrCode, can(constant.PermissionPengambilanObatQrCode))
+	c.App.Post("/api/pengambilan-obat", c.PengambilanObatController.Create, can(constant.PermissionPengambilanObatCreate))
+	c.App.Patch("/api/pengambilan-obat/:id", c.PengambilanObatController.Update, can(constant.PermissionPengambilanObatUpdate))
+	c.App.Delete("/api/pengambilan-obat/:id", c.PengambilanObatController.Delete, can(constant.PermissionPengambilanObatDelete))
+	c.App.Patch("/api/pengambilan-obat/:id/batal", c.PengambilanObatController.Batal, can(constant.PermissionPengambilanObatBatal))
+	c.App.Patch("/api/pengambilan-obat/:id/diambil", c.PengambilanObatController.Diambil, can(constant.PermissionPengambilanObatDiambil))
+
+	c.App.Post("/api/artikel", c.ArtikelController.Create, can(constant.PermissionArtikelCreate))
+	c.App.Patch("/api/artikel/:id", c.ArtikelController.Update, can(constant.PermissionArtikelUpdate))
+	c.App.Delete("/api/artikel/:id", c.ArtikelController.Delete, can(constant.PermissionArtikelDelete))
 }
 
 func (c *Config) Setup() {
@@ -0,0 +1,206 @@
+package route_test
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/recover"
+	"github.com/spf13/viper"
+	"net/http/httptest"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/route"
+	"regexp"
+	"testing"
+)
+
+const (
+	super     = constant.RoleAdminSuper
+	puskesmas = constant.RoleAdminPuskesmas
+	apotek    = constant.RoleAdminApotek
+	pengguna  = constant.RolePengguna
+	semua     = constant.ScopeSemua
+	milik     = constant.ScopeMilik
+)
+
+// role yang boleh mengakses sebuah route beserta scope datanya
+type akses map[string]string
+
+var aksesRoute = []struct {
+	method string
+	path   string
+	akses  akses
+}{
+	{fiber.MethodPost, "/api/auth/logout", akses{super: milik, puskesmas: milik, apotek: milik, pengguna: milik}},
+	{fiber.MethodPost, "/api/auth/logout-semua", akses{super: milik, puskesmas: milik, apotek: milik, pengguna: milik}},
+	{fiber.MethodPatch, "/api/admin-super/current/password", akses{super: milik}},
+	{fiber.MethodGet, "/api/dua-faktor", akses{super: milik, puskesmas: milik, apotek: milik}},
+	{fiber.MethodPost, "/api/dua-faktor/enrol", akses{super: milik, puskesmas: milik, apotek: milik}},
+	{fiber.MethodPost, "/api/dua-faktor/aktifkan", akses{super: milik, puskesmas: milik, apotek: milik}},
+	{fiber.MethodPost, "/api/dua-faktor/nonaktifkan", akses{super: milik, puskesmas: milik, apotek: milik}},
+	{fiber.MethodPost, "/api/dua-faktor/kode-cadangan", akses{super: milik, puskesmas: milik, apotek: milik}},
+	{fiber.MethodDelete, "/api/dua-faktor/:role/:id", akses{super: semua}},
+	{fiber.MethodGet, "/api/percobaan-login", akses{super: semua}},
+	{fiber.MethodPost, "/api/percobaan-login/buka-kunci", akses{super: semua}},
+	{fiber.MethodGet, "/api/audit", akses{super: semua}},
+	{fiber.MethodGet, "/api/notifikasi", akses{super: semua}},
+	{fiber.MethodPost, "/api/notifikasi/:id/kirim-ulang", akses{super: semua}},
+	{fiber.MethodGet, "/api/riwayat-job", akses{super: semua}},
+	{fiber.MethodGet, "/api/event", akses{super: semua}},
+	{fiber.MethodPost, "/api/event/:id/kirim-ulang", akses{super: semua}},
+	{fiber.MethodGet, "/api/webhook", akses{super: semua}},
+	{fiber.MethodGet, "/api/webhook/pengiriman", akses{super: semua}},
+	{fiber.MethodPost, "/api/webhook/pengiriman/:id/kirim-ulang", akses{super: semua}},
+	{fiber.MethodGet, "/api/webhook/:id", akses{super: semua}},
+	{fiber.MethodPost, "/api/webhook", akses{super: semua}},
+	{fiber.MethodPatch, "/api/webhook/:id", akses{super: semua}},
+	{fiber.MethodDelete, "/api/webhook/:id", akses{super: semua}},
+	{fiber.MethodPost, "/api/webhook/:id/rotasi-secret", akses{super: semua}},
+	{fiber.MethodGet, "/api/admin-puskesmas", akses{super: semua, puskesmas: semua, apotek: semua, pengguna: semua}},
+	{fiber.MethodGet, "/api/admin-puskesmas/current", akses{puskesmas: milik}},
+	{fiber.MethodPatch, "/api/admin-puskesmas/current", akses{puskesmas: milik}},
+	{fiber.MethodPatch, "/api/admin-puskesmas/current/password", akses{puskesmas: milik}},
+	{fiber.MethodGet, "/api/admin-puskesmas/current/jadwal", akses{puskesmas: milik}},
+	{fiber.MethodPut, "/api/admin-puskesmas/current/jadwal", akses{puskesmas: milik}},
+	{fiber.MethodGet, "/api/admin-puskesmas/:id", akses{super: semua}},
+	{fiber.MethodPost, "/api/admin-puskesmas", akses{super: semua}},
+	{fiber.MethodPatch, "/api/admin-puskesmas/:id", akses{super: semua}},
+	{fiber.MethodDelete, "/api/admin-puskesmas/:id", akses{super: semua}},
+	{fiber.MethodGet, "/api/admin-puskesmas/:id/jadwal", akses{super: semua, puskesmas: semua, apotek: semua, pengguna: semua}},
+	{fiber.MethodPut, "/api/admin-puskesmas/:id/jadwal", akses{super: semua}},
+	{fiber.MethodGet, "/api/admin-apotek", akses{super: semua, puskesmas: semua, apotek: semua, pengguna: semua}},
+	{fiber.MethodGet, "/api/admin-apotek/current", akses{apotek: milik}},
+	{fiber.MethodPatch, "/api/admin-apotek/current", akses{apotek: milik}},
+	{fiber.MethodPatch, "/api/admin-apotek/current/password", akses{apotek: milik}},
+	{fiber.MethodGet, "/api/admin-apotek/:id", akses{super: semua}},
+	{fiber.MethodPost, "/api/admin-apotek", akses{super: semua}},
+	{fiber.MethodPatch, "/api/admin-apotek/:id", akses{super: semua}},
+	{fiber.MethodDelete, "/api/admin-apotek/:id", akses{super: semua}},
+	{fiber.MethodGet, "/api/pengguna/current", akses{pengguna: milik}},
+	{fiber.MethodPatch, "/api/pengguna/current", akses{pengguna: milik}},
+	{fiber.MethodPatch, "/api/pengguna/current/password", akses{pengguna: milik}},
+	{fiber.MethodPatch, "/api/pengguna/current/perangkat", akses{pengguna: milik}},
+	{fiber.MethodGet, "/api/pengguna", akses{super: semua, puskesmas: semua}},
+	{fiber.MethodGet, "/api/pengguna/:id", akses{super: semua}},
+	{fiber.MethodPost, "/api/pengguna", akses{super: semua}},
+	{fiber.MethodPatch, "/api/pengguna/:id", akses{super: semua}},
+	{fiber.MethodDelete, "/api/pengguna/:id", akses{super: semua}},
+	{fiber.MethodGet, "/api/obat", akses{super: semua, puskesmas: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/obat/batch/kedaluwarsa", akses{super: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/obat/peringatan", akses{super: semua, puskesmas: milik, apotek: milik}},
+	{fiber.MethodGet, "/api/obat/:id", akses{super: semua, apotek: milik}},
+	{fiber.MethodPost, "/api/obat", akses{super: semua, apotek: milik}},
+	{fiber.MethodPatch, "/api/obat/:id", akses{super: semua, apotek: milik}},
+	{fiber.MethodDelete, "/api/obat/:id", akses{super: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/obat/:id/mutasi", akses{super: semua, apotek: milik}},
+	{fiber.MethodPatch, "/api/obat/:id/restok", akses{super: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/obat/:id/batch", akses{super: semua, apotek: milik}},
+	{fiber.MethodPatch, "/api/obat/:id/batch/:idBatch", akses{super: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/pasien", akses{super: semua, puskesmas: milik, pengguna: milik}},
+	{fiber.MethodGet, "/api/pasien/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodGet, "/api/pasien/:id/timeline", akses{super: semua, puskesmas: milik, pengguna: milik}},
+	{fiber.MethodGet, "/api/pasien/:id/tanda-vital", akses{super: semua, puskesmas: milik, pengguna: milik}},
+	{fiber.MethodGet, "/api/pasien/:id/hasil-lab", akses{super: semua, puskesmas: milik, pengguna: milik}},
+	{fiber.MethodPost, "/api/pasien", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/pasien/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodDelete, "/api/pasien/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/pasien/:id/selesai", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodGet, "/api/kontrol-balik", akses{super: semua, puskesmas: milik, pengguna: milik}},
+	{fiber.MethodGet, "/api/kontrol-balik/slot", akses{super: semua, puskesmas: milik, pengguna: semua}},
+	{fiber.MethodGet, "/api/kontrol-balik/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPost, "/api/kontrol-balik", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/kontrol-balik/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodDelete, "/api/kontrol-balik/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/kontrol-balik/:id/selesai", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/kontrol-balik/:id/batal", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodGet, "/api/kontrol-balik/:id/hasil-lab", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPut, "/api/kontrol-balik/:id/hasil-lab", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodGet, "/api/pemeriksaan-lab", akses{super: semua, puskesmas: semua}},
+	{fiber.MethodGet, "/api/pemeriksaan-lab/:id", akses{super: semua, puskesmas: semua}},
+	{fiber.MethodPost, "/api/pemeriksaan-lab", akses{super: semua}},
+	{fiber.MethodPatch, "/api/pemeriksaan-lab/:id", akses{super: semua}},
+	{fiber.MethodDelete, "/api/pemeriksaan-lab/:id", akses{super: semua}},
+	{fiber.MethodGet, "/api/pengambilan-obat", akses{super: semua, puskesmas: milik, apotek: milik, pengguna: milik}},
+	{fiber.MethodGet, "/api/pengambilan-obat/resi/:resi", akses{super: semua, apotek: milik}},
+	{fiber.MethodGet, "/api/pengambilan-obat/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodGet, "/api/pengambilan-obat/:id/qr", akses{super: semua, pengguna: milik}},
+	{fiber.MethodPost, "/api/pengambilan-obat", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/pengambilan-obat/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodDelete, "/api/pengambilan-obat/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/pengambilan-obat/:id/batal", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/pengambilan-obat/:id/diambil", akses{super: semua, apotek: milik}},
+	{fiber.MethodPost, "/api/artikel", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodPatch, "/api/artikel/:id", akses{super: semua, puskesmas: milik}},
+	{fiber.MethodDelete, "/api/artikel/:id", akses{super: semua, puskesmas: milik}},
+}
+
+var parameterRoute = regexp.MustCompile(`:\w+`)
+
+func TestRoutePolicy(t *testing.T) {
+	// auth terakhir yang dibuat, policy mengisi scope-nya bila role diizinkan
+	var auth *model.Auth
+	app := fiber.New()
+	app.Use(recover.New())
+	config := &route.Config{
+		App:    app,
+		Config: viper.New(),
+		AuthMiddleware: func(ctx fiber.Ctx) error {
+			auth = &model.Auth{ID: 1, Role: ctx.Get("X-Role")}
+			ctx.Locals("auth", auth)
+			return ctx.Next()
+		},
+	}
+	config.SetupGuestRoute()
+	guest := make(map[string]bool)
+	for _, r := range app.GetRoutes(true) {
+		guest[r.Method+" "+r.Path] = true
+	}
+	config.SetupAuthRoute()
+
+	diharapkan := make(map[string]akses, len(aksesRoute))
+	for _, r := range aksesRoute {
+		diharapkan[r.method+" "+r.path] = r.akses
+	}
+
+	diuji := make(map[string]bool)
+	for _, r := range app.GetRoutes(true) {
+		kunci := r.Method + " " + r.Path
+		// fiber mendaftarkan HEAD untuk setiap GET
+		if guest[kunci] || r.Method == fiber.MethodHead {
+			continue
+		}
+		aksesDiharapkan, ok := diharapkan[kunci]
+		if !ok {
+			t.Errorf("%s belum memiliki akses yang diharapkan pada pengujian", kunci)
+			continue
+		}
+		diuji[kunci] = true
+
+		path := parameterRoute.ReplaceAllString(r.Path, "1")
+		for _, role := range []string{super, puskesmas, apotek, pengguna} {
+			request := httptest.NewRequest(r.Method, path, nil)
+			request.Header.Set("X-Role", role)
+			response, err := app.Test(request)
+			if err != nil {
+				t.Fatalf("%s sebagai %s: %v", kunci, role, err)
+			}
+
+			scope, diizinkan := aksesDiharapkan[role]
+			if !diizinkan {
+				if response.StatusCode != fiber.StatusForbidden {
+					t.Errorf("%s sebagai %s seharusnya ditolak, status %d", kunci, role, response.StatusCode)
+				}
+				continue
+			}
+			if response.StatusCode == fiber.StatusForbidden {
+				t.Errorf("%s sebagai %s seharusnya diizinkan", kunci, role)
+			} else if auth.Scope != scope {
+				t.Errorf("%s sebagai %s seharusnya scope %s, didapat %q", kunci, role, scope, auth.Scope)
+			}
+		}
+	}
+
+	for kunci := range diharapkan {
+		if !diuji[kunci] {
+			t.Errorf("%s tidak terdaftar pada route", kunci)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,16 +13,21 @@ import (
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
-	"time"
 )
 
 type AdminApotekService struct {
-	DB                    *gorm.DB
-	AdminApotekRepository *repository.AdminApotekRepository
-	ObatRepository        *repository.ObatRepository
-	RecaptchaAdapter      *adapter.Captcha
-	Validator             *validator.Validate
-	Config                *viper.Viper
+	DB                           *gorm.DB
+	AdminApotekRepository        *repository.AdminApotekRepository
+	ObatRepository               *repository.ObatRepository
+	RecaptchaAdapter             *adapter.Captcha
+	SesiRepository               *repository.SesiRepository
+	RefreshTokenRepository       *repository.RefreshTokenRepository
+	TokenAdapter                 *adapter.TokenAdapter
+	PercobaanLoginRepository     *repository.PercobaanLoginRepository
+	DuaFaktorRepository          *repository.DuaFaktorRepository
+	TantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository
+	Validator                    *validator.Validate
+	Config                       *viper.Viper
 }
 
 func NewAdminApotekService(db *gorm.DB,
@@ -31,23 +35,40 @@ func NewAdminApotekService(db *gorm.DB,
 	obatRepository *repository.ObatRepository,
 	validator *validator.Validate,
 	captchaAdapter *adapter.Captcha,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	percobaanLoginRepository *repository.PercobaanLoginRepository,
+	duaFaktorRepository *repository.DuaFaktorRepository,
+	tantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository,
 	config *viper.Viper) *AdminApotekService {
 	return &AdminApotekService{db,
 		adminApotekRepository,
 		obatRepository,
 		captchaAdapter,
+		sesiRepository,
+		refreshTokenRepository,
+		tokenAdapter,
+		percobaanLoginRepository,
+		duaFaktorRepository,
+		tantanganDuaFaktorRepository,
 		validator,
 		config}
 }
 
-func (s *AdminApotekService) List(ctx context.Context) (*[]model.AdminApotekResponse, error) {
+func (s *AdminApotekService) List(ctx context.Context, request *model.AdminApotekListRequest) (*model.PageResponse[model.AdminApotekResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	adminApotek := new([]entity.AdminApotek)
-	if err := s.AdminApotekRepository.FindAll(tx, adminApotek); err != nil {
+	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
+		return nil, validasiError(err)
+	}
+
+	adminApotek := new([]entity.AdminApotek)
+	result, err := s.AdminApotekRepository.Search(tx, adminApotek, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.AdminApotekResponse
@@ -66,7 +87,7 @@ func (s *AdminApotekService) List(ctx context.Context) (*[]model.AdminApotekResp
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *AdminApotekService) Get(ctx context.Context, request *model.AdminApotekGetRequest) (*model.AdminApotekResponse, error) {
@@ -75,7 +96,7 @@ func (s *AdminApotekService) Get(ctx context.Context, request *model.AdminApotek
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -106,7 +127,7 @@ func (s *AdminApotekService) Create(ctx context.Context, request *model.AdminApo
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	total, err := s.AdminApotekRepository.CountByUsername(tx, request.Username)
@@ -115,7 +136,7 @@ func (s *AdminApotekService) Create(ctx context.Context, request *model.AdminApo
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.AdminApotekRepository.CountByTelepon(tx, request.Telepon)
@@ -124,7 +145,7 @@ func (s *AdminApotekService) Create(ctx context.Context, request *model.AdminApo
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
@@ -160,7 +181,7 @@ func (s *AdminApotekService) Update(ctx context.Context, request *model.AdminApo
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -175,7 +196,7 @@ func (s *AdminApotekService) Update(ctx context.Context, request *model.AdminApo
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminApotek.Username != request.Username {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.AdminApotekRepository.CountByTelepon(tx, request.Telepon)
@@ -184,7 +205,7 @@ func (s *AdminApotekService) Update(ctx context.Context, request *model.AdminApo
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminApotek.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	var password []byte
@@ -203,6 +224,7 @@ func (s *AdminApotekService) Update(ctx context.Context, request *model.AdminApo
 	adminApotek.Telepon = request.Telepon
 	if string(password) != "" {
 		adminApotek.Password = string(password)
+		adminApotek.VersiToken++
 	}
 
 	if err := s.AdminApotekRepository.Update(tx, adminApotek); err != nil {
@@ -224,7 +246,7 @@ func (s *AdminApotekService) Delete(ctx context.Context, request *model.AdminApo
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -234,7 +256,7 @@ func (s *AdminApotekService) Delete(ctx context.Context, request *model.AdminApo
 	}
 
 	if err := s.ObatRepository.FindByIdAdminApotek(tx, &entity.Obat{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Admin apotek masih terkait dengan data obat yang ada")
+		return model.NewAppError(constant.KodeErrorAdminApotekTerkaitObat)
 	}
 
 	if err := s.AdminApotekRepository.Delete(tx, adminApotek); err != nil {
@@ -250,83 +272,64 @@ func (s *AdminApotekService) Delete(ctx context.Context, request *model.AdminApo
 	return nil
 }
 
-func (s *AdminApotekService) Login(ctx context.Context, request *model.AdminApotekLoginRequest) (*model.AdminApotekResponse, error) {
+func (s *AdminApotekService) Login(ctx context.Context, request *model.AdminApotekLoginRequest) (*model.TokenResponse, error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
-	captchaRequest := &model.CaptchaRequest{
-		TokenCaptcha: request.TokenCaptcha,
-		Secret:       s.Config.GetString("captcha.secret"),
+	if err := cekPercobaanLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, s.Config, constant.RoleAdminApotek, request.Username, request.Ip); err != nil {
+		return nil, err
 	}
 
-	ok, err := s.RecaptchaAdapter.Verify(captchaRequest)
+	ok, err := s.RecaptchaAdapter.Verify(constant.RoleAdminApotek, request.TokenCaptcha)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 	if !ok {
-		return nil, fiber.ErrBadRequest
+		return nil, model.NewAppError(constant.KodeErrorCaptchaTidakValid)
 	}
 
 	adminApotek := new(entity.AdminApotek)
 	if err := s.AdminApotekRepository.FindByUsername(tx, adminApotek, request.Username); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminApotek, request.Username, request.Ip)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminApotek.Password), []byte(request.Password)); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
-	}
-
-	key := s.Config.GetString("jwt.secret")
-	exp := s.Config.GetInt("jwt.exp")
-	claims := jwt.MapClaims{
-		"sub":  adminApotek.ID,
-		"role": constant.RoleAdminApotek,
-		"exp":  time.Now().Add(time.Duration(exp) * time.Hour).Unix(),
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminApotek, request.Username, request.Ip)
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+	auth := &model.Auth{ID: adminApotek.ID, Role: constant.RoleAdminApotek, VersiToken: adminApotek.VersiToken}
+	token, err := tantanganDuaFaktor(tx, s.DuaFaktorRepository, s.TantanganDuaFaktorRepository, s.TokenAdapter, s.Config, auth, adminApotek.Username)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
-
-	if err := tx.Commit().Error; err != nil {
-		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
-	}
-
-	return &model.AdminApotekResponse{Token: token}, nil
-}
-
-func (s *AdminApotekService) Verify(ctx context.Context, request *model.AdminApotekVerifyRequest) error {
-	tx := s.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
-
-	if err := s.Validator.Struct(request); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrBadRequest
-	}
-
-	adminApotek := new(entity.AdminApotek)
-	if err := s.AdminApotekRepository.FindById(tx, adminApotek, request.ID); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrNotFound
+	// login baru dianggap berhasil setelah faktor kedua lolos, lihat DuaFaktorService.Login
+	if token == nil {
+		token, err = buatSesi(tx, s.SesiRepository, s.RefreshTokenRepository, s.TokenAdapter, auth)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := catatPercobaanLogin(tx, s.PercobaanLoginRepository, constant.RoleAdminApotek, request.Username, request.Ip, constant.StatusPercobaanLoginBerhasil); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrInternalServerError
+		return nil, fiber.ErrInternalServerError
 	}
 
-	return nil
+	return token, nil
 }
 
 func (s *AdminApotekService) Current(ctx context.Context, request *model.AdminApotekGetRequest) (*model.AdminApotekResponse, error) {
@@ -335,7 +338,7 @@ func (s *AdminApotekService) Current(ctx context.Context, request *model.AdminAp
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -364,7 +367,7 @@ func (s *AdminApotekService) CurrentProfileUpdate(ctx context.Context, request *
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -379,7 +382,7 @@ func (s *AdminApotekService) CurrentProfileUpdate(ctx context.Context, request *
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminApotek.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	adminApotek.NamaApotek = request.NamaApotek
@@ -406,7 +409,7 @@ func (s *AdminApotekService) CurrentPasswordUpdate(ctx context.Context, request
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminApotek := new(entity.AdminApotek)
@@ -417,7 +420,7 @@ func (s *AdminApotekService) CurrentPasswordUpdate(ctx context.Context, request
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminApotek.Password), []byte(request.CurrentPassword)); err != nil {
 		slog.Error(err.Error())
-		return fiber.NewError(fiber.StatusUnauthorized, "Password saat ini salah")
+		return model.NewAppError(constant.KodeErrorPasswordSaatIniSalah)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
@@ -426,6 +429,7 @@ func (s *AdminApotekService) CurrentPasswordUpdate(ctx context.Context, request
 		return fiber.ErrInternalServerError
 	}
 	adminApotek.Password = string(password)
+	adminApotek.VersiToken++
 
 	if err := s.AdminApotekRepository.Update(tx, adminApotek); err != nil {
 		slog.Error(err.Error())
@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,35 +13,53 @@ import (
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
-	"time"
 )
 
 type AdminPuskesmasService struct {
-	DB                       *gorm.DB
-	AdminPuskesmasRepository *repository.AdminPuskesmasRepository
-	PasienRepository         *repository.PasienRepository
-	RecaptchaAdapter         *adapter.Captcha
-	Validator                *validator.Validate
-	Config                   *viper.Viper
+	DB                           *gorm.DB
+	AdminPuskesmasRepository     *repository.AdminPuskesmasRepository
+	PasienRepository             *repository.PasienRepository
+	JadwalOperasionalRepository  *repository.JadwalOperasionalRepository
+	RecaptchaAdapter             *adapter.Captcha
+	SesiRepository               *repository.SesiRepository
+	RefreshTokenRepository       *repository.RefreshTokenRepository
+	TokenAdapter                 *adapter.TokenAdapter
+	PercobaanLoginRepository     *repository.PercobaanLoginRepository
+	DuaFaktorRepository          *repository.DuaFaktorRepository
+	TantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository
+	Validator                    *validator.Validate
+	Config                       *viper.Viper
 }
 
 func NewAdminPuskesmasService(db *gorm.DB,
 	adminPuskesmasRepository *repository.AdminPuskesmasRepository,
 	pasienRepository *repository.PasienRepository,
+	jadwalOperasionalRepository *repository.JadwalOperasionalRepository,
 	captchaAdapter *adapter.Captcha,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	percobaanLoginRepository *repository.PercobaanLoginRepository,
+	duaFaktorRepository *repository.DuaFaktorRepository,
+	tantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository,
 	validator *validator.Validate,
 	config *viper.Viper) *AdminPuskesmasService {
-	return &AdminPuskesmasService{db, adminPuskesmasRepository, pasienRepository, captchaAdapter, validator, config}
+	return &AdminPuskesmasService{db, adminPuskesmasRepository, pasienRepository, jadwalOperasionalRepository, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, duaFaktorRepository, tantanganDuaFaktorRepository, validator, config}
 }
 
-func (s *AdminPuskesmasService) List(ctx context.Context) (*[]model.AdminPuskesmasResponse, error) {
+func (s *AdminPuskesmasService) List(ctx context.Context, request *model.AdminPuskesmasListRequest) (*model.PageResponse[model.AdminPuskesmasResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	adminPuskesmas := new([]entity.AdminPuskesmas)
-	if err := s.AdminPuskesmasRepository.FindAll(tx, adminPuskesmas); err != nil {
+	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
+		return nil, validasiError(err)
+	}
+
+	adminPuskesmas := new([]entity.AdminPuskesmas)
+	result, err := s.AdminPuskesmasRepository.Search(tx, adminPuskesmas, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.AdminPuskesmasResponse
@@ -61,7 +78,7 @@ func (s *AdminPuskesmasService) List(ctx context.Context) (*[]model.AdminPuskesm
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *AdminPuskesmasService) Get(ctx context.Context, request *model.AdminPuskesmasGetRequest) (*model.AdminPuskesmasResponse, error) {
@@ -70,7 +87,7 @@ func (s *AdminPuskesmasService) Get(ctx context.Context, request *model.AdminPus
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -101,7 +118,7 @@ func (s *AdminPuskesmasService) Create(ctx context.Context, request *model.Admin
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	total, err := s.AdminPuskesmasRepository.CountByUsername(tx, request.Username)
@@ -110,7 +127,7 @@ func (s *AdminPuskesmasService) Create(ctx context.Context, request *model.Admin
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.AdminPuskesmasRepository.CountByTelepon(tx, request.Telepon)
@@ -119,7 +136,7 @@ func (s *AdminPuskesmasService) Create(ctx context.Context, request *model.Admin
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
@@ -155,7 +172,7 @@ func (s *AdminPuskesmasService) Update(ctx context.Context, request *model.Admin
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -170,7 +187,7 @@ func (s *AdminPuskesmasService) Update(ctx context.Context, request *model.Admin
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminPuskesmas.Username != request.Username {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.AdminPuskesmasRepository.CountByTelepon(tx, request.Telepon)
@@ -179,7 +196,7 @@ func (s *AdminPuskesmasService) Update(ctx context.Context, request *model.Admin
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminPuskesmas.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	var password []byte
@@ -198,6 +215,7 @@ func (s *AdminPuskesmasService) Update(ctx context.Context, request *model.Admin
 	adminPuskesmas.Telepon = request.Telepon
 	if string(password) != "" {
 		adminPuskesmas.Password = string(password)
+		adminPuskesmas.VersiToken++
 	}
 
 	if err := s.AdminPuskesmasRepository.Update(tx, adminPuskesmas); err != nil {
@@ -219,7 +237,7 @@ func (s *AdminPuskesmasService) Delete(ctx context.Context, request *model.Admin
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -229,7 +247,12 @@ func (s *AdminPuskesmasService) Delete(ctx context.Context, request *model.Admin
 	}
 
 	if err := s.PasienRepository.FindByIdAdminPuskesmas(tx, &entity.Pasien{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Admin puskesmas masih terkait dengan data pasien yang ada")
+		return model.NewAppError(constant.KodeErrorAdminPuskesmasTerkaitPasien)
+	}
+
+	if err := s.JadwalOperasionalRepository.DeleteByIdAdminPuskesmas(tx, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
 	}
 
 	if err := s.AdminPuskesmasRepository.Delete(tx, adminPuskesmas); err != nil {
@@ -245,83 +268,64 @@ func (s *AdminPuskesmasService) Delete(ctx context.Context, request *model.Admin
 	return nil
 }
 
-func (s *AdminPuskesmasService) Login(ctx context.Context, request *model.AdminPuskesmasLoginRequest) (*model.AdminPuskesmasResponse, error) {
+func (s *AdminPuskesmasService) Login(ctx context.Context, request *model.AdminPuskesmasLoginRequest) (*model.TokenResponse, error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
-	captchaRequest := &model.CaptchaRequest{
-		TokenCaptcha: request.TokenCaptcha,
-		Secret:       s.Config.GetString("captcha.secret"),
+	if err := cekPercobaanLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, s.Config, constant.RoleAdminPuskesmas, request.Username, request.Ip); err != nil {
+		return nil, err
 	}
 
-	ok, err := s.RecaptchaAdapter.Verify(captchaRequest)
+	ok, err := s.RecaptchaAdapter.Verify(constant.RoleAdminPuskesmas, request.TokenCaptcha)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 	if !ok {
-		return nil, fiber.ErrBadRequest
+		return nil, model.NewAppError(constant.KodeErrorCaptchaTidakValid)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
 	if err := s.AdminPuskesmasRepository.FindByUsername(tx, adminPuskesmas, request.Username); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminPuskesmas, request.Username, request.Ip)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminPuskesmas.Password), []byte(request.Password)); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
-	}
-
-	key := s.Config.GetString("jwt.secret")
-	exp := s.Config.GetInt("jwt.exp")
-	claims := jwt.MapClaims{
-		"sub":  adminPuskesmas.ID,
-		"role": constant.RoleAdminPuskesmas,
-		"exp":  time.Now().Add(time.Duration(exp) * time.Hour).Unix(),
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminPuskesmas, request.Username, request.Ip)
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+	auth := &model.Auth{ID: adminPuskesmas.ID, Role: constant.RoleAdminPuskesmas, VersiToken: adminPuskesmas.VersiToken}
+	token, err := tantanganDuaFaktor(tx, s.DuaFaktorRepository, s.TantanganDuaFaktorRepository, s.TokenAdapter, s.Config, auth, adminPuskesmas.Username)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
-
-	if err := tx.Commit().Error; err != nil {
-		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
-	}
-
-	return &model.AdminPuskesmasResponse{Token: token}, nil
-}
-
-func (s *AdminPuskesmasService) Verify(ctx context.Context, request *model.AdminPuskesmasVerifyRequest) error {
-	tx := s.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
-
-	if err := s.Validator.Struct(request); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrBadRequest
-	}
-
-	adminPuskesmas := new(entity.AdminPuskesmas)
-	if err := s.AdminPuskesmasRepository.FindById(tx, adminPuskesmas, request.ID); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrNotFound
+	// login baru dianggap berhasil setelah faktor kedua lolos, lihat DuaFaktorService.Login
+	if token == nil {
+		token, err = buatSesi(tx, s.SesiRepository, s.RefreshTokenRepository, s.TokenAdapter, auth)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := catatPercobaanLogin(tx, s.PercobaanLoginRepository, constant.RoleAdminPuskesmas, request.Username, request.Ip, constant.StatusPercobaanLoginBerhasil); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrInternalServerError
+		return nil, fiber.ErrInternalServerError
 	}
 
-	return nil
+	return token, nil
 }
 
 func (s *AdminPuskesmasService) Current(ctx context.Context, request *model.AdminPuskesmasGetRequest) (*model.AdminPuskesmasResponse, error) {
@@ -330,7 +334,7 @@ func (s *AdminPuskesmasService) Current(ctx context.Context, request *model.Admi
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -359,7 +363,7 @@ func (s *AdminPuskesmasService) CurrentProfileUpdate(ctx context.Context, reques
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -374,7 +378,7 @@ func (s *AdminPuskesmasService) CurrentProfileUpdate(ctx context.Context, reques
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && adminPuskesmas.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	adminPuskesmas.NamaPuskesmas = request.NamaPuskesmas
@@ -401,7 +405,7 @@ func (s *AdminPuskesmasService) CurrentPasswordUpdate(ctx context.Context, reque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminPuskesmas := new(entity.AdminPuskesmas)
@@ -412,7 +416,7 @@ func (s *AdminPuskesmasService) CurrentPasswordUpdate(ctx context.Context, reque
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminPuskesmas.Password), []byte(request.CurrentPassword)); err != nil {
 		slog.Error(err.Error())
-		return fiber.NewError(fiber.StatusUnauthorized, "Password saat ini salah")
+		return model.NewAppError(constant.KodeErrorPasswordSaatIniSalah)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
@@ -421,6 +425,7 @@ func (s *AdminPuskesmasService) CurrentPasswordUpdate(ctx context.Context, reque
 		return fiber.ErrInternalServerError
 	}
 	adminPuskesmas.Password = string(password)
+	adminPuskesmas.VersiToken++
 
 	if err := s.AdminPuskesmasRepository.Update(tx, adminPuskesmas); err != nil {
 		slog.Error(err.Error())
@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,79 +13,94 @@ import (
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
-	"time"
 )
 
 type AdminSuperService struct {
-	DB                   *gorm.DB
-	AdminSuperRepository *repository.AdminSuperRepository
-	RecaptchaAdapter     *adapter.Captcha
-	Validator            *validator.Validate
-	Config               *viper.Viper
+	DB                           *gorm.DB
+	AdminSuperRepository         *repository.AdminSuperRepository
+	RecaptchaAdapter             *adapter.Captcha
+	SesiRepository               *repository.SesiRepository
+	RefreshTokenRepository       *repository.RefreshTokenRepository
+	TokenAdapter                 *adapter.TokenAdapter
+	PercobaanLoginRepository     *repository.PercobaanLoginRepository
+	DuaFaktorRepository          *repository.DuaFaktorRepository
+	TantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository
+	Validator                    *validator.Validate
+	Config                       *viper.Viper
 }
 
 func NewAdminSuperService(db *gorm.DB,
 	adminSuperRepository *repository.AdminSuperRepository,
 	captchaAdapter *adapter.Captcha,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	percobaanLoginRepository *repository.PercobaanLoginRepository,
+	duaFaktorRepository *repository.DuaFaktorRepository,
+	tantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository,
 	validator *validator.Validate,
 	config *viper.Viper) *AdminSuperService {
-	return &AdminSuperService{db, adminSuperRepository, captchaAdapter, validator, config}
+	return &AdminSuperService{db, adminSuperRepository, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, duaFaktorRepository, tantanganDuaFaktorRepository, validator, config}
 }
 
-func (s *AdminSuperService) Login(ctx context.Context, request *model.AdminSuperLoginRequest) (*model.AdminSuperResponse, error) {
+func (s *AdminSuperService) Login(ctx context.Context, request *model.AdminSuperLoginRequest) (*model.TokenResponse, error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
-	captchaRequest := &model.CaptchaRequest{
-		TokenCaptcha: request.TokenCaptcha,
-		Secret:       s.Config.GetString("captcha.secret"),
+	if err := cekPercobaanLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, s.Config, constant.RoleAdminSuper, request.Username, request.Ip); err != nil {
+		return nil, err
 	}
 
-	ok, err := s.RecaptchaAdapter.Verify(captchaRequest)
+	ok, err := s.RecaptchaAdapter.Verify(constant.RoleAdminSuper, request.TokenCaptcha)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 	if !ok {
-		return nil, fiber.ErrBadRequest
+		return nil, model.NewAppError(constant.KodeErrorCaptchaTidakValid)
 	}
 
 	adminSuper := new(entity.AdminSuper)
 	if err := s.AdminSuperRepository.FindByUsername(tx, adminSuper, request.Username); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminSuper, request.Username, request.Ip)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminSuper.Password), []byte(request.Password)); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RoleAdminSuper, request.Username, request.Ip)
 	}
 
-	key := s.Config.GetString("jwt.secret")
-	exp := s.Config.GetInt("jwt.exp")
-	claims := jwt.MapClaims{
-		"sub":  adminSuper.ID,
-		"role": constant.RoleAdminSuper,
-		"exp":  time.Now().Add(time.Duration(exp) * time.Hour).Unix(),
-	}
-
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+	auth := &model.Auth{ID: adminSuper.ID, Role: constant.RoleAdminSuper, VersiToken: adminSuper.VersiToken}
+	token, err := tantanganDuaFaktor(tx, s.DuaFaktorRepository, s.TantanganDuaFaktorRepository, s.TokenAdapter, s.Config, auth, adminSuper.Username)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
+	// login baru dianggap berhasil setelah faktor kedua lolos, lihat DuaFaktorService.Login
+	if token == nil {
+		token, err = buatSesi(tx, s.SesiRepository, s.RefreshTokenRepository, s.TokenAdapter, auth)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := catatPercobaanLogin(tx, s.PercobaanLoginRepository, constant.RoleAdminSuper, request.Username, request.Ip, constant.StatusPercobaanLoginBerhasil); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &model.AdminSuperResponse{Token: token}, nil
+	return token, nil
 }
 
 func (s *AdminSuperService) PasswordUpdate(ctx context.Context, request *model.AdminSuperPasswordUpdateRequest) error {
@@ -95,7 +109,7 @@ func (s *AdminSuperService) PasswordUpdate(ctx context.Context, request *model.A
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	adminSuper := new(entity.AdminSuper)
@@ -106,7 +120,7 @@ func (s *AdminSuperService) PasswordUpdate(ctx context.Context, request *model.A
 
 	if err := bcrypt.CompareHashAndPassword([]byte(adminSuper.Password), []byte(request.CurrentPassword)); err != nil {
 		slog.Error(err.Error())
-		return fiber.NewError(fiber.StatusUnauthorized, "Password saat ini salah")
+		return model.NewAppError(constant.KodeErrorPasswordSaatIniSalah)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
@@ -115,6 +129,8 @@ func (s *AdminSuperService) PasswordUpdate(ctx context.Context, request *model.A
 		return fiber.ErrInternalServerError
 	}
 	adminSuper.Password = string(password)
+	// token yang sudah terbit tidak berlaku lagi setelah password diganti
+	adminSuper.VersiToken++
 
 	if err := s.AdminSuperRepository.Update(tx, adminSuper); err != nil {
 		slog.Error(err.Error())
@@ -128,26 +144,3 @@ func (s *AdminSuperService) PasswordUpdate(ctx context.Context, request *model.A
 
 	return nil
 }
-
-func (s *AdminSuperService) Verify(ctx context.Context, request *model.AdminSuperVerifyRequest) error {
-	tx := s.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
-
-	if err := s.Validator.Struct(request); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrBadRequest
-	}
-
-	adminSuper := new(entity.AdminSuper)
-	if err := s.AdminSuperRepository.FindById(tx, adminSuper, request.ID); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrNotFound
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrInternalServerError
-	}
-
-	return nil
-}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"time"
+)
+
+// antrean kirim dengan percobaan ulang yang dipakai notifikasi, outbox event, dan pengiriman webhook
+type antreanKirimRepository[T any] interface {
+	FindAllSiapKirimAndLockForUpdate(db *gorm.DB, item *[]T, sekarang int64, batas int) error
+	Update(db *gorm.DB, item *T) error
+}
+
+// item yang siap dikirim diklaim dulu dengan memajukan kirim_berikutnya lalu dikirim di luar transaksi, sehingga
+// instance lain tidak mengirim ulang dan item yang klaimnya terputus dicoba lagi lima menit kemudian
+func klaimLaluKirim[T any](ctx context.Context, db *gorm.DB, repository antreanKirimRepository[T], batas int, kirimBerikutnya func(*T) *int64, kirim func(context.Context, *T) error) error {
+	tx := db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	sekarang := time.Now().Unix()
+	item := new([]T)
+	if err := repository.FindAllSiapKirimAndLockForUpdate(tx, item, sekarang, batas); err != nil {
+		return err
+	}
+	for i := range *item {
+		*kirimBerikutnya(&(*item)[i]) = sekarang + 5*60
+		if err := repository.Update(tx, &(*item)[i]); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	for i := range *item {
+		if err := kirim(ctx, &(*item)[i]); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+	return nil
+}
+
+// batas percobaan dan jeda awal dalam detik bila <prefix>.maks_percobaan dan <prefix>.jeda tidak diatur
+var percobaanBawaan = map[string]struct{ maks, jeda int }{
+	"notifikasi": {5, 60},
+	"event":      {10, 30},
+	"webhook":    {8, 30},
+}
+
+// kolom percobaan milik satu item antrean kirim
+type percobaanKirim struct {
+	Status          *string
+	Percobaan       *int32
+	Error           **string
+	KirimBerikutnya *int64
+	Terkirim        **int64
+	StatusTerkirim  string
+	StatusGagal     string
+}
+
+// mencatat hasil satu percobaan kirim. item yang gagal dihentikan bila gagalAkhir atau sudah <prefix>.maks_percobaan
+// kali, selain itu dijadwalkan ulang
+func (p *percobaanKirim) catat(config *viper.Viper, prefix string, err error, gagalAkhir bool) {
+	sekarang := time.Now().Unix()
+	*p.Percobaan++
+	if err == nil {
+		*p.Status = p.StatusTerkirim
+		*p.Terkirim = &sekarang
+		*p.Error = nil
+		return
+	}
+
+	pesan := err.Error()
+	*p.Error = &pesan
+	if gagalAkhir || *p.Percobaan >= int32(konfigurasiInt(config, prefix+".maks_percobaan", percobaanBawaan[prefix].maks)) {
+		*p.Status = p.StatusGagal
+		return
+	}
+	*p.KirimBerikutnya = sekarang + jedaPercobaanUlang(config, prefix, *p.Percobaan)
+}
+
+// jeda percobaan ulang berlipat dua dari <prefix>.jeda, paling lama satu jam
+func jedaPercobaanUlang(config *viper.Viper, prefix string, percobaan int32) int64 {
+	jeda := int64(konfigurasiInt(config, prefix+".jeda", percobaanBawaan[prefix].jeda)) << min(percobaan-1, 10)
+	return min(jeda, 60*60)
+}
@@ -47,19 +47,19 @@ func NewArtikelService(
 	}
 }
 
-func (s *ArtikelService) Search(ctx context.Context, request *model.ArtikelSearchRequest) (*[]model.ArtikelResponse, error) {
+func (s *ArtikelService) Search(ctx context.Context, request *model.ArtikelSearchRequest) (*model.PageResponse[model.ArtikelResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	artikel := new([]entity.Artikel)
-	if err := s.ArtikelRepository.Search(tx, artikel, request.IdAdminPuskesmas); err != nil {
-		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
+	result, err := s.ArtikelRepository.Search(tx, artikel, request.IdAdminPuskesmas, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.ArtikelResponse
@@ -85,7 +85,7 @@ func (s *ArtikelService) Search(ctx context.Context, request *model.ArtikelSearc
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *ArtikelService) Get(ctx context.Context, request *model.ArtikelGetRequest) (*model.ArtikelResponse, error) {
@@ -94,7 +94,7 @@ func (s *ArtikelService) Get(ctx context.Context, request *model.ArtikelGetReque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	artikel := new(entity.Artikel)
@@ -133,7 +133,7 @@ func (s *ArtikelService) Create(ctx context.Context, request *model.ArtikelCreat
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	if err := s.AdminPuskesmasRepository.FindById(tx, &entity.AdminPuskesmas{}, request.IdAdminPuskesmas); err != nil {
@@ -147,7 +147,7 @@ func (s *ArtikelService) Create(ctx context.Context, request *model.ArtikelCreat
 	if file != nil {
 		if err := s.Validator.Struct(file); err != nil {
 			slog.Error(err.Error())
-			return fiber.ErrBadRequest
+			return validasiError(err)
 		}
 		if file.FileHeader != nil && file.FileHeader.Filename != "" {
 			storedFile, err = s.FileAdapter.StoreImage(s.Config.GetString("dir.pict"), file)
@@ -241,7 +241,7 @@ func (s *ArtikelService) Update(ctx context.Context, request *model.ArtikelUpdat
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	if err := s.AdminPuskesmasRepository.FindById(tx, &entity.AdminPuskesmas{}, request.IdAdminPuskesmas); err != nil {
@@ -268,7 +268,7 @@ func (s *ArtikelService) Update(ctx context.Context, request *model.ArtikelUpdat
 	if file != nil {
 		if err := s.Validator.Struct(file); err != nil {
 			slog.Error(err.Error())
-			return fiber.ErrBadRequest
+			return validasiError(err)
 		}
 		if file.FileHeader != nil && file.FileHeader.Filename != "" {
 			storedFile, err = s.FileAdapter.StoreImage(s.Config.GetString("dir.pict"), file)
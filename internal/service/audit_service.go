@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"log/slog"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type AuditService struct {
+	DB              *gorm.DB
+	AuditRepository *repository.AuditRepository
+	Validator       *validator.Validate
+}
+
+func NewAuditService(db *gorm.DB, auditRepository *repository.AuditRepository, validator *validator.Validate) *AuditService {
+	return &AuditService{db, auditRepository, validator}
+}
+
+func (s *AuditService) List(ctx context.Context, request *model.AuditListRequest) (*model.PageResponse[model.AuditResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	audit := new([]entity.Audit)
+	result, err := s.AuditRepository.Search(tx, audit, request.Entitas, request.IdEntitas, request.Aksi, request.RoleAktor, request.IdAktor, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.AuditResponse
+	for _, a := range *audit {
+		response = append(response, model.AuditResponse{
+			ID:        a.ID,
+			IdAktor:   a.IdAktor,
+			RoleAktor: a.RoleAktor,
+			Aksi:      a.Aksi,
+			Entitas:   a.Entitas,
+			IdEntitas: a.IdEntitas,
+			Perubahan: json.RawMessage(a.Perubahan),
+			Waktu:     a.Waktu,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+var auditSchema sync.Map
+
+// dicatat di transaksi yang sama dengan perubahannya. sebelum nil untuk data baru dan sesudah nil untuk data yang dihapus,
+// nama entitas dan id diambil dari schema gorm, perubahan hanya berisi kolom yang nilainya berbeda.
+// aktor nil berarti perubahan dilakukan oleh sistem
+func catatAudit(tx *gorm.DB, auditRepository *repository.AuditRepository, aktor *model.Auth, aksi string, sebelum any, sesudah any) error {
+	nilai := sesudah
+	if nilai == nil {
+		nilai = sebelum
+	}
+	sch, err := schema.Parse(nilai, &auditSchema, tx.NamingStrategy)
+	if err != nil {
+		return err
+	}
+
+	ctx := tx.Statement.Context
+	kolomSebelum := kolomAudit(ctx, tx.NamingStrategy, sch, sebelum)
+	kolomSesudah := kolomAudit(ctx, tx.NamingStrategy, sch, sesudah)
+	perubahan := make(map[string]model.PerubahanAudit)
+	for kolom := range kolomSebelum {
+		if _, ok := kolomSesudah[kolom]; !ok {
+			perubahan[kolom] = model.PerubahanAudit{Sebelum: kolomSebelum[kolom]}
+		}
+	}
+	for kolom, baru := range kolomSesudah {
+		lama, ok := kolomSebelum[kolom]
+		if ok && samaAudit(lama, baru) {
+			continue
+		}
+		perubahan[kolom] = model.PerubahanAudit{Sebelum: lama, Sesudah: baru}
+	}
+	perubahanJson, err := json.Marshal(perubahan)
+	if err != nil {
+		return err
+	}
+
+	id, _ := sch.PrioritizedPrimaryField.ValueOf(ctx, reflect.ValueOf(nilai))
+	audit := &entity.Audit{
+		Aksi:      aksi,
+		Entitas:   sch.Table,
+		IdEntitas: reflect.ValueOf(id).Convert(reflect.TypeOf(int32(0))).Interface().(int32),
+		Perubahan: string(perubahanJson),
+		Waktu:     time.Now().Unix(),
+	}
+	if aktor != nil {
+		audit.IdAktor = &aktor.ID
+		audit.RoleAktor = &aktor.Role
+	}
+	return auditRepository.Create(tx, audit)
+}
+
+// kolom tabel beserta isi relasi has many yang ikut dimuat, misalnya item pengambilan obat
+func kolomAudit(ctx context.Context, namer schema.Namer, sch *schema.Schema, nilai any) map[string]any {
+	if nilai == nil {
+		return nil
+	}
+	v := reflect.ValueOf(nilai)
+	kolom := make(map[string]any)
+	for _, dbName := range sch.DBNames {
+		kolom[dbName], _ = sch.FieldsByDBName[dbName].ValueOf(ctx, v)
+	}
+	for _, relasi := range sch.Relationships.HasMany {
+		isi, _ := relasi.Field.ValueOf(ctx, v)
+		daftar := reflect.ValueOf(isi)
+		if daftar.Len() == 0 {
+			continue
+		}
+		var item []map[string]any
+		for i := range daftar.Len() {
+			elemen := make(map[string]any)
+			for _, dbName := range relasi.FieldSchema.DBNames {
+				field := relasi.FieldSchema.FieldsByDBName[dbName]
+				if field.PrimaryKey || relasiForeignKey(relasi, field) {
+					continue
+				}
+				elemen[dbName], _ = field.ValueOf(ctx, daftar.Index(i))
+			}
+			item = append(item, elemen)
+		}
+		kolom[namer.ColumnName("", relasi.Name)] = item
+	}
+	return kolom
+}
+
+func relasiForeignKey(relasi *schema.Relationship, field *schema.Field) bool {
+	for _, ref := range relasi.References {
+		if ref.ForeignKey == field {
+			return true
+		}
+	}
+	return false
+}
+
+func samaAudit(a any, b any) bool {
+	aJson, errA := json.Marshal(a)
+	bJson, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJson) == string(bJson)
+}
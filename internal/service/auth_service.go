@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type AuthService struct {
+	DB                       *gorm.DB
+	SesiRepository           *repository.SesiRepository
+	RefreshTokenRepository   *repository.RefreshTokenRepository
+	AdminSuperRepository     *repository.AdminSuperRepository
+	AdminPuskesmasRepository *repository.AdminPuskesmasRepository
+	AdminApotekRepository    *repository.AdminApotekRepository
+	PenggunaRepository       *repository.PenggunaRepository
+	TokenAdapter             *adapter.TokenAdapter
+	Validator                *validator.Validate
+}
+
+func NewAuthService(
+	db *gorm.DB,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	adminSuperRepository *repository.AdminSuperRepository,
+	adminPuskesmasRepository *repository.AdminPuskesmasRepository,
+	adminApotekRepository *repository.AdminApotekRepository,
+	penggunaRepository *repository.PenggunaRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	validator *validator.Validate,
+) *AuthService {
+	return &AuthService{db, sesiRepository, refreshTokenRepository, adminSuperRepository, adminPuskesmasRepository, adminApotekRepository, penggunaRepository, tokenAdapter, validator}
+}
+
+func (s *AuthService) Verify(ctx context.Context, request *model.AuthVerifyRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrUnauthorized
+	}
+
+	versiToken, err := s.versiToken(tx, request.Role, request.ID)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrUnauthorized
+	}
+	if versiToken != request.VersiToken {
+		return fiber.ErrUnauthorized
+	}
+
+	sesi := new(entity.Sesi)
+	if err := s.SesiRepository.FindAktifByIdAndIdAkunAndRole(tx, sesi, request.IdSesi, request.ID, request.Role, time.Now().Unix()); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrUnauthorized
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *AuthService) Refresh(ctx context.Context, request *model.AuthRefreshRequest) (*model.TokenResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	refreshToken := new(entity.RefreshToken)
+	if err := s.RefreshTokenRepository.FindByHashAndLockForUpdate(tx, refreshToken, s.TokenAdapter.Hash(request.RefreshToken)); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrUnauthorized
+	}
+
+	sesi := new(entity.Sesi)
+	if err := s.SesiRepository.FindByIdAndLockForUpdate(tx, sesi, refreshToken.IdSesi); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrUnauthorized
+	}
+
+	sekarang := time.Now().Unix()
+	if sesi.Dicabut != nil || sesi.Kedaluwarsa <= sekarang {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	// refresh token yang sudah dirotasi dipakai lagi, anggap bocor dan cabut seluruh sesinya
+	if refreshToken.Digunakan != nil {
+		slog.Warn("Refresh token reused", "sesi", sesi.ID, "role", sesi.Role, "id", sesi.IdAkun)
+		sesi.Dicabut = &sekarang
+		if err := s.SesiRepository.Update(tx, sesi); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := tx.Commit().Error; err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		return nil, fiber.ErrUnauthorized
+	}
+
+	versiToken, err := s.versiToken(tx, sesi.Role, sesi.IdAkun)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrUnauthorized
+	}
+	if versiToken != sesi.VersiToken {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	refreshToken.Digunakan = &sekarang
+	if err := s.RefreshTokenRepository.Update(tx, refreshToken); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	token, hash, err := s.TokenAdapter.Acak()
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	if err := s.RefreshTokenRepository.Create(tx, &entity.RefreshToken{IdSesi: sesi.ID, Hash: hash, Dibuat: sekarang}); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	accessToken, err := s.TokenAdapter.Access(&model.Auth{ID: sesi.IdAkun, Role: sesi.Role, VersiToken: sesi.VersiToken, IdSesi: sesi.ID})
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.TokenResponse{Token: accessToken, RefreshToken: token}, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, request *model.AuthLogoutRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	sesi := new(entity.Sesi)
+	if err := s.SesiRepository.FindByIdAndLockForUpdate(tx, sesi, request.Aktor.IdSesi); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+	if sesi.IdAkun != request.Aktor.ID || sesi.Role != request.Aktor.Role {
+		return fiber.ErrNotFound
+	}
+
+	if sesi.Dicabut == nil {
+		sekarang := time.Now().Unix()
+		sesi.Dicabut = &sekarang
+		if err := s.SesiRepository.Update(tx, sesi); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *AuthService) LogoutSemua(ctx context.Context, request *model.AuthLogoutRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	// access token yang masih berlaku ikut ditolak karena versi tokennya tertinggal
+	var err error
+	switch request.Aktor.Role {
+	case constant.RoleAdminSuper:
+		err = s.AdminSuperRepository.IncrementVersiTokenById(tx, request.Aktor.ID)
+	case constant.RoleAdminPuskesmas:
+		err = s.AdminPuskesmasRepository.IncrementVersiTokenById(tx, request.Aktor.ID)
+	case constant.RoleAdminApotek:
+		err = s.AdminApotekRepository.IncrementVersiTokenById(tx, request.Aktor.ID)
+	case constant.RolePengguna:
+		err = s.PenggunaRepository.IncrementVersiTokenById(tx, request.Aktor.ID)
+	default:
+		return fiber.ErrBadRequest
+	}
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := s.SesiRepository.RevokeByIdAkunAndRole(tx, request.Aktor.ID, request.Aktor.Role, time.Now().Unix()); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *AuthService) versiToken(tx *gorm.DB, role string, id int32) (int32, error) {
+	switch role {
+	case constant.RoleAdminSuper:
+		adminSuper := new(entity.AdminSuper)
+		if err := s.AdminSuperRepository.FindById(tx, adminSuper, id); err != nil {
+			return 0, err
+		}
+		return adminSuper.VersiToken, nil
+	case constant.RoleAdminPuskesmas:
+		adminPuskesmas := new(entity.AdminPuskesmas)
+		if err := s.AdminPuskesmasRepository.FindById(tx, adminPuskesmas, id); err != nil {
+			return 0, err
+		}
+		return adminPuskesmas.VersiToken, nil
+	case constant.RoleAdminApotek:
+		adminApotek := new(entity.AdminApotek)
+		if err := s.AdminApotekRepository.FindById(tx, adminApotek, id); err != nil {
+			return 0, err
+		}
+		return adminApotek.VersiToken, nil
+	case constant.RolePengguna:
+		pengguna := new(entity.Pengguna)
+		if err := s.PenggunaRepository.FindById(tx, pengguna, id); err != nil {
+			return 0, err
+		}
+		return pengguna.VersiToken, nil
+	}
+	return 0, errors.New("role tidak dikenal")
+}
+
+// setiap login membuka sesi baru dengan refresh token pertamanya
+func buatSesi(tx *gorm.DB, sesiRepository *repository.SesiRepository, refreshTokenRepository *repository.RefreshTokenRepository, tokenAdapter *adapter.TokenAdapter, auth *model.Auth) (*model.TokenResponse, error) {
+	idSesi, err := tokenAdapter.IdSesi()
+	if err != nil {
+		return nil, err
+	}
+	sekarang := time.Now()
+	sesi := &entity.Sesi{
+		ID:          idSesi,
+		IdAkun:      auth.ID,
+		Role:        auth.Role,
+		VersiToken:  auth.VersiToken,
+		Dibuat:      sekarang.Unix(),
+		Kedaluwarsa: sekarang.Add(tokenAdapter.RefreshExp()).Unix(),
+	}
+	if err := sesiRepository.Create(tx, sesi); err != nil {
+		return nil, err
+	}
+
+	token, hash, err := tokenAdapter.Acak()
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshTokenRepository.Create(tx, &entity.RefreshToken{IdSesi: sesi.ID, Hash: hash, Dibuat: sesi.Dibuat}); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := tokenAdapter.Access(&model.Auth{ID: auth.ID, Role: auth.Role, VersiToken: auth.VersiToken, IdSesi: sesi.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenResponse{Token: accessToken, RefreshToken: token}, nil
+}
@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DuaFaktorService struct {
+	DB                           *gorm.DB
+	DuaFaktorRepository          *repository.DuaFaktorRepository
+	KodeCadanganRepository       *repository.KodeCadanganRepository
+	TantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository
+	SesiRepository               *repository.SesiRepository
+	RefreshTokenRepository       *repository.RefreshTokenRepository
+	AdminSuperRepository         *repository.AdminSuperRepository
+	AdminPuskesmasRepository     *repository.AdminPuskesmasRepository
+	AdminApotekRepository        *repository.AdminApotekRepository
+	PercobaanLoginRepository     *repository.PercobaanLoginRepository
+	TokenAdapter                 *adapter.TokenAdapter
+	TotpAdapter                  *adapter.TotpAdapter
+	QrCodeAdapter                *adapter.QrCodeAdapter
+	Validator                    *validator.Validate
+	Config                       *viper.Viper
+}
+
+func NewDuaFaktorService(db *gorm.DB,
+	duaFaktorRepository *repository.DuaFaktorRepository,
+	kodeCadanganRepository *repository.KodeCadanganRepository,
+	tantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	adminSuperRepository *repository.AdminSuperRepository,
+	adminPuskesmasRepository *repository.AdminPuskesmasRepository,
+	adminApotekRepository *repository.AdminApotekRepository,
+	percobaanLoginRepository *repository.PercobaanLoginRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	totpAdapter *adapter.TotpAdapter,
+	qrCodeAdapter *adapter.QrCodeAdapter,
+	validator *validator.Validate,
+	config *viper.Viper) *DuaFaktorService {
+	return &DuaFaktorService{db, duaFaktorRepository, kodeCadanganRepository, tantanganDuaFaktorRepository, sesiRepository, refreshTokenRepository, adminSuperRepository, adminPuskesmasRepository, adminApotekRepository, percobaanLoginRepository, tokenAdapter, totpAdapter, qrCodeAdapter, validator, config}
+}
+
+var errKodeDuaFaktorSalah = model.NewAppError(constant.KodeErrorDuaFaktorSalah)
+
+func (s *DuaFaktorService) Get(ctx context.Context, request *model.DuaFaktorGetRequest) (*model.DuaFaktorResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	response := &model.DuaFaktorResponse{Wajib: wajibDuaFaktor(s.Config, request.Aktor.Role)}
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRole(tx, duaFaktor, request.Aktor.ID, request.Aktor.Role); err == nil && duaFaktor.Aktif != nil {
+		sisa, err := s.KodeCadanganRepository.CountSisaByIdDuaFaktor(tx, duaFaktor.ID)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		response.Aktif = true
+		response.SisaKodeCadangan = sisa
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+func (s *DuaFaktorService) Enrol(ctx context.Context, request *model.DuaFaktorEnrolRequest) (*model.DuaFaktorEnrolResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	username, err := s.username(tx, request.Aktor.Role, request.Aktor.ID)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	response, err := s.enrol(tx, request.Aktor.ID, request.Aktor.Role, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+func (s *DuaFaktorService) Aktifkan(ctx context.Context, request *model.DuaFaktorKodeRequest) (*model.DuaFaktorKodeCadanganResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, request.Aktor.ID, request.Aktor.Role); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+	if duaFaktor.Aktif != nil {
+		return nil, model.NewAppError(constant.KodeErrorDuaFaktorAktif)
+	}
+
+	kodeCadangan, err := s.aktifkan(tx, duaFaktor, request.Kode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.DuaFaktorKodeCadanganResponse{KodeCadangan: kodeCadangan}, nil
+}
+
+func (s *DuaFaktorService) Nonaktifkan(ctx context.Context, request *model.DuaFaktorKodeRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	if wajibDuaFaktor(s.Config, request.Aktor.Role) {
+		return model.NewAppError(constant.KodeErrorDuaFaktorWajib)
+	}
+
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, request.Aktor.ID, request.Aktor.Role); err != nil || duaFaktor.Aktif == nil {
+		return fiber.ErrNotFound
+	}
+
+	ok, err := s.verifikasiKode(tx, duaFaktor, request.Kode)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if !ok {
+		return errKodeDuaFaktorSalah
+	}
+
+	if err := s.DuaFaktorRepository.Delete(tx, duaFaktor); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *DuaFaktorService) KodeCadangan(ctx context.Context, request *model.DuaFaktorKodeRequest) (*model.DuaFaktorKodeCadanganResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, request.Aktor.ID, request.Aktor.Role); err != nil || duaFaktor.Aktif == nil {
+		return nil, fiber.ErrNotFound
+	}
+
+	// kode cadangan baru hanya bisa dibuat dengan kode dari authenticator
+	langkah, ok := s.TotpAdapter.Validate(duaFaktor.Secret, request.Kode, duaFaktor.LangkahTerakhir)
+	if !ok {
+		return nil, errKodeDuaFaktorSalah
+	}
+	duaFaktor.LangkahTerakhir = langkah
+	if err := s.DuaFaktorRepository.Update(tx, duaFaktor); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	kodeCadangan, err := s.buatKodeCadangan(tx, duaFaktor)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.DuaFaktorKodeCadanganResponse{KodeCadangan: kodeCadangan}, nil
+}
+
+// untuk admin yang kehilangan authenticator sekaligus kode cadangannya
+func (s *DuaFaktorService) Reset(ctx context.Context, request *model.DuaFaktorResetRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, request.IdAkun, request.Role); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	if err := s.DuaFaktorRepository.Delete(tx, duaFaktor); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// enrol saat login, untuk role yang mewajibkan 2fa tapi akunnya belum memasang authenticator
+func (s *DuaFaktorService) LoginEnrol(ctx context.Context, request *model.DuaFaktorLoginEnrolRequest) (*model.DuaFaktorEnrolResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	tantangan, err := s.tantangan(tx, request.TokenDuaFaktor)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.enrol(tx, tantangan.IdAkun, tantangan.Role, tantangan.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+func (s *DuaFaktorService) Login(ctx context.Context, request *model.DuaFaktorLoginRequest) (*model.TokenResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	tantangan, err := s.tantangan(tx, request.TokenDuaFaktor)
+	if err != nil {
+		return nil, err
+	}
+
+	// kunci akun juga berlaku pada langkah kedua karena gagal 2fa ikut dihitung
+	if err := cekPercobaanLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, s.Config, tantangan.Role, tantangan.Username, request.Ip); err != nil {
+		return nil, err
+	}
+
+	duaFaktor := new(entity.DuaFaktor)
+	if err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, tantangan.IdAkun, tantangan.Role); err != nil {
+		slog.Error(err.Error())
+		return nil, model.NewAppError(constant.KodeErrorDuaFaktorBelumEnrol)
+	}
+
+	// kode pertama dari authenticator yang baru dienrol sekaligus mengaktifkan 2fa
+	var kodeCadangan []string
+	var ok bool
+	if duaFaktor.Aktif == nil {
+		kodeCadangan, err = s.aktifkan(tx, duaFaktor, request.Kode)
+		if err != nil && err != errKodeDuaFaktorSalah {
+			return nil, err
+		}
+		ok = err == nil
+	} else {
+		ok, err = s.verifikasiKode(tx, duaFaktor, request.Kode)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	sekarang := time.Now().Unix()
+	if !ok {
+		slog.Warn("2FA code rejected", "role", tantangan.Role, "id", tantangan.IdAkun)
+		tantangan.Percobaan++
+		if err := s.TantanganDuaFaktorRepository.Update(tx, tantangan); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := tx.Commit().Error; err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		catatGagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, tantangan.Role, tantangan.Username, request.Ip)
+		return nil, errKodeDuaFaktorSalah
+	}
+
+	tantangan.Digunakan = &sekarang
+	if err := s.TantanganDuaFaktorRepository.Update(tx, tantangan); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	token, err := buatSesi(tx, s.SesiRepository, s.RefreshTokenRepository, s.TokenAdapter, &model.Auth{ID: tantangan.IdAkun, Role: tantangan.Role, VersiToken: tantangan.VersiToken})
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	token.KodeCadangan = kodeCadangan
+
+	if err := catatPercobaanLogin(tx, s.PercobaanLoginRepository, tantangan.Role, tantangan.Username, request.Ip, constant.StatusPercobaanLoginBerhasil); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return token, nil
+}
+
+func (s *DuaFaktorService) tantangan(tx *gorm.DB, tokenDuaFaktor string) (*entity.TantanganDuaFaktor, error) {
+	tantangan := new(entity.TantanganDuaFaktor)
+	if err := s.TantanganDuaFaktorRepository.FindByHashAndLockForUpdate(tx, tantangan, s.TokenAdapter.Hash(tokenDuaFaktor)); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrUnauthorized
+	}
+	if tantangan.Digunakan != nil || tantangan.Kedaluwarsa <= time.Now().Unix() ||
+		tantangan.Percobaan >= int32(konfigurasiInt(s.Config, "dua_faktor.maks_percobaan", 5)) {
+		return nil, fiber.ErrUnauthorized
+	}
+	return tantangan, nil
+}
+
+func (s *DuaFaktorService) enrol(tx *gorm.DB, idAkun int32, role string, username string) (*model.DuaFaktorEnrolResponse, error) {
+	duaFaktor := new(entity.DuaFaktor)
+	err := s.DuaFaktorRepository.FindByIdAkunAndRoleAndLockForUpdate(tx, duaFaktor, idAkun, role)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	if err == nil && duaFaktor.Aktif != nil {
+		return nil, model.NewAppError(constant.KodeErrorDuaFaktorAktif)
+	}
+
+	// enrol ulang sebelum aktif mengganti secret yang lama
+	secret, uri, err := s.TotpAdapter.Generate(username)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	duaFaktor.IdAkun = idAkun
+	duaFaktor.Role = role
+	duaFaktor.Secret = secret
+	duaFaktor.LangkahTerakhir = 0
+	duaFaktor.Dibuat = time.Now().Unix()
+	if err := s.DuaFaktorRepository.Update(tx, duaFaktor); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	qrCode, err := s.QrCodeAdapter.PNG(uri, 256)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.DuaFaktorEnrolResponse{
+		Secret:     secret,
+		OtpauthUri: uri,
+		QrCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+func (s *DuaFaktorService) aktifkan(tx *gorm.DB, duaFaktor *entity.DuaFaktor, kode string) ([]string, error) {
+	langkah, ok := s.TotpAdapter.Validate(duaFaktor.Secret, kode, duaFaktor.LangkahTerakhir)
+	if !ok {
+		return nil, errKodeDuaFaktorSalah
+	}
+	sekarang := time.Now().Unix()
+	duaFaktor.Aktif = &sekarang
+	duaFaktor.LangkahTerakhir = langkah
+	if err := s.DuaFaktorRepository.Update(tx, duaFaktor); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	kodeCadangan, err := s.buatKodeCadangan(tx, duaFaktor)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	return kodeCadangan, nil
+}
+
+// kode dari authenticator atau salah satu kode cadangan yang belum dipakai
+func (s *DuaFaktorService) verifikasiKode(tx *gorm.DB, duaFaktor *entity.DuaFaktor, kode string) (bool, error) {
+	if langkah, ok := s.TotpAdapter.Validate(duaFaktor.Secret, kode, duaFaktor.LangkahTerakhir); ok {
+		duaFaktor.LangkahTerakhir = langkah
+		return true, s.DuaFaktorRepository.Update(tx, duaFaktor)
+	}
+
+	kodeCadangan := new(entity.KodeCadangan)
+	hash := s.TokenAdapter.Hash(strings.ToLower(strings.TrimSpace(kode)))
+	if err := s.KodeCadanganRepository.FindSisaByIdDuaFaktorAndHashAndLockForUpdate(tx, kodeCadangan, duaFaktor.ID, hash); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	sekarang := time.Now().Unix()
+	kodeCadangan.Digunakan = &sekarang
+	return true, s.KodeCadanganRepository.Update(tx, kodeCadangan)
+}
+
+func (s *DuaFaktorService) buatKodeCadangan(tx *gorm.DB, duaFaktor *entity.DuaFaktor) ([]string, error) {
+	if err := s.KodeCadanganRepository.DeleteByIdDuaFaktor(tx, duaFaktor.ID); err != nil {
+		return nil, err
+	}
+	kode, err := s.TotpAdapter.KodeCadangan(10)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range kode {
+		if err := s.KodeCadanganRepository.Create(tx, &entity.KodeCadangan{IdDuaFaktor: duaFaktor.ID, Hash: s.TokenAdapter.Hash(k)}); err != nil {
+			return nil, err
+		}
+	}
+	return kode, nil
+}
+
+func (s *DuaFaktorService) username(tx *gorm.DB, role string, id int32) (string, error) {
+	switch role {
+	case constant.RoleAdminSuper:
+		adminSuper := new(entity.AdminSuper)
+		if err := s.AdminSuperRepository.FindById(tx, adminSuper, id); err != nil {
+			return "", err
+		}
+		return adminSuper.Username, nil
+	case constant.RoleAdminPuskesmas:
+		adminPuskesmas := new(entity.AdminPuskesmas)
+		if err := s.AdminPuskesmasRepository.FindById(tx, adminPuskesmas, id); err != nil {
+			return "", err
+		}
+		return adminPuskesmas.Username, nil
+	case constant.RoleAdminApotek:
+		adminApotek := new(entity.AdminApotek)
+		if err := s.AdminApotekRepository.FindById(tx, adminApotek, id); err != nil {
+			return "", err
+		}
+		return adminApotek.Username, nil
+	}
+	return "", errors.New("role tidak memakai 2fa")
+}
+
+// role yang tercantum di dua_faktor.wajib tidak bisa login tanpa 2fa
+func wajibDuaFaktor(config *viper.Viper, role string) bool {
+	return slices.Contains(config.GetStringSlice("dua_faktor.wajib"), role)
+}
+
+// dipanggil setelah password benar. bila akun memakai 2fa (atau rolenya mewajibkan 2fa) sesi belum dibuat,
+// yang dikembalikan token tantangan untuk langkah login kedua. nil berarti login bisa langsung membuat sesi
+func tantanganDuaFaktor(tx *gorm.DB, duaFaktorRepository *repository.DuaFaktorRepository, tantanganDuaFaktorRepository *repository.TantanganDuaFaktorRepository, tokenAdapter *adapter.TokenAdapter, config *viper.Viper, auth *model.Auth, username string) (*model.TokenResponse, error) {
+	duaFaktor := new(entity.DuaFaktor)
+	langkah := constant.DuaFaktorVerifikasi
+	if err := duaFaktorRepository.FindByIdAkunAndRole(tx, duaFaktor, auth.ID, auth.Role); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	} else if err != nil || duaFaktor.Aktif == nil {
+		if !wajibDuaFaktor(config, auth.Role) {
+			return nil, nil
+		}
+		langkah = constant.DuaFaktorEnrol
+	}
+
+	token, hash, err := tokenAdapter.Acak()
+	if err != nil {
+		return nil, err
+	}
+	sekarang := time.Now().Unix()
+	if err := tantanganDuaFaktorRepository.Create(tx, &entity.TantanganDuaFaktor{
+		Hash:        hash,
+		IdAkun:      auth.ID,
+		Role:        auth.Role,
+		Username:    username,
+		VersiToken:  auth.VersiToken,
+		Dibuat:      sekarang,
+		Kedaluwarsa: sekarang + int64(konfigurasiInt(config, "dua_faktor.exp", 5))*60,
+	}); err != nil {
+		return nil, err
+	}
+	return &model.TokenResponse{DuaFaktor: langkah, TokenDuaFaktor: token}, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type EventService struct {
+	DB               *gorm.DB
+	OutboxRepository *repository.OutboxRepository
+	Sinks            []adapter.EventSink
+	Validator        *validator.Validate
+	Config           *viper.Viper
+}
+
+func NewEventService(
+	db *gorm.DB,
+	outboxRepository *repository.OutboxRepository,
+	sinks []adapter.EventSink,
+	validator *validator.Validate,
+	config *viper.Viper,
+) *EventService {
+	return &EventService{db, outboxRepository, sinks, validator, config}
+}
+
+func (s *EventService) List(ctx context.Context, request *model.EventListRequest) (*model.PageResponse[model.EventResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	outbox := new([]entity.Outbox)
+	result, err := s.OutboxRepository.Search(tx, outbox, request.Jenis, request.Agregat, request.IdAgregat, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.EventResponse
+	for _, o := range *outbox {
+		response = append(response, model.EventResponse{
+			ID:              o.ID,
+			IdEvent:         o.IdEvent,
+			Jenis:           o.Jenis,
+			Agregat:         o.Agregat,
+			IdAgregat:       o.IdAgregat,
+			Data:            json.RawMessage(o.Data),
+			Status:          o.Status,
+			Percobaan:       o.Percobaan,
+			Error:           o.Error,
+			Dibuat:          o.Dibuat,
+			KirimBerikutnya: o.KirimBerikutnya,
+			Terkirim:        o.Terkirim,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+func (s *EventService) KirimUlang(ctx context.Context, request *model.EventKirimUlangRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	outbox := new(entity.Outbox)
+	if err := s.OutboxRepository.FindByIdAndLockForUpdate(tx, outbox, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+	if outbox.Status == constant.StatusOutboxTerkirim {
+		return model.NewAppError(constant.KodeErrorEventTerkirim)
+	}
+
+	outbox.Status = constant.StatusOutboxMenunggu
+	outbox.Percobaan = 0
+	outbox.KirimBerikutnya = time.Now().Unix()
+	if err := s.OutboxRepository.Update(tx, outbox); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// memproses outbox setiap event.interval detik sampai ctx selesai
+func (s *EventService) Jalankan(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(konfigurasiInt(s.Config, "event.interval", 5)) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := s.Kirim(ctx); err != nil {
+			slog.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sama seperti antrean notifikasi, event diklaim dulu lalu dikirim di luar transaksi. event baru ditandai terkirim
+// setelah semua sink menerimanya, sehingga setiap sink menerima event paling sedikit satu kali
+func (s *EventService) Kirim(ctx context.Context) error {
+	return klaimLaluKirim(ctx, s.DB, s.OutboxRepository, 100, func(o *entity.Outbox) *int64 { return &o.KirimBerikutnya }, s.kirim)
+}
+
+func (s *EventService) kirim(ctx context.Context, outbox *entity.Outbox) error {
+	event := &adapter.Event{
+		ID:        outbox.IdEvent,
+		Jenis:     outbox.Jenis,
+		Agregat:   outbox.Agregat,
+		IdAgregat: outbox.IdAgregat,
+		Data:      json.RawMessage(outbox.Data),
+		Waktu:     outbox.Dibuat,
+	}
+	var gagal []error
+	for _, sink := range s.Sinks {
+		if err := sink.Kirim(event); err != nil {
+			gagal = append(gagal, fmt.Errorf("%s: %w", sink.Nama(), err))
+		}
+	}
+	percobaan := &percobaanKirim{&outbox.Status, &outbox.Percobaan, &outbox.Error, &outbox.KirimBerikutnya, &outbox.Terkirim, constant.StatusOutboxTerkirim, constant.StatusOutboxGagal}
+	percobaan.catat(s.Config, "event", errors.Join(gagal...), false)
+	return s.OutboxRepository.Update(s.DB.WithContext(ctx), outbox)
+}
+
+// dicatat di transaksi yang sama dengan perubahannya, sehingga event hanya ada bila perubahannya tersimpan
+func catatEvent(tx *gorm.DB, outboxRepository *repository.OutboxRepository, jenis string, agregat string, idAgregat int32, data any) error {
+	nilai, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	sekarang := time.Now().Unix()
+	return outboxRepository.Create(tx, &entity.Outbox{
+		IdEvent:         uuid.NewString(),
+		Jenis:           jenis,
+		Agregat:         agregat,
+		IdAgregat:       idAgregat,
+		Data:            string(nilai),
+		Status:          constant.StatusOutboxMenunggu,
+		Dibuat:          sekarang,
+		KirimBerikutnya: sekarang,
+	})
+}
+
+func eventPasien(tx *gorm.DB, outboxRepository *repository.OutboxRepository, jenis string, pasien *entity.Pasien) error {
+	return catatEvent(tx, outboxRepository, jenis, constant.AgregatPasien, pasien.ID, map[string]any{
+		"id":               pasien.ID,
+		"noRekamMedis":     pasien.NoRekamMedis,
+		"idPengguna":       pasien.IdPengguna,
+		"idAdminPuskesmas": pasien.IdAdminPuskesmas,
+		"tanggalDaftar":    pasien.TanggalDaftar,
+		"status":           pasien.Status,
+	})
+}
+
+func eventKontrolBalik(tx *gorm.DB, outboxRepository *repository.OutboxRepository, jenis string, kontrolBalik *entity.KontrolBalik) error {
+	return catatEvent(tx, outboxRepository, jenis, constant.AgregatKontrolBalik, kontrolBalik.ID, map[string]any{
+		"id":             kontrolBalik.ID,
+		"idPasien":       kontrolBalik.IdPasien,
+		"noAntrean":      kontrolBalik.NoAntrean,
+		"tanggalKontrol": kontrolBalik.TanggalKontrol,
+		"waktuKontrol":   kontrolBalik.WaktuKontrol,
+		"status":         kontrolBalik.Status,
+	})
+}
+
+func eventPengambilanObat(tx *gorm.DB, outboxRepository *repository.OutboxRepository, jenis string, pengambilanObat *entity.PengambilanObat) error {
+	return catatEvent(tx, outboxRepository, jenis, constant.AgregatPengambilanObat, pengambilanObat.ID, map[string]any{
+		"id":                 pengambilanObat.ID,
+		"resi":               pengambilanObat.Resi,
+		"idPasien":           pengambilanObat.IdPasien,
+		"idAdminApotek":      pengambilanObat.IdAdminApotek,
+		"tanggalPengambilan": pengambilanObat.TanggalPengambilan,
+		"status":             pengambilanObat.Status,
+	})
+}
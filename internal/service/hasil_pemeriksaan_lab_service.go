@@ -0,0 +1,275 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"slices"
+)
+
+type HasilPemeriksaanLabService struct {
+	DB                            *gorm.DB
+	HasilPemeriksaanLabRepository *repository.HasilPemeriksaanLabRepository
+	PemeriksaanLabRepository      *repository.PemeriksaanLabRepository
+	KontrolBalikRepository        *repository.KontrolBalikRepository
+	PasienRepository              *repository.PasienRepository
+	AuditRepository               *repository.AuditRepository
+	Validator                     *validator.Validate
+}
+
+func NewHasilPemeriksaanLabService(
+	db *gorm.DB,
+	hasilPemeriksaanLabRepository *repository.HasilPemeriksaanLabRepository,
+	pemeriksaanLabRepository *repository.PemeriksaanLabRepository,
+	kontrolBalikRepository *repository.KontrolBalikRepository,
+	pasienRepository *repository.PasienRepository,
+	auditRepository *repository.AuditRepository,
+	validator *validator.Validate,
+) *HasilPemeriksaanLabService {
+	return &HasilPemeriksaanLabService{db, hasilPemeriksaanLabRepository, pemeriksaanLabRepository, kontrolBalikRepository, pasienRepository, auditRepository, validator}
+}
+
+func (s *HasilPemeriksaanLabService) Get(ctx context.Context, request *model.HasilPemeriksaanLabGetRequest) (*[]model.HasilPemeriksaanLabResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	kontrolBalik := new(entity.KontrolBalik)
+	if request.IdAdminPuskesmas > 0 {
+		if err := s.KontrolBalikRepository.FindByIdAndIdAdminPuskesmasAndStatusOrStatus(tx, kontrolBalik, request.ID, request.IdAdminPuskesmas, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	} else if err := s.KontrolBalikRepository.FindByIdAndStatusOrStatus(tx, kontrolBalik, request.ID, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	hasil := new([]entity.HasilPemeriksaanLab)
+	if err := s.HasilPemeriksaanLabRepository.FindAllByIdKontrolBalik(tx, hasil, kontrolBalik.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]model.HasilPemeriksaanLabResponse, 0, len(*hasil))
+	for _, h := range *hasil {
+		response = append(response, hasilPemeriksaanLabResponse(&h, &h.PemeriksaanLab))
+	}
+	return &response, nil
+}
+
+// seluruh hasil lama diganti dengan hasil pada request, hasil dapat diisi setelah kontrol balik selesai
+// karena hasil lab sering keluar belakangan
+func (s *HasilPemeriksaanLabService) Update(ctx context.Context, request *model.HasilPemeriksaanLabUpdateRequest) (*[]model.HasilPemeriksaanLabResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	kontrolBalik := new(entity.KontrolBalik)
+	if request.IdAdminPuskesmas > 0 {
+		if err := s.KontrolBalikRepository.FindByIdAndIdAdminPuskesmasAndStatusOrStatus(tx, kontrolBalik, request.ID, request.IdAdminPuskesmas, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	}
+	// dikunci agar dua penyimpanan bersamaan tidak saling menimpa
+	if err := s.KontrolBalikRepository.FindByIdAndStatusOrStatusAndLockForUpdate(tx, kontrolBalik, request.ID, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	idPemeriksaanLab := make([]int32, 0, len(request.Hasil))
+	for _, h := range request.Hasil {
+		idPemeriksaanLab = append(idPemeriksaanLab, h.IdPemeriksaanLab)
+	}
+	pemeriksaanLab := new([]entity.PemeriksaanLab)
+	if err := s.PemeriksaanLabRepository.FindAllById(tx, pemeriksaanLab, idPemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	katalog := make(map[int32]entity.PemeriksaanLab, len(*pemeriksaanLab))
+	for _, p := range *pemeriksaanLab {
+		katalog[p.ID] = p
+	}
+	if len(katalog) != len(request.Hasil) {
+		return nil, fiber.ErrNotFound
+	}
+
+	hasilLama := new([]entity.HasilPemeriksaanLab)
+	if err := s.HasilPemeriksaanLabRepository.FindAllByIdKontrolBalik(tx, hasilLama, kontrolBalik.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	sebelum := *kontrolBalik
+	sebelum.HasilPemeriksaanLab = *hasilLama
+
+	if err := s.HasilPemeriksaanLabRepository.DeleteByIdKontrolBalik(tx, kontrolBalik.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	hasil := make([]entity.HasilPemeriksaanLab, 0, len(request.Hasil))
+	for _, h := range request.Hasil {
+		p := katalog[h.IdPemeriksaanLab]
+		// dibulatkan mengikuti kolom numeric(10,2) agar flag sesuai dengan nilai yang tersimpan
+		nilai := math.Round(*h.Nilai*100) / 100
+		hasil = append(hasil, entity.HasilPemeriksaanLab{
+			IdKontrolBalik:   kontrolBalik.ID,
+			IdPemeriksaanLab: p.ID,
+			Nilai:            nilai,
+			NilaiMin:         p.NilaiMin,
+			NilaiMaks:        p.NilaiMaks,
+			Flag:             flagHasilLab(nilai, p.NilaiMin, p.NilaiMaks),
+		})
+	}
+	for i := range hasil {
+		if err := s.HasilPemeriksaanLabRepository.Create(tx, &hasil[i]); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+	kontrolBalik.HasilPemeriksaanLab = hasil
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]model.HasilPemeriksaanLabResponse, 0, len(hasil))
+	for _, h := range hasil {
+		p := katalog[h.IdPemeriksaanLab]
+		response = append(response, hasilPemeriksaanLabResponse(&h, &p))
+	}
+	return &response, nil
+}
+
+// riwayat dikelompokkan per pemeriksaan dan diurutkan berdasarkan tanggal kontrol untuk ditampilkan sebagai grafik
+func (s *HasilPemeriksaanLabService) Riwayat(ctx context.Context, request *model.RiwayatHasilPemeriksaanLabRequest) (*model.RiwayatHasilPemeriksaanLabResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pasien := new(entity.Pasien)
+	if err := findPasienMilik(tx, s.PasienRepository, pasien, request.ID, request.IdPengguna, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	var idPemeriksaanLab int32
+	if request.Kode != "" {
+		pemeriksaanLab := new(entity.PemeriksaanLab)
+		if err := s.PemeriksaanLabRepository.FindByKode(tx, pemeriksaanLab, request.Kode); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+		idPemeriksaanLab = pemeriksaanLab.ID
+	}
+
+	tanggalAkhir := int64(math.MaxInt64)
+	if request.TanggalSelesai > 0 {
+		tanggalAkhir = request.TanggalSelesai
+	}
+	hasil := new([]entity.HasilPemeriksaanLab)
+	if err := s.HasilPemeriksaanLabRepository.FindAllByIdPasienAndTanggalKontrolBetweenAndStatusOrStatus(tx, hasil, pasien.ID, idPemeriksaanLab, request.TanggalMulai, tanggalAkhir, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	riwayat := make(map[int32]*model.RiwayatPemeriksaanLabResponse)
+	for _, h := range *hasil {
+		r, ok := riwayat[h.IdPemeriksaanLab]
+		if !ok {
+			r = &model.RiwayatPemeriksaanLabResponse{
+				PemeriksaanLab: *pemeriksaanLabResponse(&h.PemeriksaanLab),
+				Seri:           []model.TitikHasilLabResponse{},
+			}
+			riwayat[h.IdPemeriksaanLab] = r
+		}
+		r.Seri = append(r.Seri, model.TitikHasilLabResponse{
+			IdKontrolBalik: h.IdKontrolBalik,
+			TanggalKontrol: h.KontrolBalik.TanggalKontrol,
+			Nilai:          h.Nilai,
+			NilaiMin:       h.NilaiMin,
+			NilaiMaks:      h.NilaiMaks,
+			Flag:           h.Flag,
+		})
+		if h.Flag == constant.FlagHasilLabRendah || h.Flag == constant.FlagHasilLabTinggi {
+			r.JumlahAbnormal++
+		}
+	}
+
+	response := &model.RiwayatHasilPemeriksaanLabResponse{
+		IdPasien:    pasien.ID,
+		Pemeriksaan: make([]model.RiwayatPemeriksaanLabResponse, 0, len(riwayat)),
+	}
+	for _, r := range riwayat {
+		r.Jumlah = len(r.Seri)
+		r.Terakhir = &r.Seri[len(r.Seri)-1]
+		response.Pemeriksaan = append(response.Pemeriksaan, *r)
+	}
+	slices.SortFunc(response.Pemeriksaan, func(a, b model.RiwayatPemeriksaanLabResponse) int {
+		return cmp.Compare(a.PemeriksaanLab.ID, b.PemeriksaanLab.ID)
+	})
+	return response, nil
+}
+
+func hasilPemeriksaanLabResponse(hasil *entity.HasilPemeriksaanLab, pemeriksaanLab *entity.PemeriksaanLab) model.HasilPemeriksaanLabResponse {
+	return model.HasilPemeriksaanLabResponse{
+		ID:             hasil.ID,
+		IdKontrolBalik: hasil.IdKontrolBalik,
+		PemeriksaanLab: pemeriksaanLabResponse(pemeriksaanLab),
+		Nilai:          hasil.Nilai,
+		NilaiMin:       hasil.NilaiMin,
+		NilaiMaks:      hasil.NilaiMaks,
+		Flag:           hasil.Flag,
+	}
+}
+
+// batas rujukan inklusif, nilai tepat di batas masih dianggap normal
+func flagHasilLab(nilai float64, nilaiMin *float64, nilaiMaks *float64) string {
+	switch {
+	case nilaiMin == nil && nilaiMaks == nil:
+		return constant.FlagHasilLabTanpaRujukan
+	case nilaiMin != nil && nilai < *nilaiMin:
+		return constant.FlagHasilLabRendah
+	case nilaiMaks != nil && nilai > *nilaiMaks:
+		return constant.FlagHasilLabTinggi
+	}
+	return constant.FlagHasilLabNormal
+}
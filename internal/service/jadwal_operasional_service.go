@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type JadwalOperasionalService struct {
+	DB                          *gorm.DB
+	JadwalOperasionalRepository *repository.JadwalOperasionalRepository
+	AdminPuskesmasRepository    *repository.AdminPuskesmasRepository
+	Validator                   *validator.Validate
+}
+
+func NewJadwalOperasionalService(
+	db *gorm.DB,
+	jadwalOperasionalRepository *repository.JadwalOperasionalRepository,
+	adminPuskesmasRepository *repository.AdminPuskesmasRepository,
+	validator *validator.Validate,
+) *JadwalOperasionalService {
+	return &JadwalOperasionalService{db, jadwalOperasionalRepository, adminPuskesmasRepository, validator}
+}
+
+func (s *JadwalOperasionalService) Get(ctx context.Context, request *model.JadwalOperasionalGetRequest) (*[]model.JadwalOperasionalResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	if err := s.AdminPuskesmasRepository.FindById(tx, &entity.AdminPuskesmas{}, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	jadwal := new([]entity.JadwalOperasional)
+	if err := s.JadwalOperasionalRepository.FindAllByIdAdminPuskesmas(tx, jadwal, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var response []model.JadwalOperasionalResponse
+	for _, j := range *jadwal {
+		response = append(response, model.JadwalOperasionalResponse{
+			Hari:        j.Hari,
+			JamBuka:     j.JamBuka,
+			JamTutup:    j.JamTutup,
+			DurasiSlot:  j.DurasiSlot,
+			KuotaSlot:   j.KuotaSlot,
+			KuotaHarian: j.KuotaHarian,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &response, nil
+}
+
+func (s *JadwalOperasionalService) Update(ctx context.Context, request *model.JadwalOperasionalUpdateRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	for _, j := range request.Jadwal {
+		buka, _ := time.Parse("15:04", j.JamBuka)
+		tutup, _ := time.Parse("15:04", j.JamTutup)
+		if tutup.Sub(buka) < time.Duration(j.DurasiSlot)*time.Minute {
+			return model.NewAppError(constant.KodeErrorJadwalOperasional)
+		}
+	}
+
+	if err := s.AdminPuskesmasRepository.FindById(tx, &entity.AdminPuskesmas{}, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	// hanya hari yang dihapus dari jadwal yang dibuang, hari lain di-upsert agar pemesanan yang sedang menunggu kunci
+	// jadwal hari itu tidak mendapati barisnya hilang
+	var hari []int16
+	for _, j := range request.Jadwal {
+		hari = append(hari, j.Hari)
+	}
+	if err := s.JadwalOperasionalRepository.DeleteByIdAdminPuskesmasAndHariNotIn(tx, request.IdAdminPuskesmas, hari); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	for _, j := range request.Jadwal {
+		jadwal := new(entity.JadwalOperasional)
+		jadwal.IdAdminPuskesmas = request.IdAdminPuskesmas
+		jadwal.Hari = j.Hari
+		jadwal.JamBuka = j.JamBuka
+		jadwal.JamTutup = j.JamTutup
+		jadwal.DurasiSlot = j.DurasiSlot
+		jadwal.KuotaSlot = j.KuotaSlot
+		jadwal.KuotaHarian = j.KuotaHarian
+		if err := s.JadwalOperasionalRepository.Upsert(tx, jadwal); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
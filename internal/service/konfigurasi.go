@@ -0,0 +1,18 @@
+package service
+
+import "github.com/spf13/viper"
+
+// nilai konfigurasi yang kosong, nol, atau negatif diganti nilai bawaan
+func konfigurasiInt(config *viper.Viper, key string, bawaan int) int {
+	if nilai := config.GetInt(key); nilai > 0 {
+		return nilai
+	}
+	return bawaan
+}
+
+func konfigurasiFloat(config *viper.Viper, key string, bawaan float64) float64 {
+	if nilai := config.GetFloat64(key); nilai > 0 {
+		return nilai
+	}
+	return bawaan
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
 	"gorm.io/gorm"
@@ -10,49 +12,56 @@ import (
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
+	"time"
 )
 
 type KontrolBalikService struct {
-	DB                     *gorm.DB
-	KontrolBalikRepository *repository.KontrolBalikRepository
-	PasienRepository       *repository.PasienRepository
-	Validator              *validator.Validate
+	DB                            *gorm.DB
+	KontrolBalikRepository        *repository.KontrolBalikRepository
+	AntreanKontrolBalikRepository *repository.AntreanKontrolBalikRepository
+	JadwalOperasionalRepository   *repository.JadwalOperasionalRepository
+	PasienRepository              *repository.PasienRepository
+	AuditRepository               *repository.AuditRepository
+	NotifikasiRepository          *repository.NotifikasiRepository
+	OutboxRepository              *repository.OutboxRepository
+	Validator                     *validator.Validate
 }
 
 func NewKontrolBalikService(
 	db *gorm.DB,
 	kontrolBalikRepository *repository.KontrolBalikRepository,
+	antreanKontrolBalikRepository *repository.AntreanKontrolBalikRepository,
+	jadwalOperasionalRepository *repository.JadwalOperasionalRepository,
 	pasienRepository *repository.PasienRepository,
+	auditRepository *repository.AuditRepository,
+	notifikasiRepository *repository.NotifikasiRepository,
+	outboxRepository *repository.OutboxRepository,
 	validator *validator.Validate,
 ) *KontrolBalikService {
-	return &KontrolBalikService{db, kontrolBalikRepository, pasienRepository, validator}
+	return &KontrolBalikService{db, kontrolBalikRepository, antreanKontrolBalikRepository, jadwalOperasionalRepository, pasienRepository, auditRepository, notifikasiRepository, outboxRepository, validator}
 }
 
-func (s *KontrolBalikService) Search(ctx context.Context, request *model.KontrolBalikSearchRequest) (*[]model.KontrolBalikResponse, error) {
+func (s *KontrolBalikService) Search(ctx context.Context, request *model.KontrolBalikSearchRequest) (*model.PageResponse[model.KontrolBalikResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	kontrolBalik := new([]entity.KontrolBalik)
+	var result *repository.PageResult
+	var err error
 	if request.IdPengguna > 0 {
-		if err := s.KontrolBalikRepository.SearchAsPengguna(tx, kontrolBalik, request.IdPengguna, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.KontrolBalikRepository.SearchAsPengguna(tx, kontrolBalik, request.IdPengguna, request.Status, pageQuery(&request.PageRequest))
 	} else if request.IdAdminPuskesmas > 0 {
-		if err := s.KontrolBalikRepository.SearchAsAdminPuskesmas(tx, kontrolBalik, request.IdAdminPuskesmas, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.KontrolBalikRepository.SearchAsAdminPuskesmas(tx, kontrolBalik, request.IdAdminPuskesmas, request.Status, pageQuery(&request.PageRequest))
 	} else {
-		if err := s.KontrolBalikRepository.Search(tx, kontrolBalik, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.KontrolBalikRepository.Search(tx, kontrolBalik, request.Status, pageQuery(&request.PageRequest))
+	}
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.KontrolBalikResponse
@@ -80,16 +89,21 @@ func (s *KontrolBalikService) Search(ctx context.Context, request *model.Kontrol
 				TanggalDaftar: k.Pasien.TanggalDaftar,
 				Status:        k.Pasien.Status,
 			},
-			Keluhan:        k.Keluhan,
-			BeratBadan:     k.BeratBadan,
-			TinggiBadan:    k.TinggiBadan,
-			TekananDarah:   k.TekananDarah,
-			DenyutNadi:     k.DenyutNadi,
-			HasilLab:       k.HasilLab,
-			HasilEkg:       k.HasilEkg,
-			HasilDiagnosa:  k.HasilDiagnosa,
-			TanggalKontrol: k.TanggalKontrol,
-			Status:         k.Status,
+			Keluhan:               k.Keluhan,
+			BeratBadan:            k.BeratBadan,
+			TinggiBadan:           k.TinggiBadan,
+			TekananDarah:          tekananDarah(k.TekananDarahSistolik, k.TekananDarahDiastolik),
+			TekananDarahSistolik:  k.TekananDarahSistolik,
+			TekananDarahDiastolik: k.TekananDarahDiastolik,
+			TekananDarahLama:      k.TekananDarahLama,
+			Imt:                   imt(k.BeratBadan, k.TinggiBadan),
+			DenyutNadi:            k.DenyutNadi,
+			HasilLab:              k.HasilLab,
+			HasilEkg:              k.HasilEkg,
+			HasilDiagnosa:         k.HasilDiagnosa,
+			TanggalKontrol:        k.TanggalKontrol,
+			WaktuKontrol:          k.WaktuKontrol,
+			Status:                k.Status,
 		})
 	}
 
@@ -98,25 +112,26 @@ func (s *KontrolBalikService) Search(ctx context.Context, request *model.Kontrol
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
+// semua status dapat dibuka karena timeline pasien menautkan kunjungan yang sudah selesai maupun batal
 func (s *KontrolBalikService) Get(ctx context.Context, request *model.KontrolBalikGetRequest) (*model.KontrolBalikResponse, error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	kontrolBalik := new(entity.KontrolBalik)
 	if request.IdAdminPuskesmas > 0 {
-		if err := s.KontrolBalikRepository.FindByIdAndIdAdminPuskesmasAndStatus(tx, kontrolBalik, request.ID, request.IdAdminPuskesmas, constant.StatusKontrolBalikMenunggu); err != nil {
+		if err := s.KontrolBalikRepository.FindByIdAndIdAdminPuskesmas(tx, kontrolBalik, request.ID, request.IdAdminPuskesmas); err != nil {
 			slog.Error(err.Error())
 			return nil, fiber.ErrNotFound
 		}
-	} else if err := s.KontrolBalikRepository.FindByIdAndStatus(tx, kontrolBalik, request.ID, constant.StatusKontrolBalikMenunggu); err != nil {
+	} else if err := s.KontrolBalikRepository.FindById(tx, kontrolBalik, request.ID); err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrNotFound
 	}
@@ -132,13 +147,19 @@ func (s *KontrolBalikService) Get(ctx context.Context, request *model.KontrolBal
 	response.Keluhan = kontrolBalik.Keluhan
 	response.BeratBadan = kontrolBalik.BeratBadan
 	response.TinggiBadan = kontrolBalik.TinggiBadan
-	response.TekananDarah = kontrolBalik.TekananDarah
+	response.TekananDarah = tekananDarah(kontrolBalik.TekananDarahSistolik, kontrolBalik.TekananDarahDiastolik)
+	response.TekananDarahSistolik = kontrolBalik.TekananDarahSistolik
+	response.TekananDarahDiastolik = kontrolBalik.TekananDarahDiastolik
+	response.TekananDarahLama = kontrolBalik.TekananDarahLama
+	response.Imt = imt(kontrolBalik.BeratBadan, kontrolBalik.TinggiBadan)
 	response.DenyutNadi = kontrolBalik.DenyutNadi
 	response.HasilLab = kontrolBalik.HasilLab
 	response.HasilEkg = kontrolBalik.HasilEkg
 	response.HasilDiagnosa = kontrolBalik.HasilDiagnosa
 	response.TanggalKontrol = kontrolBalik.TanggalKontrol
+	response.WaktuKontrol = kontrolBalik.WaktuKontrol
 	response.IdPasien = kontrolBalik.IdPasien
+	response.Status = kontrolBalik.Status
 	return response, nil
 }
 
@@ -148,7 +169,7 @@ func (s *KontrolBalikService) Create(ctx context.Context, request *model.Kontrol
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pasien := new(entity.Pasien)
@@ -164,23 +185,52 @@ func (s *KontrolBalikService) Create(ctx context.Context, request *model.Kontrol
 		}
 	}
 
-	noAntrean, err := s.KontrolBalikRepository.FindMaksNoAntreanByTanggalKontrolAndIdAdminPuskesmasAndStatus(tx, request.TanggalKontrol, pasien.IdAdminPuskesmas, constant.StatusKontrolBalikMenunggu)
+	waktuKontrol, err := s.assignSlot(tx, pasien.IdAdminPuskesmas, request.TanggalKontrol, 0)
+	if err != nil {
+		return err
+	}
+
+	antrean, err := s.lockAntrean(tx, pasien.IdAdminPuskesmas, request.TanggalKontrol)
 	if err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
+	antrean.NoAntreanTerakhir++
 
 	kontrolBalik := new(entity.KontrolBalik)
 	kontrolBalik.IdPasien = request.IdPasien
-	kontrolBalik.NoAntrean = noAntrean + 1
+	kontrolBalik.NoAntrean = antrean.NoAntreanTerakhir
 	kontrolBalik.TanggalKontrol = request.TanggalKontrol
+	kontrolBalik.WaktuKontrol = waktuKontrol
+	kontrolBalik.IdAdminPuskesmas = antrean.IdAdminPuskesmas
+	kontrolBalik.TanggalAntrean = antrean.TanggalKontrol
 	kontrolBalik.Status = constant.StatusKontrolBalikMenunggu
 
+	if err := s.AntreanKontrolBalikRepository.Update(tx, antrean); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := s.KontrolBalikRepository.Create(tx, kontrolBalik); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditBuat, nil, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := notifikasiKontrolBalik(tx, s.NotifikasiRepository, pasien.IdPengguna, constant.JenisNotifikasiKontrolBalikDibuat, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventKontrolBalik(tx, s.OutboxRepository, constant.EventKontrolBalikDibuat, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -193,9 +243,14 @@ func (s *KontrolBalikService) Update(ctx context.Context, request *model.Kontrol
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	// klien lama masih mengirim tekananDarah sebagai teks, nilai terstruktur didahulukan bila keduanya dikirim
+	if request.TekananDarahSistolik == 0 && request.TekananDarahDiastolik == 0 {
+		request.TekananDarahSistolik, request.TekananDarahDiastolik = uraiTekananDarah(request.TekananDarah)
+	}
+
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	kontrolBalik := new(entity.KontrolBalik)
@@ -223,13 +278,36 @@ func (s *KontrolBalikService) Update(ctx context.Context, request *model.Kontrol
 		}
 	}
 
-	total, err := s.KontrolBalikRepository.CountByNoAntreanAndTanggalKontrolAndIdAdminPuskesmasAndStatus(tx, request.NoAntrean, request.TanggalKontrol, pasien.IdAdminPuskesmas, constant.StatusKontrolBalikMenunggu)
+	sebelum := *kontrolBalik
+	if kontrolBalik.TanggalKontrol != request.TanggalKontrol || kontrolBalik.IdPasien != request.IdPasien {
+		waktuKontrol, err := s.assignSlot(tx, pasien.IdAdminPuskesmas, request.TanggalKontrol, kontrolBalik.ID)
+		if err != nil {
+			return err
+		}
+		kontrolBalik.WaktuKontrol = waktuKontrol
+	}
+
+	antrean, err := s.lockAntrean(tx, pasien.IdAdminPuskesmas, request.TanggalKontrol)
 	if err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
-	if total > 0 && kontrolBalik.NoAntrean != request.NoAntrean {
-		return fiber.NewError(fiber.StatusConflict, "Nomor antrean pada tanggal tersebut sudah digunakan")
+
+	total, err := s.KontrolBalikRepository.CountByNoAntreanAndIdAdminPuskesmasAndTanggalAntreanAndStatus(tx, request.NoAntrean, antrean.IdAdminPuskesmas, antrean.TanggalKontrol, constant.StatusKontrolBalikMenunggu)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if total > 0 && (kontrolBalik.NoAntrean != request.NoAntrean || kontrolBalik.IdAdminPuskesmas != antrean.IdAdminPuskesmas || kontrolBalik.TanggalAntrean != antrean.TanggalKontrol) {
+		return model.NewAppError(constant.KodeErrorNomorAntreanDigunakan)
+	}
+
+	if request.NoAntrean > antrean.NoAntreanTerakhir {
+		antrean.NoAntreanTerakhir = request.NoAntrean
+		if err := s.AntreanKontrolBalikRepository.Update(tx, antrean); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
 	}
 
 	kontrolBalik.IdPasien = request.IdPasien
@@ -237,18 +315,49 @@ func (s *KontrolBalikService) Update(ctx context.Context, request *model.Kontrol
 	kontrolBalik.Keluhan = request.Keluhan
 	kontrolBalik.BeratBadan = request.BeratBadan
 	kontrolBalik.TinggiBadan = request.TinggiBadan
-	kontrolBalik.TekananDarah = request.TekananDarah
+	kontrolBalik.TekananDarahSistolik = request.TekananDarahSistolik
+	kontrolBalik.TekananDarahDiastolik = request.TekananDarahDiastolik
+	// teks lama yang belum terurai dianggap sudah ditinjau begitu nilai terstruktur diisi
+	if request.TekananDarahSistolik > 0 {
+		kontrolBalik.TekananDarahLama = nil
+	}
 	kontrolBalik.DenyutNadi = request.DenyutNadi
 	kontrolBalik.HasilLab = request.HasilLab
 	kontrolBalik.HasilEkg = request.HasilEkg
 	kontrolBalik.HasilDiagnosa = request.HasilDiagnosa
 	kontrolBalik.TanggalKontrol = request.TanggalKontrol
+	kontrolBalik.IdAdminPuskesmas = antrean.IdAdminPuskesmas
+	kontrolBalik.TanggalAntrean = antrean.TanggalKontrol
 
 	if err := s.KontrolBalikRepository.Update(tx, kontrolBalik); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventKontrolBalik(tx, s.OutboxRepository, constant.EventKontrolBalikDiubah, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	// pasien baru menerima jadwal sebagai kontrol balik baru, pasien yang sama hanya diberi tahu bila jadwalnya berubah
+	jenis := ""
+	if sebelum.IdPasien != kontrolBalik.IdPasien {
+		jenis = constant.JenisNotifikasiKontrolBalikDibuat
+	} else if sebelum.TanggalKontrol != kontrolBalik.TanggalKontrol || sebelum.WaktuKontrol != kontrolBalik.WaktuKontrol {
+		jenis = constant.JenisNotifikasiKontrolBalikDijadwalkanUlang
+	}
+	if jenis != "" {
+		if err := notifikasiKontrolBalik(tx, s.NotifikasiRepository, pasien.IdPengguna, jenis, kontrolBalik); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -263,7 +372,7 @@ func (s *KontrolBalikService) Delete(ctx context.Context, request *model.Kontrol
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	kontrolBalik := new(entity.KontrolBalik)
@@ -284,6 +393,11 @@ func (s *KontrolBalikService) Delete(ctx context.Context, request *model.Kontrol
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditHapus, kontrolBalik, nil); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -298,7 +412,7 @@ func (s *KontrolBalikService) Batal(ctx context.Context, request *model.KontrolB
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	kontrolBalik := new(entity.KontrolBalik)
@@ -314,6 +428,55 @@ func (s *KontrolBalikService) Batal(ctx context.Context, request *model.KontrolB
 		}
 	}
 
+	if err := s.batal(tx, kontrolBalik, request.Aktor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// kontrol balik yang masih menunggu setelah tanggalnya lewat dibatalkan oleh sistem, satu transaksi per kontrol balik
+func (s *KontrolBalikService) BatalTerlewat(ctx context.Context) (int, error) {
+	awal, _ := batasHari(time.Now())
+	kontrolBalik := new([]entity.KontrolBalik)
+	if err := s.KontrolBalikRepository.FindAllByTanggalKontrolBeforeAndStatus(s.DB.WithContext(ctx), kontrolBalik, awal, constant.StatusKontrolBalikMenunggu); err != nil {
+		return 0, err
+	}
+
+	// kegagalan satu kontrol balik tidak menghentikan yang lain
+	jumlah := 0
+	var gagal []error
+	for _, k := range *kontrolBalik {
+		if err := s.batalTerlewat(ctx, k.ID); err != nil {
+			gagal = append(gagal, fmt.Errorf("kontrol balik %d: %w", k.ID, err))
+			continue
+		}
+		jumlah++
+	}
+	return jumlah, errors.Join(gagal...)
+}
+
+func (s *KontrolBalikService) batalTerlewat(ctx context.Context, id int32) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	kontrolBalik := new(entity.KontrolBalik)
+	if err := s.KontrolBalikRepository.FindByIdAndStatusAndLockForUpdate(tx, kontrolBalik, id, constant.StatusKontrolBalikMenunggu); err != nil {
+		return err
+	}
+	if err := s.batal(tx, kontrolBalik, nil); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (s *KontrolBalikService) batal(tx *gorm.DB, kontrolBalik *entity.KontrolBalik, aktor *model.Auth) error {
+	sebelum := *kontrolBalik
 	kontrolBalik.Status = constant.StatusKontrolBalikBatal
 
 	if err := s.KontrolBalikRepository.Update(tx, kontrolBalik); err != nil {
@@ -321,7 +484,22 @@ func (s *KontrolBalikService) Batal(ctx context.Context, request *model.KontrolB
 		return fiber.ErrInternalServerError
 	}
 
-	if err := tx.Commit().Error; err != nil {
+	if err := catatAudit(tx, s.AuditRepository, aktor, constant.AksiAuditBatal, &sebelum, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	pasien := new(entity.Pasien)
+	if err := s.PasienRepository.FindById(tx, pasien, kontrolBalik.IdPasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if err := notifikasiKontrolBalik(tx, s.NotifikasiRepository, pasien.IdPengguna, constant.JenisNotifikasiKontrolBalikBatal, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventKontrolBalik(tx, s.OutboxRepository, constant.EventKontrolBalikBatal, kontrolBalik); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
@@ -335,7 +513,7 @@ func (s *KontrolBalikService) Selesai(ctx context.Context, request *model.Kontro
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	kontrolBalik := new(entity.KontrolBalik)
@@ -351,6 +529,7 @@ func (s *KontrolBalikService) Selesai(ctx context.Context, request *model.Kontro
 		}
 	}
 
+	sebelum := *kontrolBalik
 	kontrolBalik.Status = constant.StatusKontrolBalikSelesai
 
 	if err := s.KontrolBalikRepository.Update(tx, kontrolBalik); err != nil {
@@ -358,6 +537,16 @@ func (s *KontrolBalikService) Selesai(ctx context.Context, request *model.Kontro
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditSelesai, &sebelum, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventKontrolBalik(tx, s.OutboxRepository, constant.EventKontrolBalikSelesai, kontrolBalik); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -365,3 +554,170 @@ func (s *KontrolBalikService) Selesai(ctx context.Context, request *model.Kontro
 
 	return nil
 }
+
+func (s *KontrolBalikService) Slot(ctx context.Context, request *model.KontrolBalikSlotRequest) (*[]model.KontrolBalikSlotResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+	if request.TanggalSelesai-request.TanggalMulai > 31*24*60*60 {
+		return nil, model.NewAppError(constant.KodeErrorRentangTanggal, 31)
+	}
+
+	jadwal := new([]entity.JadwalOperasional)
+	if err := s.JadwalOperasionalRepository.FindAllByIdAdminPuskesmas(tx, jadwal, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	jadwalHarian := make(map[time.Weekday]entity.JadwalOperasional)
+	for _, j := range *jadwal {
+		jadwalHarian[time.Weekday(j.Hari)] = j
+	}
+
+	awal, _ := batasHari(time.Unix(request.TanggalMulai, 0))
+	_, akhir := batasHari(time.Unix(request.TanggalSelesai, 0))
+
+	kontrolBalik := new([]entity.KontrolBalik)
+	if err := s.KontrolBalikRepository.FindAllByIdAdminPuskesmasAndTanggalKontrolBetweenAndStatusOrStatus(tx, kontrolBalik, request.IdAdminPuskesmas, awal, akhir, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	terisi := make(map[int64]int32)
+	terisiHarian := make(map[int64]int32)
+	for _, k := range *kontrolBalik {
+		terisi[k.WaktuKontrol]++
+		hari, _ := batasHari(time.Unix(k.TanggalKontrol, 0))
+		terisiHarian[hari]++
+	}
+
+	response := make([]model.KontrolBalikSlotResponse, 0)
+	for hari := time.Unix(awal, 0); hari.Unix() < akhir; hari = hari.AddDate(0, 0, 1) {
+		j, ok := jadwalHarian[hari.Weekday()]
+		if !ok {
+			continue
+		}
+		sisaHarian := int32(-1)
+		if j.KuotaHarian > 0 {
+			sisaHarian = max(j.KuotaHarian-terisiHarian[hari.Unix()], 0)
+		}
+		for _, slot := range slotHarian(&j, hari) {
+			tersedia := max(j.KuotaSlot-terisi[slot.mulai], 0)
+			if sisaHarian >= 0 {
+				tersedia = min(tersedia, sisaHarian)
+			}
+			response = append(response, model.KontrolBalikSlotResponse{
+				WaktuMulai:   slot.mulai,
+				WaktuSelesai: slot.selesai,
+				Kuota:        j.KuotaSlot,
+				Terisi:       terisi[slot.mulai],
+				Tersedia:     tersedia,
+			})
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &response, nil
+}
+
+// penghitung antrean dikunci per puskesmas per hari, sama seperti kuota harian pada assignSlot
+func (s *KontrolBalikService) lockAntrean(tx *gorm.DB, idAdminPuskesmas int32, tanggalKontrol int64) (*entity.AntreanKontrolBalik, error) {
+	tanggalAntrean, _ := batasHari(time.Unix(tanggalKontrol, 0))
+	// nilai awal diambil dari antrean yang sudah ada agar data lama tidak bertabrakan
+	noAntrean, err := s.KontrolBalikRepository.FindMaksNoAntreanByIdAdminPuskesmasAndTanggalAntreanAndStatus(tx, idAdminPuskesmas, tanggalAntrean, constant.StatusKontrolBalikMenunggu)
+	if err != nil {
+		return nil, err
+	}
+	antrean := new(entity.AntreanKontrolBalik)
+	antrean.IdAdminPuskesmas = idAdminPuskesmas
+	antrean.TanggalKontrol = tanggalAntrean
+	antrean.NoAntreanTerakhir = noAntrean
+	if err := s.AntreanKontrolBalikRepository.FirstOrCreateAndLockForUpdate(tx, antrean); err != nil {
+		return nil, err
+	}
+	return antrean, nil
+}
+
+func (s *KontrolBalikService) assignSlot(tx *gorm.DB, idAdminPuskesmas int32, tanggalKontrol int64, idKontrolBalik int32) (int64, error) {
+	total, err := s.JadwalOperasionalRepository.CountByIdAdminPuskesmas(tx, idAdminPuskesmas)
+	if err != nil {
+		slog.Error(err.Error())
+		return 0, fiber.ErrInternalServerError
+	}
+	// puskesmas tanpa jadwal terstruktur tetap menerima kontrol balik tanpa batas
+	if total == 0 {
+		return 0, nil
+	}
+
+	tanggal := time.Unix(tanggalKontrol, 0)
+	jadwal := new(entity.JadwalOperasional)
+	if err := s.JadwalOperasionalRepository.FindByIdAdminPuskesmasAndHariAndLockForUpdate(tx, jadwal, idAdminPuskesmas, int16(tanggal.Weekday())); err != nil {
+		slog.Error(err.Error())
+		return 0, model.NewAppError(constant.KodeErrorPuskesmasTutup)
+	}
+
+	awal, akhir := batasHari(tanggal)
+	kontrolBalik := new([]entity.KontrolBalik)
+	if err := s.KontrolBalikRepository.FindAllByIdAdminPuskesmasAndTanggalKontrolBetweenAndStatusOrStatus(tx, kontrolBalik, idAdminPuskesmas, awal, akhir, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return 0, fiber.ErrInternalServerError
+	}
+
+	terisi := make(map[int64]int32)
+	var terisiHarian int32
+	for _, k := range *kontrolBalik {
+		if k.ID == idKontrolBalik {
+			continue
+		}
+		terisi[k.WaktuKontrol]++
+		terisiHarian++
+	}
+	if jadwal.KuotaHarian > 0 && terisiHarian >= jadwal.KuotaHarian {
+		return 0, model.NewAppError(constant.KodeErrorKuotaKontrolBalikPenuh)
+	}
+
+	for _, slot := range slotHarian(jadwal, tanggal) {
+		if terisi[slot.mulai] < jadwal.KuotaSlot {
+			return slot.mulai, nil
+		}
+	}
+	return 0, model.NewAppError(constant.KodeErrorSlotKontrolBalikHabis)
+}
+
+type slotKontrol struct {
+	mulai   int64
+	selesai int64
+}
+
+func slotHarian(jadwal *entity.JadwalOperasional, tanggal time.Time) []slotKontrol {
+	buka, err := time.Parse("15:04", jadwal.JamBuka)
+	if err != nil {
+		return nil
+	}
+	tutup, err := time.Parse("15:04", jadwal.JamTutup)
+	if err != nil {
+		return nil
+	}
+	awal := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), buka.Hour(), buka.Minute(), 0, 0, tanggal.Location())
+	akhir := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), tutup.Hour(), tutup.Minute(), 0, 0, tanggal.Location())
+	durasi := time.Duration(jadwal.DurasiSlot) * time.Minute
+
+	var slot []slotKontrol
+	for t := awal; durasi > 0 && !t.Add(durasi).After(akhir); t = t.Add(durasi) {
+		slot = append(slot, slotKontrol{mulai: t.Unix(), selesai: t.Add(durasi).Unix()})
+	}
+	return slot
+}
+
+// batas hari mengikuti time.Local yang diatur dari ZONA_WAKTU, sama dengan TimeZone sesi database
+func batasHari(tanggal time.Time) (int64, int64) {
+	tanggal = tanggal.In(time.Local)
+	awal := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 0, 0, 0, 0, tanggal.Location())
+	return awal.Unix(), awal.AddDate(0, 0, 1).Unix()
+}
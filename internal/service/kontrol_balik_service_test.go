@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"prb_care_api/internal/config"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"prb_care_api/internal/service"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// database uji dibaca dari TEST_DB_*, misalnya TEST_DB_NAME=prbcare_test, agar tidak pernah menyentuh DB_*
+func testDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	testConfig := viper.New()
+	testConfig.SetEnvPrefix("test")
+	testConfig.AutomaticEnv()
+	testConfig.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if testConfig.GetString("db.name") == "" {
+		t.Skip("TEST_DB_NAME tidak diatur")
+	}
+
+	db := config.OpenDatabase(testConfig)
+	if _, err := config.MigrateUp(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestKontrolBalikCreateParalel(t *testing.T) {
+	db := testDatabase(t)
+
+	kode := fmt.Sprintf("%d", time.Now().UnixNano())
+	adminPuskesmas := &entity.AdminPuskesmas{
+		NamaPuskesmas:    "Puskesmas Uji",
+		Telepon:          kode[len(kode)-12:],
+		Alamat:           "Alamat Uji",
+		WaktuOperasional: "Senin-Jumat",
+		Username:         "puskesmas" + kode,
+		Password:         "password",
+	}
+	if err := db.Create(adminPuskesmas).Error; err != nil {
+		t.Fatal(err)
+	}
+	pengguna := &entity.Pengguna{
+		NamaLengkap:     "Pengguna Uji",
+		Telepon:         kode[len(kode)-13:],
+		TeleponKeluarga: kode[len(kode)-13:],
+		Alamat:          "Alamat Uji",
+		Username:        "pengguna" + kode,
+		Password:        "password",
+	}
+	if err := db.Create(pengguna).Error; err != nil {
+		t.Fatal(err)
+	}
+	pasien := &entity.Pasien{
+		NoRekamMedis:     kode,
+		IdPengguna:       pengguna.ID,
+		IdAdminPuskesmas: adminPuskesmas.ID,
+		TanggalDaftar:    time.Now().Unix(),
+		Status:           constant.StatusPasienAktif,
+	}
+	if err := db.Create(pasien).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		var id []int32
+		db.Model(&entity.KontrolBalik{}).Where("id_pasien = ?", pasien.ID).Pluck("id", &id)
+		db.Where("entitas = ? AND id_entitas IN ?", "kontrol_balik", append(id, 0)).Delete(&entity.Audit{})
+		db.Where("agregat = ? AND id_agregat IN ?", constant.AgregatKontrolBalik, append(id, 0)).Delete(&entity.Outbox{})
+		db.Where("id_pasien = ?", pasien.ID).Delete(&entity.KontrolBalik{})
+		db.Where("id_admin_puskesmas = ?", adminPuskesmas.ID).Delete(&entity.AntreanKontrolBalik{})
+		db.Delete(pasien)
+		db.Delete(pengguna)
+		db.Delete(adminPuskesmas)
+	})
+
+	kontrolBalikService := service.NewKontrolBalikService(
+		db,
+		repository.NewKontrolBalikRepository(),
+		repository.NewAntreanKontrolBalikRepository(),
+		repository.NewJadwalOperasionalRepository(),
+		repository.NewPasienRepository(),
+		repository.NewAuditRepository(),
+		repository.NewNotifikasiRepository(),
+		repository.NewOutboxRepository(),
+		config.NewValidator(),
+	)
+
+	// jam berbeda pada hari yang sama tetap berbagi satu urutan antrean
+	besok := time.Now().AddDate(0, 0, 1)
+	awal := time.Date(besok.Year(), besok.Month(), besok.Day(), 8, 0, 0, 0, besok.Location())
+	const jumlah = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, jumlah)
+	for i := range jumlah {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- kontrolBalikService.Create(context.Background(), &model.KontrolBalikCreateRequest{
+				IdPasien:         pasien.ID,
+				TanggalKontrol:   awal.Add(time.Duration(i) * time.Minute).Unix(),
+				IdAdminPuskesmas: adminPuskesmas.ID,
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var noAntrean []int32
+	if err := db.Model(&entity.KontrolBalik{}).Where("id_pasien = ?", pasien.ID).Order("no_antrean").Pluck("no_antrean", &noAntrean).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(noAntrean) != jumlah {
+		t.Fatalf("jumlah kontrol balik %d, seharusnya %d", len(noAntrean), jumlah)
+	}
+	for i, n := range noAntrean {
+		if n != int32(i+1) {
+			t.Fatalf("nomor antrean %v tidak berurutan tanpa duplikat", noAntrean)
+		}
+	}
+}
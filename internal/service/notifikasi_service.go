@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type NotifikasiService struct {
+	DB                   *gorm.DB
+	NotifikasiRepository *repository.NotifikasiRepository
+	PenggunaRepository   *repository.PenggunaRepository
+	Notifier             adapter.Notifier
+	Validator            *validator.Validate
+	Config               *viper.Viper
+}
+
+func NewNotifikasiService(
+	db *gorm.DB,
+	notifikasiRepository *repository.NotifikasiRepository,
+	penggunaRepository *repository.PenggunaRepository,
+	notifier adapter.Notifier,
+	validator *validator.Validate,
+	config *viper.Viper,
+) *NotifikasiService {
+	return &NotifikasiService{db, notifikasiRepository, penggunaRepository, notifier, validator, config}
+}
+
+func (s *NotifikasiService) List(ctx context.Context, request *model.NotifikasiListRequest) (*model.PageResponse[model.NotifikasiResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	notifikasi := new([]entity.Notifikasi)
+	result, err := s.NotifikasiRepository.Search(tx, notifikasi, request.IdPengguna, request.Jenis, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.NotifikasiResponse
+	for _, n := range *notifikasi {
+		response = append(response, model.NotifikasiResponse{
+			ID:              n.ID,
+			IdPengguna:      n.IdPengguna,
+			Jenis:           n.Jenis,
+			Judul:           n.Judul,
+			Isi:             n.Isi,
+			Data:            json.RawMessage(n.Data),
+			Status:          n.Status,
+			Percobaan:       n.Percobaan,
+			Error:           n.Error,
+			Dibuat:          n.Dibuat,
+			KirimBerikutnya: n.KirimBerikutnya,
+			Terkirim:        n.Terkirim,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+func (s *NotifikasiService) KirimUlang(ctx context.Context, request *model.NotifikasiKirimUlangRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	notifikasi := new(entity.Notifikasi)
+	if err := s.NotifikasiRepository.FindByIdAndLockForUpdate(tx, notifikasi, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+	if notifikasi.Status == constant.StatusNotifikasiTerkirim {
+		return model.NewAppError(constant.KodeErrorNotifikasiTerkirim)
+	}
+
+	notifikasi.Status = constant.StatusNotifikasiMenunggu
+	notifikasi.Percobaan = 0
+	notifikasi.KirimBerikutnya = time.Now().Unix()
+	if err := s.NotifikasiRepository.Update(tx, notifikasi); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// memproses antrean setiap notifikasi.interval detik sampai ctx selesai
+func (s *NotifikasiService) Jalankan(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(konfigurasiInt(s.Config, "notifikasi.interval", 10)) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := s.Kirim(ctx); err != nil {
+			slog.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifikasi yang siap dikirim diklaim dulu dengan memajukan kirim_berikutnya lalu dikirim di luar transaksi,
+// sehingga instance lain tidak mengirim ulang dan notifikasi yang klaimnya terputus akan dicoba lagi
+func (s *NotifikasiService) Kirim(ctx context.Context) error {
+	return klaimLaluKirim(ctx, s.DB, s.NotifikasiRepository, 50, func(n *entity.Notifikasi) *int64 { return &n.KirimBerikutnya }, s.kirim)
+}
+
+func (s *NotifikasiService) kirim(ctx context.Context, notifikasi *entity.Notifikasi) error {
+	db := s.DB.WithContext(ctx)
+
+	pengguna := new(entity.Pengguna)
+	if err := s.PenggunaRepository.FindById(db, pengguna, notifikasi.IdPengguna); err != nil {
+		return err
+	}
+
+	var err error
+	if pengguna.TokenPerangkat == "" {
+		err = adapter.ErrTokenPerangkatTidakValid
+	} else {
+		data := make(map[string]string)
+		if err := json.Unmarshal([]byte(notifikasi.Data), &data); err != nil {
+			return err
+		}
+		err = s.Notifier.Send(pengguna.TokenPerangkat, notifikasi.Judul, notifikasi.Isi, data)
+	}
+
+	tokenTidakValid := errors.Is(err, adapter.ErrTokenPerangkatTidakValid)
+	// token yang sudah tidak terdaftar dihapus agar notifikasi berikutnya tidak ikut dicoba
+	if tokenTidakValid && pengguna.TokenPerangkat != "" {
+		if err := s.PenggunaRepository.ClearTokenPerangkatByIdAndTokenPerangkat(db, pengguna.ID, pengguna.TokenPerangkat); err != nil {
+			return err
+		}
+	}
+	percobaan := &percobaanKirim{&notifikasi.Status, &notifikasi.Percobaan, &notifikasi.Error, &notifikasi.KirimBerikutnya, &notifikasi.Terkirim, constant.StatusNotifikasiTerkirim, constant.StatusNotifikasiGagal}
+	percobaan.catat(s.Config, "notifikasi", err, tokenTidakValid)
+	return s.NotifikasiRepository.Update(db, notifikasi)
+}
+
+// dicatat di transaksi yang sama dengan perubahannya lalu dikirim oleh NotifikasiService.Jalankan.
+// notifikasi dengan kunci yang sudah pernah diantrekan tidak dibuat lagi
+func antreNotifikasi(tx *gorm.DB, notifikasiRepository *repository.NotifikasiRepository, idPengguna int32, jenis string, kunci string, judul string, isi string, data map[string]string) error {
+	data["jenis"] = jenis
+	nilai, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	sekarang := time.Now().Unix()
+	notifikasi := &entity.Notifikasi{
+		IdPengguna:      idPengguna,
+		Jenis:           jenis,
+		Judul:           judul,
+		Isi:             isi,
+		Data:            string(nilai),
+		Status:          constant.StatusNotifikasiMenunggu,
+		Dibuat:          sekarang,
+		KirimBerikutnya: sekarang,
+	}
+	if kunci == "" {
+		return notifikasiRepository.Create(tx, notifikasi)
+	}
+	notifikasi.Kunci = &kunci
+	return notifikasiRepository.CreateOrIgnoreByKunci(tx, notifikasi)
+}
+
+func notifikasiKontrolBalik(tx *gorm.DB, notifikasiRepository *repository.NotifikasiRepository, idPengguna int32, jenis string, kontrolBalik *entity.KontrolBalik) error {
+	jadwal := time.Unix(kontrolBalik.TanggalKontrol, 0).Format("02-01-2006")
+	if kontrolBalik.WaktuKontrol > 0 {
+		jadwal += " pukul " + time.Unix(kontrolBalik.WaktuKontrol, 0).Format("15:04")
+	}
+
+	var judul, isi, kunci string
+	switch jenis {
+	case constant.JenisNotifikasiKontrolBalikDibuat:
+		judul = "Jadwal Kontrol Balik"
+		isi = fmt.Sprintf("Kontrol balik Anda dijadwalkan pada %s dengan nomor antrean %d.", jadwal, kontrolBalik.NoAntrean)
+	case constant.JenisNotifikasiKontrolBalikDijadwalkanUlang:
+		judul = "Perubahan Jadwal Kontrol Balik"
+		isi = fmt.Sprintf("Kontrol balik Anda dijadwalkan ulang menjadi %s dengan nomor antrean %d.", jadwal, kontrolBalik.NoAntrean)
+	case constant.JenisNotifikasiKontrolBalikBatal:
+		judul = "Kontrol Balik Dibatalkan"
+		isi = fmt.Sprintf("Kontrol balik Anda pada %s dibatalkan.", jadwal)
+	case constant.JenisNotifikasiKontrolBalikPengingatH1:
+		judul = "Pengingat Kontrol Balik"
+		isi = fmt.Sprintf("Besok, %s, Anda dijadwalkan kontrol balik dengan nomor antrean %d.", jadwal, kontrolBalik.NoAntrean)
+		kunci = fmt.Sprintf("%s:%d:%d", jenis, kontrolBalik.ID, kontrolBalik.TanggalKontrol)
+	case constant.JenisNotifikasiKontrolBalikPengingatHariIni:
+		judul = "Pengingat Kontrol Balik"
+		isi = fmt.Sprintf("Hari ini, %s, Anda dijadwalkan kontrol balik dengan nomor antrean %d.", jadwal, kontrolBalik.NoAntrean)
+		kunci = fmt.Sprintf("%s:%d:%d", jenis, kontrolBalik.ID, kontrolBalik.TanggalKontrol)
+	}
+	return antreNotifikasi(tx, notifikasiRepository, idPengguna, jenis, kunci, judul, isi, map[string]string{
+		"idKontrolBalik": fmt.Sprint(kontrolBalik.ID),
+	})
+}
+
+func notifikasiPengambilanObat(tx *gorm.DB, notifikasiRepository *repository.NotifikasiRepository, idPengguna int32, jenis string, pengambilanObat *entity.PengambilanObat) error {
+	tanggal := time.Unix(pengambilanObat.TanggalPengambilan, 0).Format("02-01-2006")
+
+	var judul, isi, kunci string
+	switch jenis {
+	case constant.JenisNotifikasiPengambilanObatDibuat:
+		judul = "Jadwal Pengambilan Obat"
+		isi = fmt.Sprintf("Obat Anda dapat diambil pada %s dengan resi %s.", tanggal, pengambilanObat.Resi)
+	case constant.JenisNotifikasiPengambilanObatBatal:
+		judul = "Pengambilan Obat Dibatalkan"
+		isi = fmt.Sprintf("Pengambilan obat dengan resi %s pada %s dibatalkan.", pengambilanObat.Resi, tanggal)
+	case constant.JenisNotifikasiPengambilanObatPengingatH1:
+		judul = "Pengingat Pengambilan Obat"
+		isi = fmt.Sprintf("Besok, %s, obat Anda dapat diambil dengan resi %s.", tanggal, pengambilanObat.Resi)
+		kunci = fmt.Sprintf("%s:%d:%d", jenis, pengambilanObat.ID, pengambilanObat.TanggalPengambilan)
+	case constant.JenisNotifikasiPengambilanObatPengingatHariIni:
+		judul = "Pengingat Pengambilan Obat"
+		isi = fmt.Sprintf("Hari ini, %s, obat Anda dapat diambil dengan resi %s.", tanggal, pengambilanObat.Resi)
+		kunci = fmt.Sprintf("%s:%d:%d", jenis, pengambilanObat.ID, pengambilanObat.TanggalPengambilan)
+	}
+	return antreNotifikasi(tx, notifikasiRepository, idPengguna, jenis, kunci, judul, isi, map[string]string{
+		"idPengambilanObat": fmt.Sprint(pengambilanObat.ID),
+		"resi":              pengambilanObat.Resi,
+	})
+}
@@ -6,17 +6,23 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"gorm.io/gorm"
 	"log/slog"
+	"prb_care_api/internal/constant"
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
+	"time"
 )
 
 type ObatService struct {
-	DB                        *gorm.DB
-	ObatRepository            *repository.ObatRepository
-	AdminApotekRepository     *repository.AdminApotekRepository
-	PengambilanObatRepository *repository.PengambilanObatRepository
-	Validator                 *validator.Validate
+	DB                           *gorm.DB
+	ObatRepository               *repository.ObatRepository
+	AdminApotekRepository        *repository.AdminApotekRepository
+	PengambilanObatRepository    *repository.PengambilanObatRepository
+	MutasiObatRepository         *repository.MutasiObatRepository
+	BatchObatRepository          *repository.BatchObatRepository
+	PeringatanStokObatRepository *repository.PeringatanStokObatRepository
+	AuditRepository              *repository.AuditRepository
+	Validator                    *validator.Validate
 }
 
 func NewObatService(
@@ -24,27 +30,41 @@ func NewObatService(
 	obatRepository *repository.ObatRepository,
 	adminApotekRepository *repository.AdminApotekRepository,
 	pengambilanObatRepository *repository.PengambilanObatRepository,
+	mutasiObatRepository *repository.MutasiObatRepository,
+	batchObatRepository *repository.BatchObatRepository,
+	peringatanStokObatRepository *repository.PeringatanStokObatRepository,
+	auditRepository *repository.AuditRepository,
 	validator *validator.Validate) *ObatService {
-	return &ObatService{db, obatRepository, adminApotekRepository, pengambilanObatRepository, validator}
+	return &ObatService{db, obatRepository, adminApotekRepository, pengambilanObatRepository, mutasiObatRepository, batchObatRepository, peringatanStokObatRepository, auditRepository, validator}
 }
 
-func (s *ObatService) List(ctx context.Context, request *model.ObatListRequest) (*[]model.ObatResponse, error) {
+func (s *ObatService) List(ctx context.Context, request *model.ObatListRequest) (*model.PageResponse[model.ObatResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	obat := new([]entity.Obat)
-
+	var result *repository.PageResult
+	var err error
 	if request.IdAdminApotek > 0 {
-		if err := s.ObatRepository.FindAllByIdAdminApotek(tx, obat, request.IdAdminApotek); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
-	} else if err := s.ObatRepository.FindAll(tx, obat); err != nil {
+		result, err = s.ObatRepository.SearchByIdAdminApotek(tx, obat, request.IdAdminApotek, pageQuery(&request.PageRequest))
+	} else {
+		result, err = s.ObatRepository.Search(tx, obat, pageQuery(&request.PageRequest))
+	}
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	idObat := make([]int32, 0, len(*obat))
+	for _, o := range *obat {
+		idObat = append(idObat, o.ID)
+	}
+	kedaluwarsa, err := s.BatchObatRepository.SumSisaKedaluwarsaByIdObat(tx, idObat, time.Now().Unix())
+	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
@@ -52,9 +72,11 @@ func (s *ObatService) List(ctx context.Context, request *model.ObatListRequest)
 	var response []model.ObatResponse
 	for _, o := range *obat {
 		response = append(response, model.ObatResponse{
-			ID:       o.ID,
-			NamaObat: o.NamaObat,
-			Jumlah:   o.Jumlah,
+			ID:             o.ID,
+			NamaObat:       o.NamaObat,
+			Jumlah:         o.Jumlah,
+			JumlahTersedia: jumlahTersedia(&o, kedaluwarsa),
+			StokMinimum:    o.StokMinimum,
 			AdminApotek: &model.AdminApotekResponse{
 				ID:               o.AdminApotek.ID,
 				NamaApotek:       o.AdminApotek.NamaApotek,
@@ -70,7 +92,7 @@ func (s *ObatService) List(ctx context.Context, request *model.ObatListRequest)
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *ObatService) Get(ctx context.Context, request *model.ObatGetRequest) (*model.ObatResponse, error) {
@@ -79,7 +101,7 @@ func (s *ObatService) Get(ctx context.Context, request *model.ObatGetRequest) (*
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	obat := new(entity.Obat)
@@ -93,6 +115,12 @@ func (s *ObatService) Get(ctx context.Context, request *model.ObatGetRequest) (*
 		return nil, fiber.ErrNotFound
 	}
 
+	kedaluwarsa, err := s.BatchObatRepository.SumSisaKedaluwarsaByIdObat(tx, []int32{obat.ID}, time.Now().Unix())
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
@@ -102,6 +130,8 @@ func (s *ObatService) Get(ctx context.Context, request *model.ObatGetRequest) (*
 	response.ID = obat.ID
 	response.NamaObat = obat.NamaObat
 	response.Jumlah = obat.Jumlah
+	response.JumlahTersedia = jumlahTersedia(obat, kedaluwarsa)
+	response.StokMinimum = obat.StokMinimum
 	response.IdAdminApotek = obat.IdAdminApotek
 
 	return response, nil
@@ -113,7 +143,7 @@ func (s *ObatService) Create(ctx context.Context, request *model.ObatCreateReque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	if err := s.AdminApotekRepository.FindById(tx, &entity.AdminApotek{}, request.IdAdminApotek); err != nil {
@@ -125,12 +155,41 @@ func (s *ObatService) Create(ctx context.Context, request *model.ObatCreateReque
 	obatEnity.NamaObat = request.NamaObat
 	obatEnity.IdAdminApotek = request.IdAdminApotek
 	obatEnity.Jumlah = request.Jumlah
+	obatEnity.StokMinimum = request.StokMinimum
 
 	if err := s.ObatRepository.Create(tx, obatEnity); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	batchObat := new(entity.BatchObat)
+	batchObat.IdObat = obatEnity.ID
+	batchObat.NoBatch = request.NoBatch
+	if batchObat.NoBatch == "" {
+		batchObat.NoBatch = constant.NoBatchTanpaNomor
+	}
+	if request.TanggalKedaluwarsa > 0 {
+		batchObat.TanggalKedaluwarsa = &request.TanggalKedaluwarsa
+	}
+	batchObat.JumlahDiterima = request.Jumlah
+	batchObat.Sisa = request.Jumlah
+	batchObat.TanggalDiterima = time.Now().Unix()
+
+	if err := s.BatchObatRepository.Create(tx, batchObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := s.MutasiObatRepository.Create(tx, mutasiObat(obatEnity, obatEnity.Jumlah, constant.AlasanMutasiObatRestok, request.Aktor, nil)); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditBuat, nil, obatEnity); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -145,7 +204,7 @@ func (s *ObatService) Update(ctx context.Context, request *model.ObatUpdateReque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	if err := s.AdminApotekRepository.FindById(tx, &entity.AdminApotek{}, request.IdAdminApotek); err != nil {
@@ -164,15 +223,21 @@ func (s *ObatService) Update(ctx context.Context, request *model.ObatUpdateReque
 		return fiber.ErrNotFound
 	}
 
+	sebelum := *obat
 	obat.IdAdminApotek = request.IdAdminApotek
 	obat.NamaObat = request.NamaObat
-	obat.Jumlah = request.Jumlah
+	obat.StokMinimum = request.StokMinimum
 
 	if err := s.ObatRepository.Update(tx, obat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, obat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -187,7 +252,7 @@ func (s *ObatService) Delete(ctx context.Context, request *model.ObatDeleteReque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	obat := new(entity.Obat)
@@ -202,10 +267,261 @@ func (s *ObatService) Delete(ctx context.Context, request *model.ObatDeleteReque
 	}
 
 	if err := s.PengambilanObatRepository.FindByIdObat(tx, &entity.PengambilanObat{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Obat masih terkait dengan data pengambilan obat yang ada")
+		return model.NewAppError(constant.KodeErrorObatTerkaitPengambilanObat)
+	}
+
+	if err := s.PeringatanStokObatRepository.DeleteByIdObat(tx, obat.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	// mutasi dan batch tetap disimpan karena riwayat stok bersifat append-only
+	sebelum := *obat
+	dihapus := time.Now().Unix()
+	obat.Dihapus = &dihapus
+
+	if err := s.ObatRepository.Update(tx, obat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditHapus, &sebelum, nil); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *ObatService) Restok(ctx context.Context, request *model.ObatRestokRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	obat := new(entity.Obat)
+	if request.IdAdminApotek > 0 {
+		if err := s.ObatRepository.FindByIdAndIdAdminApotekAndLockForUpdate(tx, obat, request.ID, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrNotFound
+		}
+	} else if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obat, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	sebelum := *obat
+	sekarang := time.Now()
+	if request.TanggalKedaluwarsa <= sekarang.Unix() {
+		return model.NewAppError(constant.KodeErrorBatchKedaluwarsa)
+	}
+
+	batchObat := new(entity.BatchObat)
+	if err := s.BatchObatRepository.FindByIdObatAndNoBatchAndLockForUpdate(tx, batchObat, obat.ID, request.NoBatch); err == nil {
+		if batchObat.TanggalKedaluwarsa == nil || *batchObat.TanggalKedaluwarsa != request.TanggalKedaluwarsa {
+			return model.NewAppError(constant.KodeErrorBatchBerbeda)
+		}
+		batchObat.JumlahDiterima += request.Jumlah
+		batchObat.Sisa += request.Jumlah
+		if err := s.BatchObatRepository.Update(tx, batchObat); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	} else {
+		batchObat.IdObat = obat.ID
+		batchObat.NoBatch = request.NoBatch
+		batchObat.TanggalKedaluwarsa = &request.TanggalKedaluwarsa
+		batchObat.JumlahDiterima = request.Jumlah
+		batchObat.Sisa = request.Jumlah
+		batchObat.TanggalDiterima = sekarang.Unix()
+		if err := s.BatchObatRepository.Create(tx, batchObat); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	obat.Jumlah += request.Jumlah
+
+	if err := s.ObatRepository.Update(tx, obat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := s.MutasiObatRepository.Create(tx, mutasiObat(obat, request.Jumlah, constant.AlasanMutasiObatRestok, request.Aktor, nil)); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditRestok, &sebelum, obat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *ObatService) Mutasi(ctx context.Context, request *model.MutasiObatGetRequest) (*model.MutasiObatListResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	obat := new(entity.Obat)
+	if request.IdAdminApotek > 0 {
+		if err := s.ObatRepository.FindByIdAndIdAdminApotek(tx, obat, request.IdObat, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	} else if err := s.ObatRepository.FindById(tx, obat, request.IdObat); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	mutasi := new([]entity.MutasiObat)
+	if err := s.MutasiObatRepository.FindAllByIdObat(tx, mutasi, obat.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	total, err := s.MutasiObatRepository.SumPerubahanByIdObat(tx, obat.ID)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := new(model.MutasiObatListResponse)
+	response.IdObat = obat.ID
+	response.Jumlah = obat.Jumlah
+	response.TotalMutasi = total
+	response.Sesuai = total == int64(obat.Jumlah)
+	response.Mutasi = make([]model.MutasiObatResponse, 0, len(*mutasi))
+	for _, m := range *mutasi {
+		response.Mutasi = append(response.Mutasi, model.MutasiObatResponse{
+			ID:                m.ID,
+			IdPengambilanObat: m.IdPengambilanObat,
+			Perubahan:         m.Perubahan,
+			JumlahAkhir:       m.JumlahAkhir,
+			Alasan:            m.Alasan,
+			IdAktor:           m.IdAktor,
+			RoleAktor:         m.RoleAktor,
+			Waktu:             m.Waktu,
+		})
+	}
+	return response, nil
+}
+
+func (s *ObatService) Batch(ctx context.Context, request *model.BatchObatListRequest) (*[]model.BatchObatResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	if request.IdAdminApotek > 0 {
+		if err := s.ObatRepository.FindByIdAndIdAdminApotek(tx, &entity.Obat{}, request.IdObat, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	} else if err := s.ObatRepository.FindById(tx, &entity.Obat{}, request.IdObat); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	batchObat := new([]entity.BatchObat)
+	if err := s.BatchObatRepository.FindAllByIdObat(tx, batchObat, request.IdObat); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
 	}
 
-	if err := s.ObatRepository.Delete(tx, obat); err != nil {
+	response := make([]model.BatchObatResponse, 0, len(*batchObat))
+	for _, b := range *batchObat {
+		response = append(response, batchObatResponse(b, nil))
+	}
+	return &response, nil
+}
+
+func (s *ObatService) KoreksiBatch(ctx context.Context, request *model.BatchObatKoreksiRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	obat := new(entity.Obat)
+	if request.IdAdminApotek > 0 {
+		if err := s.ObatRepository.FindByIdAndIdAdminApotekAndLockForUpdate(tx, obat, request.IdObat, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrNotFound
+		}
+	} else if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obat, request.IdObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	batchObat := new(entity.BatchObat)
+	if err := s.BatchObatRepository.FindByIdAndIdObatAndLockForUpdate(tx, batchObat, request.ID, obat.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	perubahan := request.Sisa - batchObat.Sisa
+	if perubahan == 0 {
+		return nil
+	}
+	sebelum := *batchObat
+	batchObat.Sisa = request.Sisa
+	obat.Jumlah += perubahan
+
+	if err := s.BatchObatRepository.Update(tx, batchObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := s.ObatRepository.Update(tx, obat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := s.MutasiObatRepository.Create(tx, mutasiObat(obat, perubahan, constant.AlasanMutasiObatKoreksi, request.Aktor, nil)); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatPeringatanStok(tx, s.PeringatanStokObatRepository, obat, perubahan); err != nil {
+		return err
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditKoreksi, &sebelum, batchObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
@@ -217,3 +533,164 @@ func (s *ObatService) Delete(ctx context.Context, request *model.ObatDeleteReque
 
 	return nil
 }
+
+func (s *ObatService) Kedaluwarsa(ctx context.Context, request *model.BatchObatKedaluwarsaRequest) (*[]model.BatchObatResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	batas := time.Now().AddDate(0, 0, int(request.Hari)).Unix()
+	batchObat := new([]entity.BatchObat)
+	if request.IdAdminApotek > 0 {
+		if err := s.BatchObatRepository.FindAllKedaluwarsaByIdAdminApotek(tx, batchObat, batas, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	} else if err := s.BatchObatRepository.FindAllKedaluwarsa(tx, batchObat, batas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]model.BatchObatResponse, 0, len(*batchObat))
+	for _, b := range *batchObat {
+		obat := &model.ObatResponse{
+			ID:       b.Obat.ID,
+			NamaObat: b.Obat.NamaObat,
+			Jumlah:   b.Obat.Jumlah,
+		}
+		if request.IdAdminApotek == 0 {
+			obat.AdminApotek = &model.AdminApotekResponse{
+				ID:         b.Obat.AdminApotek.ID,
+				NamaApotek: b.Obat.AdminApotek.NamaApotek,
+				Telepon:    b.Obat.AdminApotek.Telepon,
+			}
+		}
+		response = append(response, batchObatResponse(b, obat))
+	}
+	return &response, nil
+}
+
+func (s *ObatService) Peringatan(ctx context.Context, request *model.PeringatanStokObatListRequest) (*[]model.PeringatanStokObatResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	peringatan := new([]entity.PeringatanStokObat)
+	if request.IdAdminApotek > 0 {
+		if err := s.PeringatanStokObatRepository.FindAllByIdAdminApotek(tx, peringatan, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	} else if request.IdAdminPuskesmas > 0 {
+		if err := s.PeringatanStokObatRepository.FindAllByIdAdminPuskesmas(tx, peringatan, request.IdAdminPuskesmas); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+	} else if err := s.PeringatanStokObatRepository.FindAll(tx, peringatan); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]model.PeringatanStokObatResponse, 0, len(*peringatan))
+	for _, p := range *peringatan {
+		obat := &model.ObatResponse{
+			ID:          p.Obat.ID,
+			NamaObat:    p.Obat.NamaObat,
+			Jumlah:      p.Obat.Jumlah,
+			StokMinimum: p.Obat.StokMinimum,
+		}
+		if request.IdAdminApotek == 0 {
+			obat.AdminApotek = &model.AdminApotekResponse{
+				ID:         p.Obat.AdminApotek.ID,
+				NamaApotek: p.Obat.AdminApotek.NamaApotek,
+				Telepon:    p.Obat.AdminApotek.Telepon,
+			}
+		}
+		response = append(response, model.PeringatanStokObatResponse{
+			ID:          p.ID,
+			Obat:        obat,
+			Jumlah:      p.Jumlah,
+			StokMinimum: p.StokMinimum,
+			Waktu:       p.Waktu,
+		})
+	}
+	return &response, nil
+}
+
+func batchObatResponse(b entity.BatchObat, obat *model.ObatResponse) model.BatchObatResponse {
+	return model.BatchObatResponse{
+		ID:                 b.ID,
+		IdObat:             b.IdObat,
+		Obat:               obat,
+		NoBatch:            b.NoBatch,
+		TanggalKedaluwarsa: b.TanggalKedaluwarsa,
+		JumlahDiterima:     b.JumlahDiterima,
+		Sisa:               b.Sisa,
+		TanggalDiterima:    b.TanggalDiterima,
+	}
+}
+
+// sisa batch kedaluwarsa masih tercatat pada jumlah obat tetapi tidak dapat dialokasikan ke pengambilan obat
+func jumlahTersedia(obat *entity.Obat, kedaluwarsa map[int32]int32) *int32 {
+	jumlah := max(obat.Jumlah-kedaluwarsa[obat.ID], 0)
+	return &jumlah
+}
+
+func mutasiObat(obat *entity.Obat, perubahan int32, alasan string, aktor *model.Auth, idPengambilanObat *int32) *entity.MutasiObat {
+	mutasi := &entity.MutasiObat{
+		IdObat:            obat.ID,
+		IdPengambilanObat: idPengambilanObat,
+		Perubahan:         perubahan,
+		JumlahAkhir:       obat.Jumlah,
+		Alasan:            alasan,
+		Waktu:             time.Now().Unix(),
+	}
+	if aktor != nil {
+		mutasi.IdAktor = &aktor.ID
+		mutasi.RoleAktor = &aktor.Role
+	}
+	return mutasi
+}
+
+func catatPeringatanStok(tx *gorm.DB, peringatanStokObatRepository *repository.PeringatanStokObatRepository, obat *entity.Obat, perubahan int32) error {
+	// peringatan hanya dibuat saat stok turun melewati batas minimum
+	if obat.StokMinimum == 0 || perubahan >= 0 || obat.Jumlah >= obat.StokMinimum || obat.Jumlah-perubahan < obat.StokMinimum {
+		return nil
+	}
+
+	peringatan := &entity.PeringatanStokObat{
+		IdObat:      obat.ID,
+		Jumlah:      obat.Jumlah,
+		StokMinimum: obat.StokMinimum,
+		Waktu:       time.Now().Unix(),
+	}
+	if err := peringatanStokObatRepository.Create(tx, peringatan); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	// teruskan ke puskesmas yang masih memiliki pengambilan obat menunggu untuk obat ini
+	if err := peringatanStokObatRepository.CreatePenerimaByIdObatAndStatusPengambilanObat(tx, peringatan.ID, obat.ID, constant.StatusPengambilanObatMenunggu); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
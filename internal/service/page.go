@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v3"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+)
+
+func pageQuery(request *model.PageRequest) *repository.PageQuery {
+	return &repository.PageQuery{
+		Page:   request.Page,
+		Limit:  request.Limit,
+		Cursor: request.Cursor,
+		Sort:   request.Sort,
+		Order:  request.Order,
+		Cari:   request.Cari,
+		Dari:   request.TanggalMulai,
+		Sampai: request.TanggalSelesai,
+	}
+}
+
+func pageResponse[T any](request *model.PageRequest, result *repository.PageResult, data []T) *model.PageResponse[T] {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = repository.PageLimitDefault
+	}
+	metadata := &model.PageMetadata{
+		Limit:      limit,
+		Total:      result.Total,
+		TotalPage:  (result.Total + int64(limit) - 1) / int64(limit),
+		NextCursor: result.NextCursor,
+	}
+	// posisi halaman tidak diketahui ketika memakai cursor
+	if request.Cursor == "" {
+		metadata.Page = max(request.Page, 1)
+	}
+	return &model.PageResponse[T]{Data: data, Metadata: metadata}
+}
+
+func pageError(err error) error {
+	slog.Error(err.Error())
+	var parameterError *repository.PageParameterError
+	if errors.As(err, &parameterError) {
+		return model.NewAppError(constant.KodeErrorParameterHalaman, parameterError.Parameter)
+	}
+	if errors.Is(err, repository.ErrPageTidakValid) {
+		return fiber.ErrBadRequest
+	}
+	return fiber.ErrInternalServerError
+}
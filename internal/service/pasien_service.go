@@ -19,6 +19,9 @@ type PasienService struct {
 	PenggunaRepository        *repository.PenggunaRepository
 	KontrolBalikRepository    *repository.KontrolBalikRepository
 	PengambilanObatRepository *repository.PengambilanObatRepository
+	TimelinePasienRepository  *repository.TimelinePasienRepository
+	AuditRepository           *repository.AuditRepository
+	OutboxRepository          *repository.OutboxRepository
 	Validator                 *validator.Validate
 }
 
@@ -29,36 +32,35 @@ func NewPasienService(
 	penggunaRepository *repository.PenggunaRepository,
 	kontrolBalikRepository *repository.KontrolBalikRepository,
 	pengambilanObatRepository *repository.PengambilanObatRepository,
+	timelinePasienRepository *repository.TimelinePasienRepository,
+	auditRepository *repository.AuditRepository,
+	outboxRepository *repository.OutboxRepository,
 	validator *validator.Validate,
 ) *PasienService {
-	return &PasienService{db, pasienRepository, adminPuskesmasRepository, penggunaRepository, kontrolBalikRepository, pengambilanObatRepository, validator}
+	return &PasienService{db, pasienRepository, adminPuskesmasRepository, penggunaRepository, kontrolBalikRepository, pengambilanObatRepository, timelinePasienRepository, auditRepository, outboxRepository, validator}
 }
 
-func (s *PasienService) Search(ctx context.Context, request *model.PasienSearchRequest) (*[]model.PasienResponse, error) {
+func (s *PasienService) Search(ctx context.Context, request *model.PasienSearchRequest) (*model.PageResponse[model.PasienResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pasien := new([]entity.Pasien)
+	var result *repository.PageResult
+	var err error
 	if request.IdPengguna > 0 {
-		if err := s.PasienRepository.SearchAsPengguna(tx, pasien, request.IdPengguna, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PasienRepository.SearchAsPengguna(tx, pasien, request.IdPengguna, request.Status, pageQuery(&request.PageRequest))
 	} else if request.IdAdminPuskesmas > 0 {
-		if err := s.PasienRepository.SearchAsAdminPuskesmas(tx, pasien, request.IdAdminPuskesmas, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PasienRepository.SearchAsAdminPuskesmas(tx, pasien, request.IdAdminPuskesmas, request.Status, pageQuery(&request.PageRequest))
 	} else {
-		if err := s.PasienRepository.Search(tx, pasien, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PasienRepository.Search(tx, pasien, request.Status, pageQuery(&request.PageRequest))
+	}
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.PasienResponse
@@ -90,7 +92,7 @@ func (s *PasienService) Search(ctx context.Context, request *model.PasienSearchR
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *PasienService) Get(ctx context.Context, request *model.PasienGetRequest) (*model.PasienResponse, error) {
@@ -99,7 +101,7 @@ func (s *PasienService) Get(ctx context.Context, request *model.PasienGetRequest
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pasien := new(entity.Pasien)
@@ -128,13 +130,124 @@ func (s *PasienService) Get(ctx context.Context, request *model.PasienGetRequest
 	return response, nil
 }
 
+func (s *PasienService) Timeline(ctx context.Context, request *model.PasienTimelineRequest) (*model.PageResponse[model.PasienTimelineResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pasien := new(entity.Pasien)
+	if err := findPasienMilik(tx, s.PasienRepository, pasien, request.ID, request.IdPengguna, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	timeline := new([]entity.TimelinePasien)
+	result, err := s.TimelinePasienRepository.SearchByIdPasien(tx, timeline, pasien.ID, request.Jenis, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var idKontrolBalik, idPengambilanObat []int32
+	for _, t := range *timeline {
+		if t.Jenis == constant.JenisTimelineKontrolBalik {
+			idKontrolBalik = append(idKontrolBalik, t.IdReferensi)
+		} else {
+			idPengambilanObat = append(idPengambilanObat, t.IdReferensi)
+		}
+	}
+
+	kontrolBalik := make(map[int32]entity.KontrolBalik, len(idKontrolBalik))
+	if len(idKontrolBalik) > 0 {
+		data := new([]entity.KontrolBalik)
+		if err := s.KontrolBalikRepository.FindAllById(tx, data, idKontrolBalik); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		for _, k := range *data {
+			kontrolBalik[k.ID] = k
+		}
+	}
+	pengambilanObat := make(map[int32]entity.PengambilanObat, len(idPengambilanObat))
+	if len(idPengambilanObat) > 0 {
+		data := new([]entity.PengambilanObat)
+		if err := s.PengambilanObatRepository.FindAllById(tx, data, idPengambilanObat); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		for _, p := range *data {
+			pengambilanObat[p.ID] = p
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var response []model.PasienTimelineResponse
+	for _, t := range *timeline {
+		item := model.PasienTimelineResponse{
+			Jenis:   t.Jenis,
+			Tanggal: t.Tanggal,
+			Status:  t.Status,
+		}
+		if k, ok := kontrolBalik[t.IdReferensi]; ok && t.Jenis == constant.JenisTimelineKontrolBalik {
+			item.KontrolBalik = &model.KontrolBalikResponse{
+				ID:                    k.ID,
+				NoAntrean:             k.NoAntrean,
+				IdPasien:              k.IdPasien,
+				Keluhan:               k.Keluhan,
+				BeratBadan:            k.BeratBadan,
+				TinggiBadan:           k.TinggiBadan,
+				TekananDarah:          tekananDarah(k.TekananDarahSistolik, k.TekananDarahDiastolik),
+				TekananDarahSistolik:  k.TekananDarahSistolik,
+				TekananDarahDiastolik: k.TekananDarahDiastolik,
+				TekananDarahLama:      k.TekananDarahLama,
+				Imt:                   imt(k.BeratBadan, k.TinggiBadan),
+				DenyutNadi:            k.DenyutNadi,
+				HasilLab:              k.HasilLab,
+				HasilEkg:              k.HasilEkg,
+				HasilDiagnosa:         k.HasilDiagnosa,
+				TanggalKontrol:        k.TanggalKontrol,
+				WaktuKontrol:          k.WaktuKontrol,
+				Status:                k.Status,
+			}
+		}
+		if p, ok := pengambilanObat[t.IdReferensi]; ok && t.Jenis == constant.JenisTimelinePengambilanObat {
+			item.PengambilanObat = &model.PengambilanObatResponse{
+				ID:            p.ID,
+				Resi:          p.Resi,
+				IdPasien:      p.IdPasien,
+				IdAdminApotek: p.IdAdminApotek,
+				AdminApotek: &model.AdminApotekResponse{
+					ID:               p.AdminApotek.ID,
+					NamaApotek:       p.AdminApotek.NamaApotek,
+					Telepon:          p.AdminApotek.Telepon,
+					Alamat:           p.AdminApotek.Alamat,
+					WaktuOperasional: p.AdminApotek.WaktuOperasional,
+				},
+				Obat:               pengambilanObatItemResponse(p.Item),
+				TanggalPengambilan: p.TanggalPengambilan,
+				Status:             p.Status,
+			}
+		}
+		response = append(response, item)
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
 func (s *PasienService) Create(ctx context.Context, request *model.PasienCreateRequest) error {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	if err := s.AdminPuskesmasRepository.FindById(tx, &entity.AdminPuskesmas{}, request.IdAdminPuskesmas); err != nil {
@@ -159,6 +272,11 @@ func (s *PasienService) Create(ctx context.Context, request *model.PasienCreateR
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditBuat, nil, pasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -173,7 +291,7 @@ func (s *PasienService) Update(ctx context.Context, request *model.PasienUpdateR
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pasien := new(entity.Pasien)
@@ -196,6 +314,7 @@ func (s *PasienService) Update(ctx context.Context, request *model.PasienUpdateR
 		return fiber.ErrNotFound
 	}
 
+	sebelum := *pasien
 	pasien.NoRekamMedis = request.NoRekamMedis
 	pasien.IdPengguna = request.IdPengguna
 	pasien.IdAdminPuskesmas = request.IdAdminPuskesmas
@@ -206,6 +325,11 @@ func (s *PasienService) Update(ctx context.Context, request *model.PasienUpdateR
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, pasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -220,7 +344,7 @@ func (s *PasienService) Selesai(ctx context.Context, request *model.PasienSelesa
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 	pasien := new(entity.Pasien)
 	if request.IdAdminPuskesmas > 0 {
@@ -234,13 +358,14 @@ func (s *PasienService) Selesai(ctx context.Context, request *model.PasienSelesa
 	}
 
 	if err := s.KontrolBalikRepository.FindByIdPasienAndStatus(tx, &entity.KontrolBalik{}, request.ID, constant.StatusKontrolBalikMenunggu); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Pasien masih memiliki kontrol balik yang harus dilakukan")
+		return model.NewAppError(constant.KodeErrorPasienKontrolBalikAktif)
 	}
 
 	if err := s.PengambilanObatRepository.FindByIdPasienAndStatus(tx, &entity.PengambilanObat{}, request.ID, constant.StatusKontrolBalikMenunggu); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Pasien masih memiliki pengambilan obat yang harus dilakukan")
+		return model.NewAppError(constant.KodeErrorPasienPengambilanObatAktif)
 	}
 
+	sebelum := *pasien
 	pasien.Status = constant.StatusPasienSelesai
 
 	if err := s.PasienRepository.Update(tx, pasien); err != nil {
@@ -248,6 +373,16 @@ func (s *PasienService) Selesai(ctx context.Context, request *model.PasienSelesa
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditSelesai, &sebelum, pasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventPasien(tx, s.OutboxRepository, constant.EventPasienSelesai, pasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -263,7 +398,7 @@ func (s *PasienService) Delete(ctx context.Context, request *model.PasienDeleteR
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pasien := new(entity.Pasien)
@@ -278,10 +413,10 @@ func (s *PasienService) Delete(ctx context.Context, request *model.PasienDeleteR
 	}
 
 	if err := s.KontrolBalikRepository.FindByIdPasien(tx, &entity.KontrolBalik{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Pasien masih terkait dengan data kontrol balik yang ada")
+		return model.NewAppError(constant.KodeErrorPasienTerkaitKontrolBalik)
 	}
 	if err := s.PengambilanObatRepository.FindByIdPasien(tx, &entity.PengambilanObat{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Pasien masih terkait dengan data pengambilan obat yang ada")
+		return model.NewAppError(constant.KodeErrorPasienTerkaitPengambilanObat)
 	}
 
 	if err := s.PasienRepository.Delete(tx, pasien); err != nil {
@@ -289,6 +424,11 @@ func (s *PasienService) Delete(ctx context.Context, request *model.PasienDeleteR
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditHapus, pasien, nil); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -296,3 +436,14 @@ func (s *PasienService) Delete(ctx context.Context, request *model.PasienDeleteR
 
 	return nil
 }
+
+// riwayat pasien yang sudah selesai tetap dapat dibuka, sehingga status pasien tidak dibatasi
+func findPasienMilik(db *gorm.DB, pasienRepository *repository.PasienRepository, pasien *entity.Pasien, id int32, idPengguna int32, idAdminPuskesmas int32) error {
+	if idPengguna > 0 {
+		return pasienRepository.FindByIdAndIdPengguna(db, pasien, id, idPengguna)
+	}
+	if idAdminPuskesmas > 0 {
+		return pasienRepository.FindByIdAndIdAdminPuskesmas(db, pasien, id, idAdminPuskesmas)
+	}
+	return pasienRepository.FindById(db, pasien, id)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+)
+
+type PemeriksaanLabService struct {
+	DB                            *gorm.DB
+	PemeriksaanLabRepository      *repository.PemeriksaanLabRepository
+	HasilPemeriksaanLabRepository *repository.HasilPemeriksaanLabRepository
+	AuditRepository               *repository.AuditRepository
+	Validator                     *validator.Validate
+}
+
+func NewPemeriksaanLabService(
+	db *gorm.DB,
+	pemeriksaanLabRepository *repository.PemeriksaanLabRepository,
+	hasilPemeriksaanLabRepository *repository.HasilPemeriksaanLabRepository,
+	auditRepository *repository.AuditRepository,
+	validator *validator.Validate,
+) *PemeriksaanLabService {
+	return &PemeriksaanLabService{db, pemeriksaanLabRepository, hasilPemeriksaanLabRepository, auditRepository, validator}
+}
+
+func (s *PemeriksaanLabService) List(ctx context.Context, request *model.PemeriksaanLabListRequest) (*model.PageResponse[model.PemeriksaanLabResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pemeriksaanLab := new([]entity.PemeriksaanLab)
+	result, err := s.PemeriksaanLabRepository.Search(tx, pemeriksaanLab, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.PemeriksaanLabResponse
+	for _, p := range *pemeriksaanLab {
+		response = append(response, *pemeriksaanLabResponse(&p))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+func (s *PemeriksaanLabService) Get(ctx context.Context, request *model.PemeriksaanLabGetRequest) (*model.PemeriksaanLabResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pemeriksaanLab := new(entity.PemeriksaanLab)
+	if err := s.PemeriksaanLabRepository.FindById(tx, pemeriksaanLab, request.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pemeriksaanLabResponse(pemeriksaanLab), nil
+}
+
+func (s *PemeriksaanLabService) Create(ctx context.Context, request *model.PemeriksaanLabCreateRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	if !rentangRujukanValid(request.NilaiMin, request.NilaiMaks) {
+		return model.NewAppError(constant.KodeErrorRentangRujukan)
+	}
+
+	total, err := s.PemeriksaanLabRepository.CountByKode(tx, request.Kode)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if total > 0 {
+		return model.NewAppError(constant.KodeErrorKodePemeriksaanLabDigunakan)
+	}
+
+	pemeriksaanLab := new(entity.PemeriksaanLab)
+	pemeriksaanLab.Kode = request.Kode
+	pemeriksaanLab.Nama = request.Nama
+	pemeriksaanLab.Satuan = request.Satuan
+	pemeriksaanLab.NilaiMin = request.NilaiMin
+	pemeriksaanLab.NilaiMaks = request.NilaiMaks
+
+	if err := s.PemeriksaanLabRepository.Create(tx, pemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditBuat, nil, pemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// perubahan rentang rujukan hanya berlaku untuk hasil yang disimpan setelahnya
+func (s *PemeriksaanLabService) Update(ctx context.Context, request *model.PemeriksaanLabUpdateRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	if !rentangRujukanValid(request.NilaiMin, request.NilaiMaks) {
+		return model.NewAppError(constant.KodeErrorRentangRujukan)
+	}
+
+	pemeriksaanLab := new(entity.PemeriksaanLab)
+	if err := s.PemeriksaanLabRepository.FindById(tx, pemeriksaanLab, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	total, err := s.PemeriksaanLabRepository.CountByKode(tx, request.Kode)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if total > 0 && pemeriksaanLab.Kode != request.Kode {
+		return model.NewAppError(constant.KodeErrorKodePemeriksaanLabDigunakan)
+	}
+
+	sebelum := *pemeriksaanLab
+	pemeriksaanLab.Kode = request.Kode
+	pemeriksaanLab.Nama = request.Nama
+	pemeriksaanLab.Satuan = request.Satuan
+	pemeriksaanLab.NilaiMin = request.NilaiMin
+	pemeriksaanLab.NilaiMaks = request.NilaiMaks
+
+	if err := s.PemeriksaanLabRepository.Update(tx, pemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, pemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *PemeriksaanLabService) Delete(ctx context.Context, request *model.PemeriksaanLabDeleteRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	pemeriksaanLab := new(entity.PemeriksaanLab)
+	if err := s.PemeriksaanLabRepository.FindById(tx, pemeriksaanLab, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	if err := s.HasilPemeriksaanLabRepository.FindByIdPemeriksaanLab(tx, &entity.HasilPemeriksaanLab{}, pemeriksaanLab.ID); err == nil {
+		return model.NewAppError(constant.KodeErrorPemeriksaanLabTerkaitHasil)
+	}
+
+	if err := s.PemeriksaanLabRepository.Delete(tx, pemeriksaanLab); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditHapus, pemeriksaanLab, nil); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func pemeriksaanLabResponse(pemeriksaanLab *entity.PemeriksaanLab) *model.PemeriksaanLabResponse {
+	return &model.PemeriksaanLabResponse{
+		ID:        pemeriksaanLab.ID,
+		Kode:      pemeriksaanLab.Kode,
+		Nama:      pemeriksaanLab.Nama,
+		Satuan:    pemeriksaanLab.Satuan,
+		NilaiMin:  pemeriksaanLab.NilaiMin,
+		NilaiMaks: pemeriksaanLab.NilaiMaks,
+	}
+}
+
+// rentang boleh terbuka di salah satu sisi, misalnya HDL yang hanya memiliki batas bawah
+func rentangRujukanValid(nilaiMin *float64, nilaiMaks *float64) bool {
+	return nilaiMin == nil || nilaiMaks == nil || *nilaiMaks > *nilaiMin
+}
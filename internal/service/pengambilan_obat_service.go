@@ -2,108 +2,88 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
 	"gorm.io/gorm"
 	"log/slog"
+	"maps"
+	"prb_care_api/internal/adapter"
 	"prb_care_api/internal/constant"
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
+	"slices"
+	"time"
 )
 
 type PengambilanObatService struct {
-	DB                        *gorm.DB
-	PengambilanObatRepository *repository.PengambilanObatRepository
-	PasienRepository          *repository.PasienRepository
-	ObatRepository            *repository.ObatRepository
-	Validator                 *validator.Validate
+	DB                            *gorm.DB
+	PengambilanObatRepository     *repository.PengambilanObatRepository
+	PengambilanObatItemRepository *repository.PengambilanObatItemRepository
+	PasienRepository              *repository.PasienRepository
+	ObatRepository                *repository.ObatRepository
+	MutasiObatRepository          *repository.MutasiObatRepository
+	BatchObatRepository           *repository.BatchObatRepository
+	AlokasiBatchObatRepository    *repository.AlokasiBatchObatRepository
+	PeringatanStokObatRepository  *repository.PeringatanStokObatRepository
+	ResiAdapter                   *adapter.ResiAdapter
+	QrCodeAdapter                 *adapter.QrCodeAdapter
+	AuditRepository               *repository.AuditRepository
+	NotifikasiRepository          *repository.NotifikasiRepository
+	OutboxRepository              *repository.OutboxRepository
+	Validator                     *validator.Validate
 }
 
 func NewPengambilanObatService(
 	db *gorm.DB,
 	pengambilanObatRepository *repository.PengambilanObatRepository,
+	pengambilanObatItemRepository *repository.PengambilanObatItemRepository,
 	pasienRepository *repository.PasienRepository,
 	obatRepository *repository.ObatRepository,
+	mutasiObatRepository *repository.MutasiObatRepository,
+	batchObatRepository *repository.BatchObatRepository,
+	alokasiBatchObatRepository *repository.AlokasiBatchObatRepository,
+	peringatanStokObatRepository *repository.PeringatanStokObatRepository,
+	resiAdapter *adapter.ResiAdapter,
+	qrCodeAdapter *adapter.QrCodeAdapter,
+	auditRepository *repository.AuditRepository,
+	notifikasiRepository *repository.NotifikasiRepository,
+	outboxRepository *repository.OutboxRepository,
 	validator *validator.Validate,
 ) *PengambilanObatService {
-	return &PengambilanObatService{db, pengambilanObatRepository, pasienRepository, obatRepository, validator}
+	return &PengambilanObatService{db, pengambilanObatRepository, pengambilanObatItemRepository, pasienRepository, obatRepository, mutasiObatRepository, batchObatRepository, alokasiBatchObatRepository, peringatanStokObatRepository, resiAdapter, qrCodeAdapter, auditRepository, notifikasiRepository, outboxRepository, validator}
 }
 
-func (s *PengambilanObatService) Search(ctx context.Context, request *model.PengambilanObatSearchRequest) (*[]model.PengambilanObatResponse, error) {
+func (s *PengambilanObatService) Search(ctx context.Context, request *model.PengambilanObatSearchRequest) (*model.PageResponse[model.PengambilanObatResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pengambilanObat := new([]entity.PengambilanObat)
+	var result *repository.PageResult
+	var err error
 	if request.IdPengguna > 0 {
-		if err := s.PengambilanObatRepository.SearchAsPengguna(tx, pengambilanObat, request.IdPengguna, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PengambilanObatRepository.SearchAsPengguna(tx, pengambilanObat, request.IdPengguna, request.Status, pageQuery(&request.PageRequest))
 	} else if request.IdAdminPuskesmas > 0 {
-		if err := s.PengambilanObatRepository.SearchAsAdminPuskesmas(tx, pengambilanObat, request.IdAdminPuskesmas, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PengambilanObatRepository.SearchAsAdminPuskesmas(tx, pengambilanObat, request.IdAdminPuskesmas, request.Status, pageQuery(&request.PageRequest))
 	} else if request.IdAdminApotek > 0 {
-		if err := s.PengambilanObatRepository.SearchAsAdminApotek(tx, pengambilanObat, request.IdAdminApotek, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PengambilanObatRepository.SearchAsAdminApotek(tx, pengambilanObat, request.IdAdminApotek, request.Status, pageQuery(&request.PageRequest))
 	} else {
-		if err := s.PengambilanObatRepository.Search(tx, pengambilanObat, request.Status); err != nil {
-			slog.Error(err.Error())
-			return nil, fiber.ErrInternalServerError
-		}
+		result, err = s.PengambilanObatRepository.Search(tx, pengambilanObat, request.Status, pageQuery(&request.PageRequest))
+	}
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.PengambilanObatResponse
 	for _, p := range *pengambilanObat {
-		response = append(response, model.PengambilanObatResponse{
-			ID:   p.ID,
-			Resi: p.Resi,
-			PasienResponse: &model.PasienResponse{
-				ID:           p.Pasien.ID,
-				NoRekamMedis: p.Pasien.NoRekamMedis,
-				Pengguna: &model.PenggunaResponse{
-					ID:              p.Pasien.Pengguna.ID,
-					NamaLengkap:     p.Pasien.Pengguna.NamaLengkap,
-					Telepon:         p.Pasien.Pengguna.Telepon,
-					TeleponKeluarga: p.Pasien.Pengguna.TeleponKeluarga,
-					Alamat:          p.Pasien.Pengguna.Alamat,
-				},
-				AdminPuskesmas: &model.AdminPuskesmasResponse{
-					ID:               p.Pasien.AdminPuskesmas.ID,
-					NamaPuskesmas:    p.Pasien.AdminPuskesmas.NamaPuskesmas,
-					Telepon:          p.Pasien.AdminPuskesmas.Telepon,
-					Alamat:           p.Pasien.AdminPuskesmas.Alamat,
-					WaktuOperasional: p.Pasien.AdminPuskesmas.WaktuOperasional,
-				},
-				TanggalDaftar: p.Pasien.TanggalDaftar,
-				Status:        p.Pasien.Status,
-			},
-			Obat: &model.ObatResponse{
-				ID:       p.Obat.ID,
-				NamaObat: p.Obat.NamaObat,
-				Jumlah:   p.Obat.Jumlah,
-				AdminApotek: &model.AdminApotekResponse{
-					ID:               p.Obat.AdminApotek.ID,
-					NamaApotek:       p.Obat.AdminApotek.NamaApotek,
-					Telepon:          p.Obat.AdminApotek.Telepon,
-					Alamat:           p.Obat.AdminApotek.Alamat,
-					WaktuOperasional: p.Obat.AdminApotek.WaktuOperasional,
-				},
-			},
-			Jumlah:             p.Jumlah,
-			TanggalPengambilan: p.TanggalPengambilan,
-			Status:             p.Status,
-		})
+		response = append(response, pengambilanObatResponse(p))
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -111,7 +91,7 @@ func (s *PengambilanObatService) Search(ctx context.Context, request *model.Peng
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *PengambilanObatService) Get(ctx context.Context, request *model.PengambilanObatGetRequest) (*model.PengambilanObatResponse, error) {
@@ -120,7 +100,7 @@ func (s *PengambilanObatService) Get(ctx context.Context, request *model.Pengamb
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
@@ -134,6 +114,11 @@ func (s *PengambilanObatService) Get(ctx context.Context, request *model.Pengamb
 		return nil, fiber.ErrNotFound
 	}
 
+	if err := s.PengambilanObatItemRepository.FindAllByIdPengambilanObat(tx, &pengambilanObat.Item, pengambilanObat.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
@@ -141,9 +126,9 @@ func (s *PengambilanObatService) Get(ctx context.Context, request *model.Pengamb
 
 	response := new(model.PengambilanObatResponse)
 	response.ID = pengambilanObat.ID
-	response.IdObat = pengambilanObat.IdObat
 	response.IdPasien = pengambilanObat.IdPasien
-	response.Jumlah = pengambilanObat.Jumlah
+	response.IdAdminApotek = pengambilanObat.IdAdminApotek
+	response.Obat = pengambilanObatItemResponse(pengambilanObat.Item)
 	response.TanggalPengambilan = pengambilanObat.TanggalPengambilan
 	return response, nil
 }
@@ -154,45 +139,71 @@ func (s *PengambilanObatService) Create(ctx context.Context, request *model.Peng
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
+	pasien := new(entity.Pasien)
 	if request.IdAdminPuskesmas > 0 {
-		if err := s.PasienRepository.FindByIdAndIdAdminPuskesmasAndStatus(tx, &entity.Pasien{}, request.IdPasien, request.IdAdminPuskesmas, constant.StatusPasienAktif); err != nil {
+		if err := s.PasienRepository.FindByIdAndIdAdminPuskesmasAndStatus(tx, pasien, request.IdPasien, request.IdAdminPuskesmas, constant.StatusPasienAktif); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	} else {
-		if err := s.PasienRepository.FindByIdAndStatus(tx, &entity.Pasien{}, request.IdPasien, constant.StatusPasienAktif); err != nil {
+		if err := s.PasienRepository.FindByIdAndStatus(tx, pasien, request.IdPasien, constant.StatusPasienAktif); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	}
 
-	obat := new(entity.Obat)
-	if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obat, request.IdObat); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrNotFound
+	perubahan := make(map[int32]int32)
+	for _, item := range request.Obat {
+		perubahan[item.IdObat] -= item.Jumlah
 	}
-	obat.Jumlah -= request.Jumlah
-	if obat.Jumlah < 0 {
-		return fiber.NewError(fiber.StatusConflict, "Jumlah obat melebihi persediaan apotek")
+	obat, err := s.reserveObat(tx, perubahan)
+	if err != nil {
+		return err
+	}
+	idAdminApotek, err := idAdminApotekObat(obat)
+	if err != nil {
+		return err
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
-	pengambilanObat.Resi = fmt.Sprintf("%d%d%d", request.IdPasien, request.TanggalPengambilan, obat.IdAdminApotek)
+	resi, err := s.generateResi(tx)
+	if err != nil {
+		return err
+	}
+	pengambilanObat.Resi = resi
 	pengambilanObat.IdPasien = request.IdPasien
-	pengambilanObat.IdObat = request.IdObat
-	pengambilanObat.Jumlah = request.Jumlah
+	pengambilanObat.IdAdminApotek = idAdminApotek
+	pengambilanObat.Item = pengambilanObatItem(request.Obat)
 	pengambilanObat.TanggalPengambilan = request.TanggalPengambilan
 	pengambilanObat.Status = constant.StatusPengambilanObatMenunggu
 
-	if err := s.ObatRepository.Update(tx, obat); err != nil {
+	if err := s.PengambilanObatRepository.Create(tx, pengambilanObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
-	if err := s.PengambilanObatRepository.Create(tx, pengambilanObat); err != nil {
+	if err := s.alokasiBatch(tx, pengambilanObat.Item); err != nil {
+		return err
+	}
+
+	if err := s.catatMutasi(tx, obat, perubahan, request.Aktor, pengambilanObat.ID); err != nil {
+		return err
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditBuat, nil, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := notifikasiPengambilanObat(tx, s.NotifikasiRepository, pasien.IdPengguna, constant.JenisNotifikasiPengambilanObatDibuat, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventPengambilanObat(tx, s.OutboxRepository, constant.EventPengambilanObatDibuat, pengambilanObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
@@ -211,17 +222,17 @@ func (s *PengambilanObatService) Update(ctx context.Context, request *model.Peng
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
 	if request.IdAdminPuskesmas > 0 {
-		if err := s.PengambilanObatRepository.FindByIdAndIdAdminPuskesmasAndStatus(tx, pengambilanObat, request.ID, request.IdAdminPuskesmas, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndIdAdminPuskesmasAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, request.IdAdminPuskesmas, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	} else {
-		if err := s.PengambilanObatRepository.FindByIdAndStatus(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
@@ -239,43 +250,48 @@ func (s *PengambilanObatService) Update(ctx context.Context, request *model.Peng
 		}
 	}
 
-	obatNew := new(entity.Obat)
-	if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obatNew, request.IdObat); err != nil {
+	itemOld := new([]entity.PengambilanObatItem)
+	if err := s.PengambilanObatItemRepository.FindAllByIdPengambilanObat(tx, itemOld, pengambilanObat.ID); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrNotFound
+		return fiber.ErrInternalServerError
 	}
 
-	obatOld := new(entity.Obat)
-	if pengambilanObat.IdObat == request.IdObat {
-		obatNew.Jumlah = (obatNew.Jumlah + pengambilanObat.Jumlah) - request.Jumlah
-		if obatNew.Jumlah < 0 {
-			return fiber.NewError(fiber.StatusConflict, "Jumlah obat melebihi persediaan apotek")
-		}
-	} else {
-		if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obatOld, pengambilanObat.IdObat); err != nil {
-			slog.Error(err.Error())
-			return fiber.ErrNotFound
-		}
-		obatOld.Jumlah += pengambilanObat.Jumlah
-		obatNew.Jumlah -= request.Jumlah
-		if obatNew.Jumlah < 0 {
-			return fiber.NewError(fiber.StatusConflict, "Jumlah obat melebihi persediaan apotek")
-		}
-		if err := s.ObatRepository.Update(tx, obatOld); err != nil {
-			slog.Error(err.Error())
-			return fiber.ErrInternalServerError
-		}
+	sebelum := *pengambilanObat
+	sebelum.Item = *itemOld
+
+	// kembalikan persediaan item lama lalu kurangi dengan item baru
+	perubahan := make(map[int32]int32)
+	for _, item := range *itemOld {
+		perubahan[item.IdObat] += item.Jumlah
+	}
+	for _, item := range request.Obat {
+		perubahan[item.IdObat] -= item.Jumlah
+	}
+	obat, err := s.reserveObat(tx, perubahan)
+	if err != nil {
+		return err
+	}
+	baru := make(map[int32]entity.Obat)
+	for _, item := range request.Obat {
+		baru[item.IdObat] = obat[item.IdObat]
+	}
+	idAdminApotek, err := idAdminApotekObat(baru)
+	if err != nil {
+		return err
+	}
+
+	if err := s.kembalikanBatch(tx, *itemOld); err != nil {
+		return err
 	}
 
-	if err := s.ObatRepository.Update(tx, obatNew); err != nil {
+	if err := s.PengambilanObatItemRepository.DeleteByIdPengambilanObat(tx, pengambilanObat.ID); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
-	pengambilanObat.Resi = fmt.Sprintf("%d%d%d", request.IdPasien, request.TanggalPengambilan, obatNew.IdAdminApotek)
 	pengambilanObat.IdPasien = request.IdPasien
-	pengambilanObat.IdObat = request.IdObat
-	pengambilanObat.Jumlah = request.Jumlah
+	pengambilanObat.IdAdminApotek = idAdminApotek
+	pengambilanObat.Item = pengambilanObatItem(request.Obat)
 	pengambilanObat.TanggalPengambilan = request.TanggalPengambilan
 
 	if err := s.PengambilanObatRepository.Update(tx, pengambilanObat); err != nil {
@@ -283,6 +299,24 @@ func (s *PengambilanObatService) Update(ctx context.Context, request *model.Peng
 		return fiber.ErrInternalServerError
 	}
 
+	if err := s.alokasiBatch(tx, pengambilanObat.Item); err != nil {
+		return err
+	}
+
+	if err := s.catatMutasi(tx, obat, perubahan, request.Aktor, pengambilanObat.ID); err != nil {
+		return err
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditUbah, &sebelum, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventPengambilanObat(tx, s.OutboxRepository, constant.EventPengambilanObatDiubah, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -297,7 +331,7 @@ func (s *PengambilanObatService) Delete(ctx context.Context, request *model.Peng
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
@@ -313,11 +347,37 @@ func (s *PengambilanObatService) Delete(ctx context.Context, request *model.Peng
 		}
 	}
 
+	item := new([]entity.PengambilanObatItem)
+	if err := s.PengambilanObatItemRepository.FindAllByIdPengambilanObat(tx, item, pengambilanObat.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	sebelum := *pengambilanObat
+	sebelum.Item = *item
+
+	if len(*item) > 0 {
+		if err := s.AlokasiBatchObatRepository.DeleteByIdPengambilanObatItem(tx, idPengambilanObatItem(*item)); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err := s.PengambilanObatItemRepository.DeleteByIdPengambilanObat(tx, pengambilanObat.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := s.PengambilanObatRepository.Delete(tx, pengambilanObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditHapus, &sebelum, nil); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -332,41 +392,117 @@ func (s *PengambilanObatService) Batal(ctx context.Context, request *model.Penga
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
 	if request.IdAdminPuskesmas > 0 {
-		if err := s.PengambilanObatRepository.FindByIdAndIdAdminPuskesmasAndStatus(tx, pengambilanObat, request.ID, request.IdAdminPuskesmas, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndIdAdminPuskesmasAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, request.IdAdminPuskesmas, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	} else {
-		if err := s.PengambilanObatRepository.FindByIdAndStatus(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	}
-	obat := new(entity.Obat)
-	if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, obat, pengambilanObat.IdObat); err != nil {
+	if err := s.batal(tx, pengambilanObat, request.Aktor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrNotFound
+		return fiber.ErrInternalServerError
 	}
-	obat.Jumlah += pengambilanObat.Jumlah
 
-	pengambilanObat.Status = constant.StatusPengambilanObatBatal
+	return nil
+}
+
+// pengambilan obat yang masih menunggu setelah tanggalnya lewat dibatalkan oleh sistem lewat jalur yang sama dengan Batal,
+// sehingga stok dan batch obat ikut dikembalikan
+func (s *PengambilanObatService) BatalTerlewat(ctx context.Context) (int, error) {
+	awal, _ := batasHari(time.Now())
+	pengambilanObat := new([]entity.PengambilanObat)
+	if err := s.PengambilanObatRepository.FindAllByTanggalPengambilanBeforeAndStatus(s.DB.WithContext(ctx), pengambilanObat, awal, constant.StatusPengambilanObatMenunggu); err != nil {
+		return 0, err
+	}
+
+	// kegagalan satu pengambilan obat tidak menghentikan yang lain
+	jumlah := 0
+	var gagal []error
+	for _, p := range *pengambilanObat {
+		if err := s.batalTerlewat(ctx, p.ID); err != nil {
+			gagal = append(gagal, fmt.Errorf("pengambilan obat %d: %w", p.ID, err))
+			continue
+		}
+		jumlah++
+	}
+	return jumlah, errors.Join(gagal...)
+}
+
+func (s *PengambilanObatService) batalTerlewat(ctx context.Context, id int32) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	pengambilanObat := new(entity.PengambilanObat)
+	if err := s.PengambilanObatRepository.FindByIdAndStatusAndLockForUpdate(tx, pengambilanObat, id, constant.StatusPengambilanObatMenunggu); err != nil {
+		return err
+	}
+	if err := s.batal(tx, pengambilanObat, nil); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
 
-	if err := s.ObatRepository.Update(tx, obat); err != nil {
+func (s *PengambilanObatService) batal(tx *gorm.DB, pengambilanObat *entity.PengambilanObat, aktor *model.Auth) error {
+	item := new([]entity.PengambilanObatItem)
+	if err := s.PengambilanObatItemRepository.FindAllByIdPengambilanObat(tx, item, pengambilanObat.ID); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
+	perubahan := make(map[int32]int32)
+	for _, i := range *item {
+		perubahan[i.IdObat] += i.Jumlah
+	}
+	obat, err := s.reserveObat(tx, perubahan)
+	if err != nil {
+		return err
+	}
+
+	if err := s.kembalikanBatch(tx, *item); err != nil {
+		return err
+	}
+
+	sebelum := *pengambilanObat
+	pengambilanObat.Status = constant.StatusPengambilanObatBatal
+
 	if err := s.PengambilanObatRepository.Update(tx, pengambilanObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 
-	if err := tx.Commit().Error; err != nil {
+	if err := s.catatMutasi(tx, obat, perubahan, aktor, pengambilanObat.ID); err != nil {
+		return err
+	}
+
+	if err := catatAudit(tx, s.AuditRepository, aktor, constant.AksiAuditBatal, &sebelum, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	pasien := new(entity.Pasien)
+	if err := s.PasienRepository.FindById(tx, pasien, pengambilanObat.IdPasien); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if err := notifikasiPengambilanObat(tx, s.NotifikasiRepository, pasien.IdPengguna, constant.JenisNotifikasiPengambilanObatBatal, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventPengambilanObat(tx, s.OutboxRepository, constant.EventPengambilanObatBatal, pengambilanObat); err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
@@ -380,22 +516,23 @@ func (s *PengambilanObatService) Diambil(ctx context.Context, request *model.Pen
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengambilanObat := new(entity.PengambilanObat)
 	if request.IdAdminApotek > 0 {
-		if err := s.PengambilanObatRepository.FindByIdAndIdAdminApotekAndStatus(tx, pengambilanObat, request.ID, request.IdAdminApotek, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndIdAdminApotekAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, request.IdAdminApotek, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	} else {
-		if err := s.PengambilanObatRepository.FindByIdAndStatus(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
+		if err := s.PengambilanObatRepository.FindByIdAndStatusAndLockForUpdate(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
 			slog.Error(err.Error())
 			return fiber.ErrNotFound
 		}
 	}
 
+	sebelum := *pengambilanObat
 	pengambilanObat.Status = constant.StatusPengambilanObatDiambil
 
 	if err := s.PengambilanObatRepository.Update(tx, pengambilanObat); err != nil {
@@ -403,6 +540,16 @@ func (s *PengambilanObatService) Diambil(ctx context.Context, request *model.Pen
 		return fiber.ErrInternalServerError
 	}
 
+	if err := catatAudit(tx, s.AuditRepository, request.Aktor, constant.AksiAuditDiambil, &sebelum, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := eventPengambilanObat(tx, s.OutboxRepository, constant.EventPengambilanObatDiambil, pengambilanObat); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
@@ -410,3 +557,338 @@ func (s *PengambilanObatService) Diambil(ctx context.Context, request *model.Pen
 
 	return nil
 }
+
+func (s *PengambilanObatService) GetByResi(ctx context.Context, request *model.PengambilanObatResiRequest) (*model.PengambilanObatResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	if !s.ResiAdapter.Valid(request.Resi) {
+		return nil, model.NewAppError(constant.KodeErrorResiTidakValid)
+	}
+
+	pengambilanObat := new(entity.PengambilanObat)
+	if request.IdAdminApotek > 0 {
+		if err := s.PengambilanObatRepository.FindByResiAndIdAdminApotek(tx, pengambilanObat, request.Resi, request.IdAdminApotek); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	} else if err := s.PengambilanObatRepository.FindByResi(tx, pengambilanObat, request.Resi); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := pengambilanObatResponse(*pengambilanObat)
+	return &response, nil
+}
+
+func (s *PengambilanObatService) QrCode(ctx context.Context, request *model.PengambilanObatQrCodeRequest) (*model.PengambilanObatQrCodeResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pengambilanObat := new(entity.PengambilanObat)
+	if request.IdPengguna > 0 {
+		if err := s.PengambilanObatRepository.FindByIdAndIdPenggunaAndStatus(tx, pengambilanObat, request.ID, request.IdPengguna, constant.StatusPengambilanObatMenunggu); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+	} else if err := s.PengambilanObatRepository.FindByIdAndStatus(tx, pengambilanObat, request.ID, constant.StatusPengambilanObatMenunggu); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := new(model.PengambilanObatQrCodeResponse)
+	if request.Format == "svg" {
+		data, err := s.QrCodeAdapter.SVG(pengambilanObat.Resi)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		response.ContentType = "image/svg+xml"
+		response.Data = data
+	} else {
+		data, err := s.QrCodeAdapter.PNG(pengambilanObat.Resi, 256)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		response.ContentType = "image/png"
+		response.Data = data
+	}
+	return response, nil
+}
+
+func (s *PengambilanObatService) reserveObat(tx *gorm.DB, perubahan map[int32]int32) (map[int32]entity.Obat, error) {
+	// kunci obat berurutan agar tidak terjadi deadlock antar transaksi
+	idObat := slices.Sorted(maps.Keys(perubahan))
+
+	obat := make(map[int32]entity.Obat, len(idObat))
+	for _, id := range idObat {
+		o := new(entity.Obat)
+		if err := s.ObatRepository.FindByIdAndLockForUpdate(tx, o, id); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrNotFound
+		}
+		obat[id] = *o
+	}
+
+	kedaluwarsa, err := s.BatchObatRepository.SumSisaKedaluwarsaByIdObat(tx, idObat, time.Now().Unix())
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for _, id := range idObat {
+		o := obat[id]
+		if perubahan[id] != 0 {
+			o.Jumlah += perubahan[id]
+			if perubahan[id] < 0 && o.Jumlah < kedaluwarsa[id] {
+				return nil, model.NewAppError(constant.KodeErrorStokTidakCukup)
+			}
+			if err := s.ObatRepository.Update(tx, &o); err != nil {
+				slog.Error(err.Error())
+				return nil, fiber.ErrInternalServerError
+			}
+		}
+		obat[id] = o
+	}
+	return obat, nil
+}
+
+func (s *PengambilanObatService) alokasiBatch(tx *gorm.DB, item []entity.PengambilanObatItem) error {
+	sekarang := time.Now().Unix()
+	for _, i := range item {
+		batchObat := new([]entity.BatchObat)
+		if err := s.BatchObatRepository.FindAllTersediaByIdObatAndLockForUpdate(tx, batchObat, i.IdObat, sekarang); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+
+		// ambil dari batch yang paling awal kedaluwarsa terlebih dahulu
+		kebutuhan := i.Jumlah
+		for _, b := range *batchObat {
+			if kebutuhan == 0 {
+				break
+			}
+			jumlah := min(b.Sisa, kebutuhan)
+			b.Sisa -= jumlah
+			kebutuhan -= jumlah
+			if err := s.BatchObatRepository.Update(tx, &b); err != nil {
+				slog.Error(err.Error())
+				return fiber.ErrInternalServerError
+			}
+			alokasi := &entity.AlokasiBatchObat{
+				IdPengambilanObatItem: i.ID,
+				IdBatchObat:           b.ID,
+				Jumlah:                jumlah,
+			}
+			if err := s.AlokasiBatchObatRepository.Create(tx, alokasi); err != nil {
+				slog.Error(err.Error())
+				return fiber.ErrInternalServerError
+			}
+		}
+		if kebutuhan > 0 {
+			return model.NewAppError(constant.KodeErrorStokBatchTidakCukup)
+		}
+	}
+	return nil
+}
+
+func (s *PengambilanObatService) kembalikanBatch(tx *gorm.DB, item []entity.PengambilanObatItem) error {
+	if len(item) == 0 {
+		return nil
+	}
+
+	alokasi := new([]entity.AlokasiBatchObat)
+	if err := s.AlokasiBatchObatRepository.FindAllByIdPengambilanObatItem(tx, alokasi, idPengambilanObatItem(item)); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	dialokasikan := make(map[int32]int32)
+	for _, a := range *alokasi {
+		batchObat := new(entity.BatchObat)
+		if err := s.BatchObatRepository.FindByIdAndLockForUpdate(tx, batchObat, a.IdBatchObat); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+		batchObat.Sisa += a.Jumlah
+		if err := s.BatchObatRepository.Update(tx, batchObat); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+		dialokasikan[a.IdPengambilanObatItem] += a.Jumlah
+	}
+
+	if err := s.AlokasiBatchObatRepository.DeleteByIdPengambilanObatItem(tx, idPengambilanObatItem(item)); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	// pengambilan obat sebelum pencatatan batch tidak memiliki alokasi
+	for _, i := range item {
+		sisa := i.Jumlah - dialokasikan[i.ID]
+		if sisa <= 0 {
+			continue
+		}
+		batchObat := new(entity.BatchObat)
+		if err := s.BatchObatRepository.FindByIdObatAndNoBatchAndLockForUpdate(tx, batchObat, i.IdObat, constant.NoBatchTanpaNomor); err != nil {
+			batchObat.IdObat = i.IdObat
+			batchObat.NoBatch = constant.NoBatchTanpaNomor
+			batchObat.JumlahDiterima = sisa
+			batchObat.Sisa = sisa
+			batchObat.TanggalDiterima = time.Now().Unix()
+			if err := s.BatchObatRepository.Create(tx, batchObat); err != nil {
+				slog.Error(err.Error())
+				return fiber.ErrInternalServerError
+			}
+			continue
+		}
+		batchObat.Sisa += sisa
+		if err := s.BatchObatRepository.Update(tx, batchObat); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+	}
+	return nil
+}
+
+func (s *PengambilanObatService) catatMutasi(tx *gorm.DB, obat map[int32]entity.Obat, perubahan map[int32]int32, aktor *model.Auth, idPengambilanObat int32) error {
+	for _, id := range slices.Sorted(maps.Keys(perubahan)) {
+		jumlah := perubahan[id]
+		if jumlah == 0 {
+			continue
+		}
+		alasan := constant.AlasanMutasiObatReservasi
+		if jumlah > 0 {
+			alasan = constant.AlasanMutasiObatPembatalan
+		}
+		o := obat[id]
+		if err := s.MutasiObatRepository.Create(tx, mutasiObat(&o, jumlah, alasan, aktor, &idPengambilanObat)); err != nil {
+			slog.Error(err.Error())
+			return fiber.ErrInternalServerError
+		}
+		if err := catatPeringatanStok(tx, s.PeringatanStokObatRepository, &o, jumlah); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func idAdminApotekObat(obat map[int32]entity.Obat) (int32, error) {
+	var idAdminApotek int32
+	for _, o := range obat {
+		if idAdminApotek != 0 && o.IdAdminApotek != idAdminApotek {
+			return 0, model.NewAppError(constant.KodeErrorResiBedaApotek)
+		}
+		idAdminApotek = o.IdAdminApotek
+	}
+	return idAdminApotek, nil
+}
+
+func pengambilanObatItem(request []model.PengambilanObatItemRequest) []entity.PengambilanObatItem {
+	item := make([]entity.PengambilanObatItem, 0, len(request))
+	for _, r := range request {
+		item = append(item, entity.PengambilanObatItem{
+			IdObat: r.IdObat,
+			Jumlah: r.Jumlah,
+		})
+	}
+	return item
+}
+
+func pengambilanObatItemResponse(item []entity.PengambilanObatItem) []model.PengambilanObatItemResponse {
+	response := make([]model.PengambilanObatItemResponse, 0, len(item))
+	for _, i := range item {
+		response = append(response, model.PengambilanObatItemResponse{
+			IdObat:   i.IdObat,
+			NamaObat: i.Obat.NamaObat,
+			Jumlah:   i.Jumlah,
+		})
+	}
+	return response
+}
+
+func pengambilanObatResponse(p entity.PengambilanObat) model.PengambilanObatResponse {
+	return model.PengambilanObatResponse{
+		ID:   p.ID,
+		Resi: p.Resi,
+		PasienResponse: &model.PasienResponse{
+			ID:           p.Pasien.ID,
+			NoRekamMedis: p.Pasien.NoRekamMedis,
+			Pengguna: &model.PenggunaResponse{
+				ID:              p.Pasien.Pengguna.ID,
+				NamaLengkap:     p.Pasien.Pengguna.NamaLengkap,
+				Telepon:         p.Pasien.Pengguna.Telepon,
+				TeleponKeluarga: p.Pasien.Pengguna.TeleponKeluarga,
+				Alamat:          p.Pasien.Pengguna.Alamat,
+			},
+			AdminPuskesmas: &model.AdminPuskesmasResponse{
+				ID:               p.Pasien.AdminPuskesmas.ID,
+				NamaPuskesmas:    p.Pasien.AdminPuskesmas.NamaPuskesmas,
+				Telepon:          p.Pasien.AdminPuskesmas.Telepon,
+				Alamat:           p.Pasien.AdminPuskesmas.Alamat,
+				WaktuOperasional: p.Pasien.AdminPuskesmas.WaktuOperasional,
+			},
+			TanggalDaftar: p.Pasien.TanggalDaftar,
+			Status:        p.Pasien.Status,
+		},
+		AdminApotek: &model.AdminApotekResponse{
+			ID:               p.AdminApotek.ID,
+			NamaApotek:       p.AdminApotek.NamaApotek,
+			Telepon:          p.AdminApotek.Telepon,
+			Alamat:           p.AdminApotek.Alamat,
+			WaktuOperasional: p.AdminApotek.WaktuOperasional,
+		},
+		Obat:               pengambilanObatItemResponse(p.Item),
+		TanggalPengambilan: p.TanggalPengambilan,
+		Status:             p.Status,
+	}
+}
+
+func (s *PengambilanObatService) generateResi(tx *gorm.DB) (string, error) {
+	for i := 0; i < 5; i++ {
+		resi, err := s.ResiAdapter.Generate()
+		if err != nil {
+			slog.Error(err.Error())
+			return "", fiber.ErrInternalServerError
+		}
+		total, err := s.PengambilanObatRepository.CountByResi(tx, resi)
+		if err != nil {
+			slog.Error(err.Error())
+			return "", fiber.ErrInternalServerError
+		}
+		if total == 0 {
+			return resi, nil
+		}
+	}
+	slog.Error("failed to generate unique resi")
+	return "", fiber.ErrInternalServerError
+}
+
+func idPengambilanObatItem(item []entity.PengambilanObatItem) []int32 {
+	id := make([]int32, 0, len(item))
+	for _, i := range item {
+		id = append(id, i.ID)
+	}
+	return id
+}
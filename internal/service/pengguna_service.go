@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,16 +13,19 @@ import (
 	"prb_care_api/internal/entity"
 	"prb_care_api/internal/model"
 	"prb_care_api/internal/repository"
-	"time"
 )
 
 type PenggunaService struct {
-	DB                 *gorm.DB
-	PenggunaRepository *repository.PenggunaRepository
-	PasienRepository   *repository.PasienRepository
-	RecaptchaAdapter   *adapter.Captcha
-	Validator          *validator.Validate
-	Config             *viper.Viper
+	DB                       *gorm.DB
+	PenggunaRepository       *repository.PenggunaRepository
+	PasienRepository         *repository.PasienRepository
+	RecaptchaAdapter         *adapter.Captcha
+	SesiRepository           *repository.SesiRepository
+	RefreshTokenRepository   *repository.RefreshTokenRepository
+	TokenAdapter             *adapter.TokenAdapter
+	PercobaanLoginRepository *repository.PercobaanLoginRepository
+	Validator                *validator.Validate
+	Config                   *viper.Viper
 }
 
 func NewPenggunaService(db *gorm.DB,
@@ -31,18 +33,27 @@ func NewPenggunaService(db *gorm.DB,
 	pasienRepository *repository.PasienRepository,
 	validator *validator.Validate,
 	captchaAdapter *adapter.Captcha,
+	sesiRepository *repository.SesiRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository,
+	tokenAdapter *adapter.TokenAdapter,
+	percobaanLoginRepository *repository.PercobaanLoginRepository,
 	config *viper.Viper) *PenggunaService {
-	return &PenggunaService{db, penggunaRepository, pasienRepository, captchaAdapter, validator, config}
+	return &PenggunaService{db, penggunaRepository, pasienRepository, captchaAdapter, sesiRepository, refreshTokenRepository, tokenAdapter, percobaanLoginRepository, validator, config}
 }
 
-func (s *PenggunaService) List(ctx context.Context) (*[]model.PenggunaResponse, error) {
+func (s *PenggunaService) List(ctx context.Context, request *model.PenggunaListRequest) (*model.PageResponse[model.PenggunaResponse], error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	pengguna := new([]entity.Pengguna)
-	if err := s.PenggunaRepository.FindAll(tx, pengguna); err != nil {
+	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrInternalServerError
+		return nil, validasiError(err)
+	}
+
+	pengguna := new([]entity.Pengguna)
+	result, err := s.PenggunaRepository.Search(tx, pengguna, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
 	}
 
 	var response []model.PenggunaResponse
@@ -61,7 +72,7 @@ func (s *PenggunaService) List(ctx context.Context) (*[]model.PenggunaResponse,
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &response, nil
+	return pageResponse(&request.PageRequest, result, response), nil
 }
 
 func (s *PenggunaService) Get(ctx context.Context, request *model.PenggunaGetRequest) (*model.PenggunaResponse, error) {
@@ -70,7 +81,7 @@ func (s *PenggunaService) Get(ctx context.Context, request *model.PenggunaGetReq
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -101,7 +112,7 @@ func (s *PenggunaService) Create(ctx context.Context, request *model.PenggunaCre
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	total, err := s.PenggunaRepository.CountByUsername(tx, request.Username)
@@ -110,7 +121,7 @@ func (s *PenggunaService) Create(ctx context.Context, request *model.PenggunaCre
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.PenggunaRepository.CountByTelepon(tx, request.Telepon)
@@ -119,7 +130,7 @@ func (s *PenggunaService) Create(ctx context.Context, request *model.PenggunaCre
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
@@ -155,7 +166,7 @@ func (s *PenggunaService) Update(ctx context.Context, request *model.PenggunaUpd
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -170,7 +181,7 @@ func (s *PenggunaService) Update(ctx context.Context, request *model.PenggunaUpd
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && pengguna.Username != request.Username {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.PenggunaRepository.CountByTelepon(tx, request.Telepon)
@@ -179,7 +190,7 @@ func (s *PenggunaService) Update(ctx context.Context, request *model.PenggunaUpd
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 && pengguna.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	var password []byte
@@ -198,6 +209,7 @@ func (s *PenggunaService) Update(ctx context.Context, request *model.PenggunaUpd
 	pengguna.TeleponKeluarga = request.TeleponKeluarga
 	if string(password) != "" {
 		pengguna.Password = string(password)
+		pengguna.VersiToken++
 	}
 
 	if err := s.PenggunaRepository.Update(tx, pengguna); err != nil {
@@ -219,7 +231,7 @@ func (s *PenggunaService) Delete(ctx context.Context, request *model.PenggunaDel
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -229,7 +241,7 @@ func (s *PenggunaService) Delete(ctx context.Context, request *model.PenggunaDel
 	}
 
 	if err := s.PasienRepository.FindByIdPengguna(tx, &entity.Pasien{}, request.ID); err == nil {
-		return fiber.NewError(fiber.StatusConflict, "Pengguna masih terkait dengan data pasien yang ada")
+		return model.NewAppError(constant.KodeErrorPenggunaTerkaitPasien)
 	}
 
 	if err := s.PenggunaRepository.Delete(tx, pengguna); err != nil {
@@ -251,21 +263,16 @@ func (s *PenggunaService) Register(ctx context.Context, request *model.PenggunaR
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
-	}
-
-	captchaRequest := &model.CaptchaRequest{
-		TokenCaptcha: request.TokenCaptcha,
-		Secret:       s.Config.GetString("captcha.secret"),
+		return validasiError(err)
 	}
 
-	ok, err := s.RecaptchaAdapter.Verify(captchaRequest)
+	ok, err := s.RecaptchaAdapter.Verify(constant.RolePengguna, request.TokenCaptcha)
 	if err != nil {
 		slog.Error(err.Error())
 		return fiber.ErrInternalServerError
 	}
 	if !ok {
-		return fiber.ErrBadRequest
+		return model.NewAppError(constant.KodeErrorCaptchaTidakValid)
 	}
 
 	total, err := s.PenggunaRepository.CountByUsername(tx, request.Username)
@@ -274,7 +281,7 @@ func (s *PenggunaService) Register(ctx context.Context, request *model.PenggunaR
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Username sudah digunakan")
+		return model.NewAppError(constant.KodeErrorUsernameDigunakan)
 	}
 
 	total, err = s.PenggunaRepository.CountByTelepon(tx, request.Telepon)
@@ -283,7 +290,7 @@ func (s *PenggunaService) Register(ctx context.Context, request *model.PenggunaR
 		return fiber.ErrInternalServerError
 	}
 	if total > 0 {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
@@ -313,83 +320,56 @@ func (s *PenggunaService) Register(ctx context.Context, request *model.PenggunaR
 	return nil
 }
 
-func (s *PenggunaService) Login(ctx context.Context, request *model.PenggunaLoginRequest) (*model.PenggunaResponse, error) {
+func (s *PenggunaService) Login(ctx context.Context, request *model.PenggunaLoginRequest) (*model.TokenResponse, error) {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
-	captchaRequest := &model.CaptchaRequest{
-		TokenCaptcha: request.TokenCaptcha,
-		Secret:       s.Config.GetString("captcha.secret"),
+	if err := cekPercobaanLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, s.Config, constant.RolePengguna, request.Username, request.Ip); err != nil {
+		return nil, err
 	}
 
-	ok, err := s.RecaptchaAdapter.Verify(captchaRequest)
+	ok, err := s.RecaptchaAdapter.Verify(constant.RolePengguna, request.TokenCaptcha)
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 	if !ok {
-		return nil, fiber.ErrBadRequest
+		return nil, model.NewAppError(constant.KodeErrorCaptchaTidakValid)
 	}
 
 	pengguna := new(entity.Pengguna)
 	if err := s.PenggunaRepository.FindByUsername(tx, pengguna, request.Username); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RolePengguna, request.Username, request.Ip)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(pengguna.Password), []byte(request.Password)); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "Username atau password salah")
-	}
-
-	key := s.Config.GetString("jwt.secret")
-	exp := s.Config.GetInt("jwt.exp")
-	claims := jwt.MapClaims{
-		"sub":  pengguna.ID,
-		"role": constant.RolePengguna,
-		"exp":  time.Now().Add(time.Duration(exp) * time.Hour).Unix(),
+		return nil, gagalLogin(s.DB.WithContext(ctx), s.PercobaanLoginRepository, constant.RolePengguna, request.Username, request.Ip)
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
-	if err != nil {
+	if err := catatPercobaanLogin(tx, s.PercobaanLoginRepository, constant.RolePengguna, request.Username, request.Ip, constant.StatusPercobaanLoginBerhasil); err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 
-	if err := tx.Commit().Error; err != nil {
+	token, err := buatSesi(tx, s.SesiRepository, s.RefreshTokenRepository, s.TokenAdapter, &model.Auth{ID: pengguna.ID, Role: constant.RolePengguna, VersiToken: pengguna.VersiToken})
+	if err != nil {
 		slog.Error(err.Error())
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &model.PenggunaResponse{Token: token}, nil
-}
-
-func (s *PenggunaService) Verify(ctx context.Context, request *model.PenggunaVerifyRequest) error {
-	tx := s.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
-
-	if err := s.Validator.Struct(request); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrBadRequest
-	}
-
-	pengguna := new(entity.Pengguna)
-	if err := s.PenggunaRepository.FindById(tx, pengguna, request.ID); err != nil {
-		slog.Error(err.Error())
-		return fiber.ErrNotFound
-	}
-
 	if err := tx.Commit().Error; err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrInternalServerError
+		return nil, fiber.ErrInternalServerError
 	}
 
-	return nil
+	return token, nil
 }
 
 func (s *PenggunaService) Current(ctx context.Context, request *model.PenggunaGetRequest) (*model.PenggunaResponse, error) {
@@ -398,7 +378,7 @@ func (s *PenggunaService) Current(ctx context.Context, request *model.PenggunaGe
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return nil, fiber.ErrBadRequest
+		return nil, validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -427,7 +407,7 @@ func (s *PenggunaService) CurrentProfileUpdate(ctx context.Context, request *mod
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -443,7 +423,7 @@ func (s *PenggunaService) CurrentProfileUpdate(ctx context.Context, request *mod
 	}
 
 	if total > 0 && pengguna.Telepon != request.Telepon {
-		return fiber.NewError(fiber.StatusConflict, "Telepon sudah digunakan")
+		return model.NewAppError(constant.KodeErrorTeleponDigunakan)
 	}
 
 	pengguna.NamaLengkap = request.NamaLengkap
@@ -470,7 +450,7 @@ func (s *PenggunaService) CurrentPasswordUpdate(ctx context.Context, request *mo
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -481,7 +461,7 @@ func (s *PenggunaService) CurrentPasswordUpdate(ctx context.Context, request *mo
 
 	if err := bcrypt.CompareHashAndPassword([]byte(pengguna.Password), []byte(request.CurrentPassword)); err != nil {
 		slog.Error(err.Error())
-		return fiber.NewError(fiber.StatusUnauthorized, "Password saat ini salah")
+		return model.NewAppError(constant.KodeErrorPasswordSaatIniSalah)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
@@ -490,6 +470,7 @@ func (s *PenggunaService) CurrentPasswordUpdate(ctx context.Context, request *mo
 		return fiber.ErrInternalServerError
 	}
 	pengguna.Password = string(password)
+	pengguna.VersiToken++
 
 	if err := s.PenggunaRepository.Update(tx, pengguna); err != nil {
 		slog.Error(err.Error())
@@ -510,7 +491,7 @@ func (s *PenggunaService) CurrentTokenPerangkatUpdate(ctx context.Context, reque
 
 	if err := s.Validator.Struct(request); err != nil {
 		slog.Error(err.Error())
-		return fiber.ErrBadRequest
+		return validasiError(err)
 	}
 
 	pengguna := new(entity.Pengguna)
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type PengingatService struct {
+	DB                        *gorm.DB
+	KontrolBalikRepository    *repository.KontrolBalikRepository
+	PengambilanObatRepository *repository.PengambilanObatRepository
+	NotifikasiRepository      *repository.NotifikasiRepository
+	Config                    *viper.Viper
+}
+
+func NewPengingatService(
+	db *gorm.DB,
+	kontrolBalikRepository *repository.KontrolBalikRepository,
+	pengambilanObatRepository *repository.PengambilanObatRepository,
+	notifikasiRepository *repository.NotifikasiRepository,
+	config *viper.Viper,
+) *PengingatService {
+	return &PengingatService{db, kontrolBalikRepository, pengambilanObatRepository, notifikasiRepository, config}
+}
+
+// pengingat h-1 dan hari h untuk kontrol balik dan pengambilan obat yang masih menunggu, baru dikirim mulai pukul
+// pengingat.jam. notifikasi diberi kunci per jadwal sehingga job boleh dijalankan berulang kali tanpa pengingat ganda
+func (s *PengingatService) Kirim(ctx context.Context) (int, error) {
+	sekarang := time.Now()
+	if sekarang.Hour() < konfigurasiInt(s.Config, "pengingat.jam", 7) {
+		return 0, nil
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	hariIni, besok := batasHari(sekarang)
+	_, lusa := batasHari(time.Unix(besok, 0))
+
+	jumlah := 0
+	for _, jadwal := range []struct {
+		awal, akhir                             int64
+		jenisKontrolBalik, jenisPengambilanObat string
+	}{
+		{hariIni, besok, constant.JenisNotifikasiKontrolBalikPengingatHariIni, constant.JenisNotifikasiPengambilanObatPengingatHariIni},
+		{besok, lusa, constant.JenisNotifikasiKontrolBalikPengingatH1, constant.JenisNotifikasiPengambilanObatPengingatH1},
+	} {
+		kontrolBalik := new([]entity.KontrolBalik)
+		if err := s.KontrolBalikRepository.FindAllByTanggalKontrolBetweenAndStatus(tx, kontrolBalik, jadwal.awal, jadwal.akhir, constant.StatusKontrolBalikMenunggu); err != nil {
+			return 0, err
+		}
+		for _, k := range *kontrolBalik {
+			if err := notifikasiKontrolBalik(tx, s.NotifikasiRepository, k.Pasien.IdPengguna, jadwal.jenisKontrolBalik, &k); err != nil {
+				return 0, err
+			}
+			jumlah++
+		}
+
+		pengambilanObat := new([]entity.PengambilanObat)
+		if err := s.PengambilanObatRepository.FindAllByTanggalPengambilanBetweenAndStatus(tx, pengambilanObat, jadwal.awal, jadwal.akhir, constant.StatusPengambilanObatMenunggu); err != nil {
+			return 0, err
+		}
+		for _, p := range *pengambilanObat {
+			if err := notifikasiPengambilanObat(tx, s.NotifikasiRepository, p.Pasien.IdPengguna, jadwal.jenisPengambilanObat, &p); err != nil {
+				return 0, err
+			}
+			jumlah++
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, err
+	}
+
+	return jumlah, nil
+}
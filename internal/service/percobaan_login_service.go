@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type PercobaanLoginService struct {
+	DB                       *gorm.DB
+	PercobaanLoginRepository *repository.PercobaanLoginRepository
+	Validator                *validator.Validate
+}
+
+func NewPercobaanLoginService(db *gorm.DB, percobaanLoginRepository *repository.PercobaanLoginRepository, validator *validator.Validate) *PercobaanLoginService {
+	return &PercobaanLoginService{db, percobaanLoginRepository, validator}
+}
+
+func (s *PercobaanLoginService) List(ctx context.Context, request *model.PercobaanLoginListRequest) (*model.PageResponse[model.PercobaanLoginResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	percobaanLogin := new([]entity.PercobaanLogin)
+	result, err := s.PercobaanLoginRepository.Search(tx, percobaanLogin, request.Role, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.PercobaanLoginResponse
+	for _, p := range *percobaanLogin {
+		response = append(response, model.PercobaanLoginResponse{
+			ID:       p.ID,
+			Role:     p.Role,
+			Username: p.Username,
+			Ip:       p.Ip,
+			Status:   p.Status,
+			IdAktor:  p.IdAktor,
+			Waktu:    p.Waktu,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+func (s *PercobaanLoginService) BukaKunci(ctx context.Context, request *model.PercobaanLoginBukaKunciRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	// baris dibuka menjadi batas baru penghitungan gagal untuk username atau ip tersebut
+	percobaanLogin := &entity.PercobaanLogin{
+		Status:  constant.StatusPercobaanLoginDibuka,
+		IdAktor: &request.Aktor.ID,
+		Waktu:   time.Now().Unix(),
+	}
+	if request.Username != "" {
+		percobaanLogin.Role = &request.Role
+		percobaanLogin.Username = &request.Username
+	} else {
+		percobaanLogin.Ip = &request.Ip
+	}
+	if err := s.PercobaanLoginRepository.Create(tx, percobaanLogin); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// dipanggil sebelum captcha dan password diperiksa. setelah gagal login ada jeda yang makin panjang,
+// dan setelah login.maks_gagal kali gagal (atau login.maks_gagal_ip dari satu ip) akun terkunci selama login.kunci menit
+func cekPercobaanLogin(db *gorm.DB, percobaanLoginRepository *repository.PercobaanLoginRepository, config *viper.Viper, role string, username string, ip string) error {
+	sekarang := time.Now().Unix()
+	kunci := int64(konfigurasiInt(config, "login.kunci", 15)) * 60
+	sejak := sekarang - kunci
+
+	gagal, err := percobaanLoginRepository.CountGagalByRoleAndUsername(db, role, username, sejak)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	var tunggu int64
+	if gagal.Jumlah >= int64(konfigurasiInt(config, "login.maks_gagal", 5)) {
+		tunggu = gagal.Terakhir + kunci - sekarang
+	} else if gagal.Jumlah > 0 {
+		tunggu = gagal.Terakhir + min(int64(1)<<min(gagal.Jumlah-1, 6), 60) - sekarang
+	}
+
+	gagalIp, err := percobaanLoginRepository.CountGagalByIp(db, ip, sejak)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if gagalIp.Jumlah >= int64(konfigurasiInt(config, "login.maks_gagal_ip", 20)) {
+		tunggu = max(tunggu, gagalIp.Terakhir+kunci-sekarang)
+	}
+
+	if tunggu > 0 {
+		slog.Warn("Login rejected", "role", role, "username", username, "ip", ip, "tunggu", tunggu)
+		if err := catatPercobaanLogin(db, percobaanLoginRepository, role, username, ip, constant.StatusPercobaanLoginDitolak); err != nil {
+			slog.Error(err.Error())
+		}
+		return model.NewAppError(constant.KodeErrorLoginDibatasi, tunggu)
+	}
+	return nil
+}
+
+func catatPercobaanLogin(db *gorm.DB, percobaanLoginRepository *repository.PercobaanLoginRepository, role string, username string, ip string, status string) error {
+	return percobaanLoginRepository.Create(db, &entity.PercobaanLogin{
+		Role:     &role,
+		Username: &username,
+		Ip:       &ip,
+		Status:   status,
+		Waktu:    time.Now().Unix(),
+	})
+}
+
+func gagalLogin(db *gorm.DB, percobaanLoginRepository *repository.PercobaanLoginRepository, role string, username string, ip string) error {
+	catatGagalLogin(db, percobaanLoginRepository, role, username, ip)
+	return model.NewAppError(constant.KodeErrorLoginSalah)
+}
+
+// dicatat di luar transaksi login agar tetap tersimpan, termasuk kode 2fa yang salah
+func catatGagalLogin(db *gorm.DB, percobaanLoginRepository *repository.PercobaanLoginRepository, role string, username string, ip string) {
+	if err := catatPercobaanLogin(db, percobaanLoginRepository, role, username, ip, constant.StatusPercobaanLoginGagal); err != nil {
+		slog.Error(err.Error())
+	}
+}
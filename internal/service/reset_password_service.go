@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type ResetPasswordService struct {
+	DB                         *gorm.DB
+	PenggunaRepository         *repository.PenggunaRepository
+	OtpResetPasswordRepository *repository.OtpResetPasswordRepository
+	SesiRepository             *repository.SesiRepository
+	RecaptchaAdapter           *adapter.Captcha
+	TokenAdapter               *adapter.TokenAdapter
+	OtpSender                  adapter.OtpSender
+	Validator                  *validator.Validate
+	Config                     *viper.Viper
+}
+
+func NewResetPasswordService(db *gorm.DB,
+	penggunaRepository *repository.PenggunaRepository,
+	otpResetPasswordRepository *repository.OtpResetPasswordRepository,
+	sesiRepository *repository.SesiRepository,
+	captchaAdapter *adapter.Captcha,
+	tokenAdapter *adapter.TokenAdapter,
+	otpSender adapter.OtpSender,
+	validator *validator.Validate,
+	config *viper.Viper) *ResetPasswordService {
+	return &ResetPasswordService{db, penggunaRepository, otpResetPasswordRepository, sesiRepository, captchaAdapter, tokenAdapter, otpSender, validator, config}
+}
+
+// jawaban selalu sama baik telepon terdaftar atau tidak supaya tidak bisa dipakai menebak nomor pengguna
+func (s *ResetPasswordService) LupaPassword(ctx context.Context, request *model.LupaPasswordRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	ok, err := s.RecaptchaAdapter.Verify(constant.RolePengguna, request.TokenCaptcha)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if !ok {
+		return model.NewAppError(constant.KodeErrorCaptchaTidakValid)
+	}
+
+	// baris pengguna dikunci agar permintaan paralel tidak bisa melewati jeda dan batas per jam bersamaan
+	pengguna := new(entity.Pengguna)
+	terdaftar := true
+	if err := s.PenggunaRepository.FindByTeleponAndLockForUpdate(tx, pengguna, request.Telepon); err != nil {
+		slog.Error(err.Error())
+		terdaftar = false
+	}
+
+	// nomor yang tidak terdaftar, masih dalam jeda, atau melewati batas tetap menjalani query dan hash yang sama
+	// supaya lama respons tidak membedakannya
+	kirim := terdaftar
+	sekarang := time.Now().Unix()
+	terakhir := new(entity.OtpResetPassword)
+	if err := s.OtpResetPasswordRepository.FindTerakhirByIdPengguna(tx, terakhir, pengguna.ID); err == nil {
+		if tunggu := terakhir.Dibuat + int64(konfigurasiInt(s.Config, "otp.jeda", 60)) - sekarang; tunggu > 0 {
+			slog.Warn("OTP requested during cooldown", "idPengguna", pengguna.ID, "tunggu", tunggu)
+			kirim = false
+		}
+	}
+
+	total, err := s.OtpResetPasswordRepository.CountByIdPenggunaSejak(tx, pengguna.ID, sekarang-3600)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if total >= int64(konfigurasiInt(s.Config, "otp.maks_kirim", 5)) {
+		slog.Warn("OTP request limit exceeded", "idPengguna", pengguna.ID, "total", total)
+		kirim = false
+	}
+
+	otp, err := s.TokenAdapter.Otp()
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	hashOtp, err := bcrypt.GenerateFromPassword([]byte(otp), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if !kirim {
+		return nil
+	}
+
+	exp := konfigurasiInt(s.Config, "otp.exp", 5)
+	otpResetPassword := &entity.OtpResetPassword{
+		IdPengguna:  pengguna.ID,
+		HashOtp:     string(hashOtp),
+		Dibuat:      sekarang,
+		Kedaluwarsa: sekarang + int64(exp)*60,
+	}
+	if err := s.OtpResetPasswordRepository.Create(tx, otpResetPassword); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	pesan := fmt.Sprintf("Kode OTP reset password PRB Care Anda %s, berlaku %d menit. Jangan berikan kode ini kepada siapa pun.", otp, exp)
+	go s.kirimOtp(pengguna.Telepon, pesan, otpResetPassword)
+
+	return nil
+}
+
+// dikirim di luar request agar lama panggilan gateway tidak terlihat oleh peminta. otp yang gagal dikirim dihapus
+// sehingga tidak menghabiskan jeda dan batas per jam, dan pengguna bisa langsung meminta ulang
+func (s *ResetPasswordService) kirimOtp(telepon string, pesan string, otpResetPassword *entity.OtpResetPassword) {
+	if err := s.OtpSender.Send(telepon, pesan); err != nil {
+		slog.Error(err.Error(), "idPengguna", otpResetPassword.IdPengguna)
+		if err := s.OtpResetPasswordRepository.Delete(s.DB, otpResetPassword); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+}
+
+func (s *ResetPasswordService) VerifikasiOtp(ctx context.Context, request *model.VerifikasiOtpRequest) (*model.VerifikasiOtpResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	otpSalah := model.NewAppError(constant.KodeErrorOtpSalah)
+
+	pengguna := new(entity.Pengguna)
+	if err := s.PenggunaRepository.FindByTelepon(tx, pengguna, request.Telepon); err != nil {
+		slog.Error(err.Error())
+		return nil, otpSalah
+	}
+
+	// hanya otp terakhir yang berlaku, otp sebelumnya otomatis gugur saat otp baru diminta
+	otpResetPassword := new(entity.OtpResetPassword)
+	if err := s.OtpResetPasswordRepository.FindTerakhirByIdPenggunaAndLockForUpdate(tx, otpResetPassword, pengguna.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, otpSalah
+	}
+
+	sekarang := time.Now().Unix()
+	if otpResetPassword.Terverifikasi != nil || otpResetPassword.Kedaluwarsa <= sekarang ||
+		otpResetPassword.Percobaan >= int32(konfigurasiInt(s.Config, "otp.maks_percobaan", 5)) {
+		return nil, otpSalah
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(otpResetPassword.HashOtp), []byte(request.Otp)); err != nil {
+		otpResetPassword.Percobaan++
+		if err := s.OtpResetPasswordRepository.Update(tx, otpResetPassword); err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := tx.Commit().Error; err != nil {
+			slog.Error(err.Error())
+			return nil, fiber.ErrInternalServerError
+		}
+		return nil, otpSalah
+	}
+
+	tokenReset, hash, err := s.TokenAdapter.Acak()
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	otpResetPassword.Terverifikasi = &sekarang
+	otpResetPassword.HashTokenReset = &hash
+	if err := s.OtpResetPasswordRepository.Update(tx, otpResetPassword); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.VerifikasiOtpResponse{TokenReset: tokenReset}, nil
+}
+
+func (s *ResetPasswordService) ResetPassword(ctx context.Context, request *model.ResetPasswordRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	tokenSalah := model.NewAppError(constant.KodeErrorTokenResetTidakValid)
+
+	otpResetPassword := new(entity.OtpResetPassword)
+	if err := s.OtpResetPasswordRepository.FindByHashTokenResetAndLockForUpdate(tx, otpResetPassword, s.TokenAdapter.Hash(request.TokenReset)); err != nil {
+		slog.Error(err.Error())
+		return tokenSalah
+	}
+
+	sekarang := time.Now().Unix()
+	if otpResetPassword.Digunakan != nil || otpResetPassword.Terverifikasi == nil ||
+		*otpResetPassword.Terverifikasi+int64(konfigurasiInt(s.Config, "otp.token_reset_exp", 10))*60 <= sekarang {
+		return tokenSalah
+	}
+
+	pengguna := new(entity.Pengguna)
+	if err := s.PenggunaRepository.FindById(tx, pengguna, otpResetPassword.IdPengguna); err != nil {
+		slog.Error(err.Error())
+		return tokenSalah
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	pengguna.Password = string(password)
+	// semua sesi yang masih terbuka ikut ditutup setelah password direset
+	pengguna.VersiToken++
+	if err := s.PenggunaRepository.Update(tx, pengguna); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+	if err := s.SesiRepository.RevokeByIdAkunAndRole(tx, pengguna.ID, constant.RolePengguna, sekarang); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	otpResetPassword.Digunakan = &sekarang
+	if err := s.OtpResetPasswordRepository.Update(tx, otpResetPassword); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
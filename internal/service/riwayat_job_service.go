@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+)
+
+type RiwayatJobService struct {
+	DB                   *gorm.DB
+	RiwayatJobRepository *repository.RiwayatJobRepository
+	Validator            *validator.Validate
+}
+
+func NewRiwayatJobService(db *gorm.DB, riwayatJobRepository *repository.RiwayatJobRepository, validator *validator.Validate) *RiwayatJobService {
+	return &RiwayatJobService{db, riwayatJobRepository, validator}
+}
+
+func (s *RiwayatJobService) List(ctx context.Context, request *model.RiwayatJobListRequest) (*model.PageResponse[model.RiwayatJobResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	riwayatJob := new([]entity.RiwayatJob)
+	result, err := s.RiwayatJobRepository.Search(tx, riwayatJob, request.Nama, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.RiwayatJobResponse
+	for _, r := range *riwayatJob {
+		response = append(response, model.RiwayatJobResponse{
+			ID:      r.ID,
+			Nama:    r.Nama,
+			Mulai:   r.Mulai,
+			Selesai: r.Selesai,
+			Status:  r.Status,
+			Jumlah:  r.Jumlah,
+			Error:   r.Error,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
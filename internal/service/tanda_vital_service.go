@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"math"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"slices"
+	"strconv"
+)
+
+type TandaVitalService struct {
+	DB                     *gorm.DB
+	PasienRepository       *repository.PasienRepository
+	KontrolBalikRepository *repository.KontrolBalikRepository
+	Validator              *validator.Validate
+	Config                 *viper.Viper
+}
+
+func NewTandaVitalService(
+	db *gorm.DB,
+	pasienRepository *repository.PasienRepository,
+	kontrolBalikRepository *repository.KontrolBalikRepository,
+	validator *validator.Validate,
+	config *viper.Viper,
+) *TandaVitalService {
+	return &TandaVitalService{db, pasienRepository, kontrolBalikRepository, validator, config}
+}
+
+func (s *TandaVitalService) Get(ctx context.Context, request *model.TandaVitalRequest) (*model.TandaVitalResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pasien := new(entity.Pasien)
+	if err := findPasienMilik(tx, s.PasienRepository, pasien, request.ID, request.IdPengguna, request.IdAdminPuskesmas); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	tanggalAkhir := int64(math.MaxInt64)
+	if request.TanggalSelesai > 0 {
+		tanggalAkhir = request.TanggalSelesai
+	}
+	kontrolBalik := new([]entity.KontrolBalik)
+	if err := s.KontrolBalikRepository.FindAllByIdPasienAndTanggalKontrolBetweenAndStatusOrStatus(tx, kontrolBalik, pasien.ID, request.TanggalMulai, tanggalAkhir, constant.StatusKontrolBalikMenunggu, constant.StatusKontrolBalikSelesai); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	ambang := ambangTandaVital(s.Config)
+	response := &model.TandaVitalResponse{
+		IdPasien: pasien.ID,
+		Ambang:   ambang,
+		Seri:     []model.TitikTandaVitalResponse{},
+	}
+	for _, k := range *kontrolBalik {
+		// kontrol balik yang belum diperiksa tidak memiliki tanda vital
+		if k.BeratBadan == 0 && k.TinggiBadan == 0 && k.TekananDarahSistolik == 0 && k.DenyutNadi == 0 {
+			continue
+		}
+		titik := model.TitikTandaVitalResponse{
+			IdKontrolBalik:        k.ID,
+			TanggalKontrol:        k.TanggalKontrol,
+			BeratBadan:            k.BeratBadan,
+			TinggiBadan:           k.TinggiBadan,
+			Imt:                   imt(k.BeratBadan, k.TinggiBadan),
+			TekananDarahSistolik:  k.TekananDarahSistolik,
+			TekananDarahDiastolik: k.TekananDarahDiastolik,
+			DenyutNadi:            k.DenyutNadi,
+		}
+		titik.Peringatan = peringatanTandaVital(&titik, &ambang)
+		response.Seri = append(response.Seri, titik)
+	}
+
+	response.Ringkasan = ringkasanTandaVital(response.Seri, konfigurasiInt(s.Config, "tanda_vital.tidak_terkontrol_berturut", 2))
+	return response, nil
+}
+
+func ambangTandaVital(config *viper.Viper) model.AmbangTandaVitalResponse {
+	return model.AmbangTandaVitalResponse{
+		SistolikMin:    konfigurasiInt(config, "tanda_vital.sistolik_min", 90),
+		SistolikMaks:   konfigurasiInt(config, "tanda_vital.sistolik_maks", 140),
+		DiastolikMin:   konfigurasiInt(config, "tanda_vital.diastolik_min", 60),
+		DiastolikMaks:  konfigurasiInt(config, "tanda_vital.diastolik_maks", 90),
+		DenyutNadiMin:  konfigurasiInt(config, "tanda_vital.denyut_nadi_min", 60),
+		DenyutNadiMaks: konfigurasiInt(config, "tanda_vital.denyut_nadi_maks", 100),
+		ImtMin:         konfigurasiFloat(config, "tanda_vital.imt_min", 18.5),
+		ImtMaks:        konfigurasiFloat(config, "tanda_vital.imt_maks", 25),
+	}
+}
+
+// batas maks tekanan darah inklusif mengikuti kriteria hipertensi >= 140/90, batas lain eksklusif
+func peringatanTandaVital(titik *model.TitikTandaVitalResponse, ambang *model.AmbangTandaVitalResponse) []string {
+	peringatan := []string{}
+	if titik.TekananDarahSistolik > 0 && titik.TekananDarahDiastolik > 0 {
+		sistolik, diastolik := int(titik.TekananDarahSistolik), int(titik.TekananDarahDiastolik)
+		if sistolik >= ambang.SistolikMaks || diastolik >= ambang.DiastolikMaks {
+			peringatan = append(peringatan, constant.PeringatanTekananDarahTinggi)
+		} else if sistolik < ambang.SistolikMin || diastolik < ambang.DiastolikMin {
+			peringatan = append(peringatan, constant.PeringatanTekananDarahRendah)
+		}
+	}
+	if titik.DenyutNadi > 0 {
+		if int(titik.DenyutNadi) > ambang.DenyutNadiMaks {
+			peringatan = append(peringatan, constant.PeringatanDenyutNadiTinggi)
+		} else if int(titik.DenyutNadi) < ambang.DenyutNadiMin {
+			peringatan = append(peringatan, constant.PeringatanDenyutNadiRendah)
+		}
+	}
+	if titik.Imt > 0 {
+		if titik.Imt > ambang.ImtMaks {
+			peringatan = append(peringatan, constant.PeringatanImtTinggi)
+		} else if titik.Imt < ambang.ImtMin {
+			peringatan = append(peringatan, constant.PeringatanImtRendah)
+		}
+	}
+	return peringatan
+}
+
+// tekanan darah dianggap tidak terkontrol bila beberapa pengukuran terakhir berturut-turut tinggi
+func ringkasanTandaVital(seri []model.TitikTandaVitalResponse, berturut int) model.RingkasanTandaVitalResponse {
+	ringkasan := model.RingkasanTandaVitalResponse{Jumlah: len(seri)}
+	if len(seri) > 0 {
+		ringkasan.Terakhir = &seri[len(seri)-1]
+	}
+
+	tinggiBerturut := 0
+	for _, titik := range seri {
+		if titik.TekananDarahSistolik == 0 {
+			continue
+		}
+		if slices.Contains(titik.Peringatan, constant.PeringatanTekananDarahTinggi) {
+			ringkasan.JumlahTekananDarahTinggi++
+			tinggiBerturut++
+		} else {
+			tinggiBerturut = 0
+		}
+	}
+	ringkasan.TekananDarahTidakTerkontrol = tinggiBerturut >= berturut
+	return ringkasan
+}
+
+// indeks massa tubuh dari berat dalam kg dan tinggi dalam cm, dibulatkan satu desimal
+func imt(beratBadan int32, tinggiBadan int32) float64 {
+	if beratBadan <= 0 || tinggiBadan <= 0 {
+		return 0
+	}
+	tinggi := float64(tinggiBadan) / 100
+	return math.Round(float64(beratBadan)/(tinggi*tinggi)*10) / 10
+}
+
+func tekananDarah(sistolik int32, diastolik int32) string {
+	if sistolik <= 0 || diastolik <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", sistolik, diastolik)
+}
+
+// kebalikan tekananDarah, teks yang tidak sesuai format menghasilkan 0/0 dan ditolak validator
+func uraiTekananDarah(teks string) (int32, int32) {
+	bagian := constant.PolaTekananDarah.FindStringSubmatch(teks)
+	if bagian == nil {
+		return 0, 0
+	}
+	sistolik, _ := strconv.Atoi(bagian[1])
+	diastolik, _ := strconv.Atoi(bagian[2])
+	return int32(sistolik), int32(diastolik)
+}
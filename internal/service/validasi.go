@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"prb_care_api/internal/model"
+)
+
+// kesalahan validator diteruskan apa adanya agar ErrorHandler bisa menampilkan field yang tidak valid
+func validasiError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return &model.ValidationError{Errors: validationErrors}
+	}
+	return fiber.ErrBadRequest
+}
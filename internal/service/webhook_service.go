@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"log/slog"
+	"prb_care_api/internal/adapter"
+	"prb_care_api/internal/constant"
+	"prb_care_api/internal/entity"
+	"prb_care_api/internal/model"
+	"prb_care_api/internal/repository"
+	"time"
+)
+
+type WebhookService struct {
+	DB                          *gorm.DB
+	WebhookRepository           *repository.WebhookRepository
+	WebhookEventRepository      *repository.WebhookEventRepository
+	PengirimanWebhookRepository *repository.PengirimanWebhookRepository
+	WebhookAdapter              *adapter.WebhookAdapter
+	Validator                   *validator.Validate
+	Config                      *viper.Viper
+}
+
+func NewWebhookService(
+	db *gorm.DB,
+	webhookRepository *repository.WebhookRepository,
+	webhookEventRepository *repository.WebhookEventRepository,
+	pengirimanWebhookRepository *repository.PengirimanWebhookRepository,
+	webhookAdapter *adapter.WebhookAdapter,
+	validator *validator.Validate,
+	config *viper.Viper,
+) *WebhookService {
+	return &WebhookService{db, webhookRepository, webhookEventRepository, pengirimanWebhookRepository, webhookAdapter, validator, config}
+}
+
+func (s *WebhookService) List(ctx context.Context, request *model.WebhookListRequest) (*model.PageResponse[model.WebhookResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	webhook := new([]entity.Webhook)
+	result, err := s.WebhookRepository.Search(tx, webhook, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.WebhookResponse
+	for _, w := range *webhook {
+		response = append(response, *webhookResponse(&w, false))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+func (s *WebhookService) Get(ctx context.Context, request *model.WebhookGetRequest) (*model.WebhookResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	webhook := new(entity.Webhook)
+	if err := s.WebhookRepository.FindById(tx, webhook, request.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return webhookResponse(webhook, true), nil
+}
+
+func (s *WebhookService) Create(ctx context.Context, request *model.WebhookCreateRequest) (*model.WebhookResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	secret, err := s.WebhookAdapter.Secret()
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	webhook := new(entity.Webhook)
+	webhook.Nama = request.Nama
+	webhook.Url = request.Url
+	webhook.Secret = secret
+	webhook.Aktif = true
+	webhook.Event = webhookEvent(request.Event)
+	webhook.Dibuat = time.Now().Unix()
+
+	if err := s.WebhookRepository.Create(tx, webhook); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return webhookResponse(webhook, true), nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, request *model.WebhookUpdateRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	webhook := new(entity.Webhook)
+	if err := s.WebhookRepository.FindByIdAndLockForUpdate(tx, webhook, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	if err := s.WebhookEventRepository.DeleteByIdWebhook(tx, webhook.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	webhook.Nama = request.Nama
+	webhook.Url = request.Url
+	webhook.Aktif = request.Aktif
+	webhook.Event = webhookEvent(request.Event)
+
+	if err := s.WebhookRepository.Update(tx, webhook); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, request *model.WebhookDeleteRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	webhook := new(entity.Webhook)
+	if err := s.WebhookRepository.FindByIdAndLockForUpdate(tx, webhook, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	if err := s.WebhookRepository.Delete(tx, webhook); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// secret lama langsung tidak berlaku, termasuk untuk pengiriman yang masih menunggu di antrean
+func (s *WebhookService) RotasiSecret(ctx context.Context, request *model.WebhookRotasiSecretRequest) (*model.WebhookResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	webhook := new(entity.Webhook)
+	if err := s.WebhookRepository.FindByIdAndLockForUpdate(tx, webhook, request.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrNotFound
+	}
+
+	secret, err := s.WebhookAdapter.Secret()
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+	webhook.Secret = secret
+
+	if err := s.WebhookRepository.Update(tx, webhook); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := s.WebhookRepository.FindById(tx, webhook, webhook.ID); err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return webhookResponse(webhook, true), nil
+}
+
+func (s *WebhookService) ListPengiriman(ctx context.Context, request *model.PengirimanWebhookListRequest) (*model.PageResponse[model.PengirimanWebhookResponse], error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return nil, validasiError(err)
+	}
+
+	pengiriman := new([]entity.PengirimanWebhook)
+	result, err := s.PengirimanWebhookRepository.Search(tx, pengiriman, request.IdWebhook, request.Jenis, request.Status, pageQuery(&request.PageRequest))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	var response []model.PengirimanWebhookResponse
+	for _, p := range *pengiriman {
+		response = append(response, model.PengirimanWebhookResponse{
+			ID:              p.ID,
+			IdWebhook:       p.IdWebhook,
+			IdEvent:         p.IdEvent,
+			Jenis:           p.Jenis,
+			Payload:         json.RawMessage(p.Payload),
+			Status:          p.Status,
+			Percobaan:       p.Percobaan,
+			StatusHttp:      p.StatusHttp,
+			Error:           p.Error,
+			Dibuat:          p.Dibuat,
+			KirimBerikutnya: p.KirimBerikutnya,
+			Terkirim:        p.Terkirim,
+		})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return pageResponse(&request.PageRequest, result, response), nil
+}
+
+// pengiriman yang sudah terkirim pun boleh dikirim ulang, misalnya bila penerima kehilangan datanya.
+// id event tetap sama sehingga penerima tetap bisa membuang yang ganda
+func (s *WebhookService) KirimUlang(ctx context.Context, request *model.PengirimanWebhookKirimUlangRequest) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error(err.Error())
+		return validasiError(err)
+	}
+
+	pengiriman := new(entity.PengirimanWebhook)
+	if err := s.PengirimanWebhookRepository.FindByIdAndLockForUpdate(tx, pengiriman, request.ID); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrNotFound
+	}
+
+	pengiriman.Status = constant.StatusPengirimanWebhookMenunggu
+	pengiriman.Percobaan = 0
+	pengiriman.KirimBerikutnya = time.Now().Unix()
+	if err := s.PengirimanWebhookRepository.Update(tx, pengiriman); err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *WebhookService) Nama() string {
+	return "webhook langganan"
+}
+
+// dipanggil EventService untuk setiap event outbox, event hanya diantrekan ke webhook aktif yang berlangganan
+// jenisnya lalu dikirim oleh Jalankan
+func (s *WebhookService) Kirim(event *adapter.Event) error {
+	tx := s.DB.Begin()
+	defer tx.Rollback()
+
+	webhook := new([]entity.Webhook)
+	if err := s.WebhookRepository.FindAllByAktifAndEvent(tx, webhook, true, event.Jenis); err != nil {
+		return err
+	}
+	if len(*webhook) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	sekarang := time.Now().Unix()
+	for _, w := range *webhook {
+		if err := s.PengirimanWebhookRepository.CreateOrIgnoreByIdWebhookAndIdEvent(tx, &entity.PengirimanWebhook{
+			IdWebhook:       w.ID,
+			IdEvent:         event.ID,
+			Jenis:           event.Jenis,
+			Payload:         string(payload),
+			Status:          constant.StatusPengirimanWebhookMenunggu,
+			Dibuat:          sekarang,
+			KirimBerikutnya: sekarang,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// memproses antrean pengiriman setiap webhook.interval detik sampai ctx selesai
+func (s *WebhookService) Jalankan(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(konfigurasiInt(s.Config, "webhook.interval", 5)) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := s.kirimAntrean(ctx); err != nil {
+			slog.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sama seperti antrean notifikasi, pengiriman diklaim dulu lalu dikirim di luar transaksi
+func (s *WebhookService) kirimAntrean(ctx context.Context) error {
+	return klaimLaluKirim(ctx, s.DB, s.PengirimanWebhookRepository, 50, func(p *entity.PengirimanWebhook) *int64 { return &p.KirimBerikutnya }, s.kirim)
+}
+
+func (s *WebhookService) kirim(ctx context.Context, pengiriman *entity.PengirimanWebhook) error {
+	db := s.DB.WithContext(ctx)
+
+	// url dan secret dibaca saat pengiriman agar perubahan dan rotasi secret langsung berlaku
+	webhook := new(entity.Webhook)
+	if err := s.WebhookRepository.FindById(db, webhook, pengiriman.IdWebhook); err != nil {
+		return err
+	}
+
+	var statusHttp int
+	var err error
+	if !webhook.Aktif {
+		err = errors.New("webhook nonaktif")
+	} else {
+		timeout := time.Duration(konfigurasiInt(s.Config, "webhook.timeout", 10)) * time.Second
+		statusHttp, err = s.WebhookAdapter.Kirim(webhook.Url, webhook.Secret, pengiriman.IdEvent, pengiriman.Jenis, []byte(pengiriman.Payload), timeout)
+	}
+
+	pengiriman.StatusHttp = nil
+	if statusHttp != 0 {
+		status := int32(statusHttp)
+		pengiriman.StatusHttp = &status
+	}
+	percobaan := &percobaanKirim{&pengiriman.Status, &pengiriman.Percobaan, &pengiriman.Error, &pengiriman.KirimBerikutnya, &pengiriman.Terkirim, constant.StatusPengirimanWebhookTerkirim, constant.StatusPengirimanWebhookGagal}
+	percobaan.catat(s.Config, "webhook", err, !webhook.Aktif)
+	return s.PengirimanWebhookRepository.Update(db, pengiriman)
+}
+
+func webhookEvent(jenis []string) []entity.WebhookEvent {
+	event := make([]entity.WebhookEvent, 0, len(jenis))
+	for _, j := range jenis {
+		event = append(event, entity.WebhookEvent{Jenis: j})
+	}
+	return event
+}
+
+// secret hanya ditampilkan pada detail webhook, tidak pada daftar
+func webhookResponse(webhook *entity.Webhook, denganSecret bool) *model.WebhookResponse {
+	response := &model.WebhookResponse{
+		ID:     webhook.ID,
+		Nama:   webhook.Nama,
+		Url:    webhook.Url,
+		Event:  make([]string, 0, len(webhook.Event)),
+		Aktif:  webhook.Aktif,
+		Dibuat: webhook.Dibuat,
+	}
+	if denganSecret {
+		response.Secret = webhook.Secret
+	}
+	for _, e := range webhook.Event {
+		response.Event = append(response.Event, e.Jenis)
+	}
+	return response
+}